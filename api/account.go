@@ -2,33 +2,45 @@ package api
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+var ErrAccountExists = util.NewCustomError(util.KindAlreadyExists, "ErrAccountExists", "an account of this type and currency already exists")
+
 type CreateAccountRequest struct {
 	Currency string `json:"currency" binding:"currency"`
+	Type     string `json:"type" binding:"omitempty,oneof=checking savings"`
 }
 
 func (s *Server) createAccount(ctx *gin.Context) {
 	var req CreateAccountRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		renderError(ctx, validationError(err))
 		return
 	}
 
-	authUser := Auth(ctx)
+	authUser, ok := Auth(ctx)
+
+	if !ok {
+		return
+	}
+
+	if req.Type == "" {
+		req.Type = util.CheckingAccount
+	}
 
 	arg := db.CreateAccountParams{
 		OwnerID:  authUser.UserId,
 		Currency: req.Currency,
+		Type:     req.Type,
 		Balance:  0,
 	}
 
@@ -38,15 +50,15 @@ func (s *Server) createAccount(ctx *gin.Context) {
 		if err, ok := err.(*pgconn.PgError); ok {
 			switch err.ConstraintName {
 			case "fk_accounts_users":
-				ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("user with ID %d not found", arg.OwnerID)))
+				renderError(ctx, util.ErrUserNotFound)
 				return
-			case "unique_owner_currency":
-				ctx.JSON(http.StatusConflict, errorResponse(fmt.Errorf("account already exists for user ID %d with currency %s", arg.OwnerID, arg.Currency)))
+			case "unique_owner_currency_type":
+				renderError(ctx, ErrAccountExists)
 				return
 			}
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		renderError(ctx, err)
 		return
 	}
 
@@ -61,7 +73,7 @@ func (s *Server) getAccountByID(ctx *gin.Context) {
 	var req GetAccountByIdRequest
 
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		renderError(ctx, validationError(err))
 		return
 	}
 
@@ -69,17 +81,22 @@ func (s *Server) getAccountByID(ctx *gin.Context) {
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("account with id '%v' not found", req.ID)))
+			renderError(ctx, db.ErrAccountNotFound)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		renderError(ctx, err)
+		return
+	}
+
+	authUser, ok := Auth(ctx)
+
+	if !ok {
 		return
 	}
 
-	authUser := Auth(ctx)
 	if account.OwnerID != authUser.UserId {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("user not authorized")))
+		renderError(ctx, util.ErrNotAuthorized)
 		return
 	}
 
@@ -95,11 +112,15 @@ func (s *Server) getAccountList(ctx *gin.Context) {
 	var req GetAccountList
 
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		renderError(ctx, validationError(err))
 		return
 	}
 
-	authUser := Auth(ctx)
+	authUser, ok := Auth(ctx)
+
+	if !ok {
+		return
+	}
 
 	arg := db.GetAccountsParams{
 		Offset: int64((req.Page - 1) * req.PerPage),
@@ -114,7 +135,7 @@ func (s *Server) getAccountList(ctx *gin.Context) {
 
 	if err != nil {
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		renderError(ctx, err)
 		return
 	}
 
@@ -2,46 +2,53 @@ package api
 
 import (
 	"errors"
-	"fmt"
-	"log"
-	"net/http"
 	"strings"
+	"time"
 
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
 	"github.com/devphasex/cedar-bank-api/token"
+	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const (
 	authorizationHeaderKey  = "authorization"
 	authorizationPayload    = "auth_payload"
+	authorizationUser       = "auth_user"
 	authorizationTypeBearer = "bearer"
 )
 
-func AuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
+var (
+	ErrAuthorizationMissing = util.NewCustomError(util.KindUnauthenticated, "ErrAuthorizationMissing", "authorization token not set in header")
+	ErrAuthorizationFormat  = util.NewCustomError(util.KindUnauthenticated, "ErrAuthorizationFormat", "invalid authorization header format")
+	ErrAuthorizationType    = util.NewCustomError(util.KindUnauthenticated, "ErrAuthorizationType", "unsupported authorization type")
+	ErrTokenRevoked         = util.NewCustomError(util.KindUnauthenticated, "ErrTokenRevoked", "token issued before the last password change, sign in again")
+)
+
+// AuthMiddleware authenticates the bearer token and loads its user, which
+// later handlers read with AuthUser. Tokens issued before the user's last
+// password change are rejected.
+func AuthMiddleware(tokenMaker token.Maker, store db.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authorizationHeader := ctx.GetHeader(authorizationHeaderKey)
 		if len(authorizationHeader) == 0 {
-			err := errors.New("authorization token not set in header")
-
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			renderError(ctx, ErrAuthorizationMissing)
 			return
 		}
 
 		fields := strings.Fields(authorizationHeader)
 
 		if len(fields) < 2 {
-			err := errors.New("invalid authorization header format")
-
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			renderError(ctx, ErrAuthorizationFormat)
 			return
 		}
 
 		authorizationType := strings.ToLower(fields[0])
 
 		if authorizationType != "bearer" {
-			err := errors.New(fmt.Sprintf("unsupported authorization type %s", authorizationType))
-
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			renderError(ctx, ErrAuthorizationType)
 			return
 		}
 
@@ -50,23 +57,106 @@ func AuthMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
 		payload, err := tokenMaker.VerifyToken(accessToken)
 
 		if err != nil {
-			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err))
+			renderError(ctx, err)
+			return
+		}
+
+		user, err := store.GetUserByUniqueID(ctx, db.GetUserByUniqueIDParams{
+			ID: pgtype.Int8{
+				Int64: payload.UserId,
+				Valid: true,
+			},
+		})
+
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				renderError(ctx, token.ErrInvalidToken)
+				return
+			}
+
+			renderError(ctx, err)
+			return
+		}
+
+		// Token timestamps only carry whole seconds, so the change time is
+		// truncated the same way before comparing.
+		if user.PasswordChangedAt.Valid && payload.IssuedAt != nil &&
+			payload.IssuedAt.Time.Before(user.PasswordChangedAt.Time.Truncate(time.Second)) {
+			renderError(ctx, ErrTokenRevoked)
 			return
 		}
 
 		ctx.Set(authorizationPayload, payload)
+		ctx.Set(authorizationUser, user)
+
+		ctx.Next()
+	}
+}
+
+// RoleMiddleware only lets through users holding one of roles. It must run
+// after AuthMiddleware, which reads the user from the database on every
+// request, so revoking a role takes effect immediately.
+func RoleMiddleware(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, ok := AuthUser(ctx)
+
+		if !ok {
+			return
+		}
+
+		for _, role := range roles {
+			if user.Role == role {
+				ctx.Next()
+				return
+			}
+		}
+
+		renderError(ctx, util.ErrNotAuthorized)
+	}
+}
+
+// VerifiedEmailMiddleware rejects users whose email address is not verified
+// yet. It must run after AuthMiddleware.
+func VerifiedEmailMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user, ok := AuthUser(ctx)
+
+		if !ok {
+			return
+		}
+
+		if !user.IsEmailVerified {
+			renderError(ctx, util.ErrEmailNotVerified)
+			return
+		}
 
 		ctx.Next()
 	}
 }
 
-func Auth(ctx *gin.Context) *token.Payload {
-	payload, ok := ctx.MustGet(authorizationPayload).(*token.Payload)
+// Auth returns the token payload stored by AuthMiddleware. Without one,
+// the route is missing the middleware: ErrInternal is rendered and ok is
+// false.
+func Auth(ctx *gin.Context) (payload *token.Payload, ok bool) {
+	value, _ := ctx.Get(authorizationPayload)
+	payload, ok = value.(*token.Payload)
+
+	if !ok {
+		renderError(ctx, errors.Join(errors.New("no token payload, route is missing AuthMiddleware"), util.ErrInternal))
+	}
+
+	return payload, ok
+}
+
+// AuthUser returns the user loaded by AuthMiddleware, rendering
+// ErrInternal like Auth when there is none.
+func AuthUser(ctx *gin.Context) (user db.User, ok bool) {
+	value, _ := ctx.Get(authorizationUser)
+	user, ok = value.(db.User)
 
 	if !ok {
-		log.Fatal("payload not conform to the *Payload type")
-		return nil
+		renderError(ctx, errors.Join(errors.New("no user, route is missing AuthMiddleware"), util.ErrInternal))
 	}
 
-	return payload
+	return user, ok
 }
@@ -7,8 +7,16 @@ import (
 	"testing"
 	"time"
 
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/metrics"
+	"github.com/devphasex/cedar-bank-api/ratelimit"
 	"github.com/devphasex/cedar-bank-api/token"
+	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,11 +29,21 @@ func addAuthorization(t *testing.T, request *http.Request, tokeMaker token.Maker
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
 }
 
+// expectAuthUser lets every request through AuthMiddleware and
+// VerifiedEmailMiddleware.
+func expectAuthUser(store *mockdb.MockStore) {
+	store.EXPECT().
+		GetUserByUniqueID(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(db.User{IsEmailVerified: true}, nil)
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	user, _ := randomUser(t)
 	testCases := []struct {
 		name          string
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
@@ -33,15 +51,65 @@ func TestAuthMiddleware(t *testing.T) {
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
 			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
 
+		{
+			name: "PasswordChangedBeforeIssue",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				changed := user
+				changed.PasswordChangedAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(changed, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+
+		{
+			name: "PasswordChangedAfterIssue",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				changed := user
+				changed.PasswordChangedAt = pgtype.Timestamptz{Time: time.Now().Add(2 * time.Second), Valid: true}
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(changed, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Contains(t, recorder.Body.String(), ErrTokenRevoked.Error())
+			},
+		},
+
+		{
+			name: "UserNotFound",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, pgx.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+
 		{
 			name: "NoAuthorization",
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(0)
+			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
@@ -52,6 +120,9 @@ func TestAuthMiddleware(t *testing.T) {
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, "unsupported", user.ID, user.Email, time.Minute)
 			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(0)
+			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
@@ -62,6 +133,9 @@ func TestAuthMiddleware(t *testing.T) {
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, "", user.ID, user.Email, time.Minute)
 			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(0)
+			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
@@ -72,6 +146,9 @@ func TestAuthMiddleware(t *testing.T) {
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, "", user.ID, user.Email, -time.Minute)
 			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(0)
+			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
@@ -80,11 +157,17 @@ func TestAuthMiddleware(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			server := newTestServer(t, nil)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
 
 			authPath := "/auth"
 
-			server.router.GET(authPath, AuthMiddleware(server.tokenMaker), func(ctx *gin.Context) {
+			server.router.GET(authPath, AuthMiddleware(server.tokenMaker, store), func(ctx *gin.Context) {
 				ctx.JSON(http.StatusOK, gin.H{})
 			})
 
@@ -102,3 +185,158 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifiedEmailMiddleware(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Verified",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotVerified",
+			buildStubs: func(store *mockdb.MockStore) {
+				unverified := user
+				unverified.IsEmailVerified = false
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(unverified, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), util.ErrEmailNotVerified.Error())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+
+			path := "/verified"
+			server.router.GET(path, AuthMiddleware(server.tokenMaker, store), VerifiedEmailMiddleware(), func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, path, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAuthWithoutMiddleware(t *testing.T) {
+	server := newTestServer(t, mockdb.NewMockStore(gomock.NewController(t)))
+
+	handlerRan := false
+
+	server.router.GET("/unguarded", VerifiedEmailMiddleware(), func(ctx *gin.Context) {
+		handlerRan = true
+	})
+	server.router.GET("/unguarded-payload", func(ctx *gin.Context) {
+		if _, ok := Auth(ctx); ok {
+			ctx.JSON(http.StatusOK, gin.H{})
+		}
+	})
+
+	for _, path := range []string{"/unguarded", "/unguarded-payload"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusInternalServerError, recorder.Code, path)
+	}
+
+	require.False(t, handlerRan)
+}
+
+func TestAuthCountsTokenFailureOnce(t *testing.T) {
+	user, _ := randomUser(t)
+	server := newTestServer(t, mockdb.NewMockStore(gomock.NewController(t)))
+
+	// The rate limiter reads the bearer token before AuthMiddleware does.
+	rules, err := ratelimit.ParseRules("GET /accounts=100/m@user")
+	require.NoError(t, err)
+
+	server.limiter, server.rateLimits = ratelimit.NewMemoryLimiter(), rules
+	server.setupRouter()
+
+	testCases := []struct {
+		name      string
+		setupAuth func(t *testing.T, request *http.Request)
+		reason    string
+	}{
+		{
+			name: "ExpiredToken",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Email, -time.Minute)
+			},
+			reason: token.ErrExpiredToken.Code(),
+		},
+		{
+			name: "InvalidToken",
+			setupAuth: func(t *testing.T, request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, authorizationTypeBearer+" garbage")
+			},
+			reason: token.ErrInvalidToken.Code(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			before := tokenFailures(t, tc.reason)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/accounts", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request)
+			server.router.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			require.Equal(t, before+1, tokenFailures(t, tc.reason))
+		})
+	}
+}
+
+// tokenFailures reads the count of tokens rejected for reason.
+func tokenFailures(t *testing.T, reason string) float64 {
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != "cedar_token_verification_failures_total" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "reason" && label.GetValue() == reason {
+					return metric.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+
+	return 0
+}
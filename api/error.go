@@ -1,37 +1,83 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
-// Custom error handler for Gin
-func prettyValidateError(err error) FailedResponse {
-	errs := err.(validator.ValidationErrors)
-	var errMessages []string
+var ErrMalformedRequest = util.NewCustomError(util.KindInvalidArgument, "ErrMalformedRequest", "request could not be parsed")
+
+// renderError answers with err rendered from the error catalog and stops
+// the handler chain. Errors outside the catalog are reported as
+// util.ErrInternal, so query failures and the like never reach clients;
+// the access log records the actual error.
+func renderError(ctx *gin.Context, err error) {
+	renderErrorDetails(ctx, err, nil)
+}
+
+// renderErrorDetails is renderError with extra details for the client to
+// recover with.
+func renderErrorDetails(ctx *gin.Context, err error, details any) {
+	customErr, _ := util.AsCustomError(err)
+	requestID := util.RequestIDFromContext(ctx.Request.Context())
+
+	_ = ctx.Error(err)
+
+	body := customErr.Body(requestID, ctx.GetHeader("Accept-Language"))
+	body.Details = details
+
+	ctx.AbortWithStatusJSON(customErr.Kind().HTTPStatus(), FailedResponse{Status: false, Error: body})
+}
+
+// validationError turns what binding a request failed on into
+// util.ErrInvalidArgument with a violation per field.
+func validationError(err error) error {
+	var errs validator.ValidationErrors
+
+	if !errors.As(err, &errs) {
+		return ErrMalformedRequest.WithViolations(util.FieldViolation{
+			Field:       "body",
+			Description: err.Error(),
+		})
+	}
+
+	violations := make([]util.FieldViolation, 0, len(errs))
+
 	for _, e := range errs {
-		switch e.Tag() {
-		case "currency":
-			currencyErr := &util.UnsupportedCurrencyError{Currency: e.Value().(string)}
-			errMessages = append(errMessages, currencyErr.Error())
-		default:
-			errMessages = append(errMessages, formatValidationError(e))
-		}
+		violations = append(violations, util.FieldViolation{
+			Field:       e.Field(),
+			Description: formatValidationError(e),
+		})
 	}
-	return errorResponse(errMessages)
+
+	return util.ErrInvalidArgument.WithViolations(violations...)
 }
 
 // Helper function to format validation errors
 func formatValidationError(e validator.FieldError) string {
-	field := strings.ToLower(e.Field())
 	switch e.Tag() {
+	case "currency":
+		currencyErr := &util.UnsupportedCurrencyError{Currency: fmt.Sprint(e.Value())}
+		return currencyErr.Error()
 	case "required":
-		return field + " is required"
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required without %s", strings.ToLower(e.Param()))
+	case "min":
+		return fmt.Sprintf("must be at least %s", e.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s", e.Param())
+	case "email":
+		return "is not a valid email address"
+	case "oneof":
+		return fmt.Sprintf("must be one of %s", e.Param())
 	// Add more cases for other validation tags as needed
 	default:
-		return fmt.Sprintf("%s failed on the '%s' tag", field, e.Tag())
+		return fmt.Sprintf("failed on the '%s' tag", e.Tag())
 	}
 }
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderError(t *testing.T) {
+	user, password := randomUser(t)
+
+	util.RegisterMessages("eo", map[string]string{
+		util.ErrInvalidArgument.Code(): "peto havas nevalidajn kampojn",
+	})
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		header        http.Header
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, rsp FailedResponse)
+	}{
+		{
+			name: "Violations",
+			body: gin.H{"username": user.Username, "fullname": user.Fullname, "email": "not-an-email"},
+			header: http.Header{
+				util.RequestIDHeader: {"client-chosen-id"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp FailedResponse) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, util.ErrInvalidArgument.Code(), rsp.Error.Code)
+				require.Equal(t, util.ErrInvalidArgument.Error(), rsp.Error.Message)
+				require.Equal(t, "client-chosen-id", rsp.Error.RequestID)
+				require.Equal(t, "client-chosen-id", recorder.Header().Get(util.RequestIDHeader))
+				require.Equal(t, []util.FieldViolation{
+					{Field: "email", Description: "is not a valid email address"},
+					{Field: "password", Description: "is required"},
+				}, rsp.Error.Violations)
+			},
+		},
+		{
+			name: "Localized",
+			body: gin.H{},
+			header: http.Header{
+				"Accept-Language": {"de;q=0.5, eo-US, en;q=0.8"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp FailedResponse) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, "peto havas nevalidajn kampojn", rsp.Error.Message)
+				require.NotEmpty(t, rsp.Error.RequestID)
+				require.Equal(t, rsp.Error.RequestID, recorder.Header().Get(util.RequestIDHeader))
+			},
+		},
+		{
+			name: "InternalErrorHidden",
+			body: gin.H{"username": user.Username, "fullname": user.Fullname, "email": user.Email, "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.User{}, errors.New("connection to 10.0.0.5:5432 refused"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp FailedResponse) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, util.ErrInternal.Code(), rsp.Error.Code)
+				require.NotContains(t, recorder.Body.String(), "10.0.0.5")
+			},
+		},
+		{
+			name: "MalformedBody",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, rsp FailedResponse) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, ErrMalformedRequest.Code(), rsp.Error.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body := []byte("{")
+
+			if tc.body != nil {
+				var err error
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			request, err := http.NewRequest(http.MethodPost, "/auth/sign-up", bytes.NewBuffer(body))
+			require.NoError(t, err)
+
+			for key, values := range tc.header {
+				request.Header[key] = values
+			}
+
+			server.router.ServeHTTP(recorder, request)
+
+			var rsp FailedResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.False(t, rsp.Status)
+
+			tc.checkResponse(t, recorder, rsp)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrInterestRateExists = util.NewCustomError(util.KindAlreadyExists, "ErrInterestRateExists", "a rate for this account type and currency already takes effect on that date")
+
+type CreateInterestRateRequest struct {
+	AccountType string   `json:"account_type" binding:"required,oneof=checking savings"`
+	Currency    string   `json:"currency" binding:"required,currency"`
+	AnnualRate  *float64 `json:"annual_rate" binding:"required,gte=0"`
+	// EffectiveFrom is a calendar date (YYYY-MM-DD). The rate applies to
+	// accruals from that day until a later rate takes effect.
+	EffectiveFrom string `json:"effective_from" binding:"required,datetime=2006-01-02"`
+}
+
+func (s *Server) createInterestRate(ctx *gin.Context) {
+	var req CreateInterestRateRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	effectiveFrom, err := time.Parse(time.DateOnly, req.EffectiveFrom)
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	rate, err := s.store.CreateInterestRate(ctx, db.CreateInterestRateParams{
+		AccountType:   req.AccountType,
+		Currency:      req.Currency,
+		AnnualRate:    *req.AnnualRate,
+		EffectiveFrom: pgtype.Date{Time: effectiveFrom, Valid: true},
+	})
+
+	if err != nil {
+		if err, ok := err.(*pgconn.PgError); ok && err.ConstraintName == "unique_interest_rate_effective_from" {
+			renderError(ctx, ErrInterestRateExists)
+			return
+		}
+
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(rate, "interest rate scheduled"))
+}
+
+func (s *Server) getInterestRates(ctx *gin.Context) {
+	rates, err := s.store.GetInterestRates(ctx)
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(rates))
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateInterestRateAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	depositor, _ := randomUser(t)
+
+	effectiveFrom := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		user          db.User
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			user: admin,
+			body: gin.H{"account_type": util.SavingsAccount, "currency": "USD", "annual_rate": 2.5, "effective_from": "2024-05-01"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().
+					CreateInterestRate(gomock.Any(), gomock.Eq(db.CreateInterestRateParams{
+						AccountType:   util.SavingsAccount,
+						Currency:      "USD",
+						AnnualRate:    2.5,
+						EffectiveFrom: pgtype.Date{Time: effectiveFrom, Valid: true},
+					})).
+					Times(1).
+					Return(db.InterestRate{ID: 1}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotAdmin",
+			user: depositor,
+			body: gin.H{"account_type": util.SavingsAccount, "currency": "USD", "annual_rate": 2.5, "effective_from": "2024-05-01"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(depositor, nil)
+				store.EXPECT().CreateInterestRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDate",
+			user: admin,
+			body: gin.H{"account_type": util.SavingsAccount, "currency": "USD", "annual_rate": 2.5, "effective_from": "05/01/2024"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().CreateInterestRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidAccountType",
+			user: admin,
+			body: gin.H{"account_type": "brokerage", "currency": "USD", "annual_rate": 2.5, "effective_from": "2024-05-01"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().CreateInterestRate(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyScheduled",
+			user: admin,
+			body: gin.H{"account_type": util.SavingsAccount, "currency": "USD", "annual_rate": 2.5, "effective_from": "2024-05-01"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().
+					CreateInterestRate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.InterestRate{}, &pgconn.PgError{ConstraintName: "unique_interest_rate_effective_from"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/interest-rates", bytes.NewBuffer(b))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.user.ID, tc.user.Email, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
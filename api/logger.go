@@ -0,0 +1,57 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/token"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLogMiddleware logs every request once it has been answered, at
+// warn for client errors and error for server errors. Query parameters
+// such as a verification token are redacted; bodies are never logged.
+// It must come after RequestIDMiddleware.
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("method", ctx.Request.Method),
+			slog.String("path", ctx.Request.URL.Path),
+			slog.String("route", ctx.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", ctx.ClientIP()),
+		}
+
+		if query := ctx.Request.URL.RawQuery; query != "" {
+			attrs = append(attrs, slog.String("query", util.RedactQuery(query)))
+		}
+
+		if payload, ok := ctx.Get(authorizationPayload); ok {
+			attrs = append(attrs, slog.Int64("user_id", payload.(*token.Payload).UserId))
+		}
+
+		if len(ctx.Errors) != 0 {
+			attrs = append(attrs, slog.String("error", ctx.Errors.Last().Error()))
+		}
+
+		level := slog.LevelInfo
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		reqCtx := ctx.Request.Context()
+		util.Logger(reqCtx).LogAttrs(reqCtx, level, "http request", attrs...)
+	}
+}
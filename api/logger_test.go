@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+// captureLogs makes the default logger write JSON records to the returned
+// buffer for the rest of the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+
+	logger, err := util.NewLogger(&buf, "json", "debug")
+	require.NoError(t, err)
+
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	return &buf
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.ID)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+	store.EXPECT().GetAccountByID(gomock.Any(), account.ID).Times(1).Return(account, nil)
+
+	server := newTestServer(t, store)
+	logs := captureLogs(t)
+
+	url := fmt.Sprintf("/accounts/%d?token=leaked&verbose=1", account.ID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	request.Header.Set(util.RequestIDHeader, "trace-me")
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+
+	require.Equal(t, "INFO", record["level"])
+	require.Equal(t, "http request", record["msg"])
+	require.Equal(t, "trace-me", record["request_id"])
+	require.Equal(t, http.MethodGet, record["method"])
+	require.Equal(t, "/accounts/:id", record["route"])
+	require.EqualValues(t, http.StatusOK, record["status"])
+	require.EqualValues(t, user.ID, record["user_id"])
+	require.Equal(t, "token=[REDACTED]&verbose=1", record["query"])
+	require.Contains(t, record, "latency")
+	require.Contains(t, record, "client_ip")
+	require.NotContains(t, logs.String(), "leaked")
+}
+
+func TestAccessLogMiddlewareError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	logs := captureLogs(t)
+
+	request, err := http.NewRequest(http.MethodGet, "/accounts/1", nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+
+	require.Equal(t, "WARN", record["level"])
+	require.Equal(t, recorder.Header().Get(util.RequestIDHeader), record["request_id"])
+	require.Equal(t, ErrAuthorizationMissing.Error(), record["error"])
+	require.NotContains(t, record, "user_id")
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"os"
 	"testing"
+	"time"
 
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
 	"github.com/devphasex/cedar-bank-api/util"
@@ -12,7 +13,23 @@ import (
 
 func newTestServer(t *testing.T, store db.Store) *Server {
 	server, err := NewServer(store, &util.Config{
-		SymmetricKey: util.RandomString(32),
+		SymmetricKey:            util.RandomString(32),
+		TransferHoldTTL:         time.Hour,
+		Mailer:                  "memory",
+		EmailVerifyURL:          "http://localhost:8080/auth/verify-email",
+		EmailVerifyTTL:          time.Hour,
+		EmailVerifyResendLimit:  3,
+		EmailVerifyResendWindow: time.Hour,
+		PasswordResetURL:        "http://localhost:8080/auth/password/reset",
+		PasswordResetTTL:        time.Hour,
+		PasswordResetLimit:      3,
+		PasswordResetWindow:     time.Hour,
+		TOTPIssuer:              "Cedar Bank",
+		SigninChallengeTTL:      5 * time.Minute,
+		PasswordHasher:          "argon2id",
+		PasswordMinLength:       8,
+		PasswordMaxLength:       64,
+		PasswordMinClasses:      1,
 	})
 
 	require.NoError(t, err)
@@ -0,0 +1,20 @@
+package api
+
+import (
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records the rate, errors and duration of requests by
+// route.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		metrics.ObserveHTTP(ctx.Request.Method, ctx.FullPath(), ctx.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type UpdateOverdraftUri struct {
+	ID int64 `uri:"id" binding:"min=1"`
+}
+
+type UpdateOverdraftRequest struct {
+	OverdraftLimit *float64 `json:"overdraft_limit" binding:"required,gte=0"`
+}
+
+func (s *Server) updateOverdraftLimit(ctx *gin.Context) {
+	var uri UpdateOverdraftUri
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	var req UpdateOverdraftRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	account, err := s.store.UpdateAccountOverdraftLimit(ctx, db.UpdateAccountOverdraftLimitParams{
+		ID:             uri.ID,
+		OverdraftLimit: *req.OverdraftLimit,
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			renderError(ctx, db.ErrAccountNotFound)
+			return
+		}
+
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(account, "overdraft limit updated"))
+}
@@ -0,0 +1,298 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/mail"
+	"github.com/devphasex/cedar-bank-api/passwordpolicy"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/devphasex/cedar-bank-api/util/hash"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var (
+	ErrPasswordMismatch       = util.NewCustomError(util.KindPermissionDenied, "ErrPasswordMismatch", "current password is incorrect")
+	ErrPasswordUnchanged      = util.NewCustomError(util.KindInvalidArgument, "ErrPasswordUnchanged", "new password must differ from the current one")
+	ErrPasswordResetsExceeded = util.NewCustomError(util.KindRateLimited, "ErrPasswordResetsExceeded", "too many password resets requested, try again later")
+	ErrPasswordResetNotSent   = util.NewCustomError(util.KindInternal, "ErrPasswordResetNotSent", "could not send password reset email")
+)
+
+// hashPassword returns the hash stored for a user, made by the configured
+// PasswordHasher.
+func (s *Server) hashPassword(password string) (string, error) {
+	return s.hasher.Hash([]byte(password))
+}
+
+// checkPassword verifies password against the user's hash, whichever
+// supported algorithm made it.
+func checkPassword(user db.User, password string) error {
+	return hash.Verify(user.HashedPassword, []byte(password))
+}
+
+// checkPasswordPolicy answers with util.ErrWeakPassword, listing every
+// policy rule password breaks for the account of username and email as a
+// violation of field, and reports whether it may be used.
+func (s *Server) checkPasswordPolicy(ctx *gin.Context, field, password, username, email string) bool {
+	err := s.policy.Check(ctx, password, username, email)
+
+	if err == nil {
+		return true
+	}
+
+	var policyErr *passwordpolicy.Error
+
+	if !errors.As(err, &policyErr) {
+		renderError(ctx, err)
+		return false
+	}
+
+	violations := make([]util.FieldViolation, len(policyErr.Violations))
+
+	for i, violation := range policyErr.Violations {
+		violations[i] = util.FieldViolation{Field: field, Description: violation}
+	}
+
+	renderError(ctx, util.ErrWeakPassword.WithViolations(violations...))
+	return false
+}
+
+// rehashPassword upgrades the stored hash of a user who just proved their
+// password if it was made by another algorithm than the configured one,
+// or with outdated parameters. The update
+// only applies while the old hash is still in place, so it never undoes a
+// concurrent password change. Failing is logged; the old hash keeps
+// working.
+func (s *Server) rehashPassword(ctx context.Context, user db.User, password string) {
+	if !s.hasher.NeedsRehash(user.HashedPassword) {
+		return
+	}
+
+	passwordHash, err := s.hashPassword(password)
+
+	if err == nil {
+		_, err = s.store.RehashUserPassword(ctx, db.RehashUserPasswordParams{
+			ID:                user.ID,
+			HashedPassword:    passwordHash,
+			OldHashedPassword: user.HashedPassword,
+		})
+	}
+
+	if err != nil {
+		util.Logger(ctx).Error("cannot rehash password", "user_id", user.ID, "error", err)
+	}
+}
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// changePassword replaces the password of the signed in user. All of their
+// sessions are revoked, including the one making the request, so the
+// client has to sign in again.
+func (s *Server) changePassword(ctx *gin.Context) {
+	var req ChangePasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	user, ok := AuthUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	if err := checkPassword(user, req.OldPassword); err != nil {
+		renderError(ctx, ErrPasswordMismatch)
+		return
+	}
+
+	if req.NewPassword == req.OldPassword {
+		renderError(ctx, ErrPasswordUnchanged)
+		return
+	}
+
+	if !s.checkPasswordPolicy(ctx, "new_password", req.NewPassword, user.Username, user.Email) {
+		return
+	}
+
+	passwordHash, err := s.hashPassword(req.NewPassword)
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	updated, err := s.store.ChangePasswordTx(ctx, db.UpdateUserPasswordParams{
+		ID:             user.ID,
+		HashedPassword: passwordHash,
+	})
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(newUserResponse(*updated), "password changed successfully, sign in again"))
+}
+
+// sendPasswordReset issues a reset token for user and mails the link.
+// Only the token hash is stored.
+func (s *Server) sendPasswordReset(ctx context.Context, user db.User) error {
+	token, tokenHash, err := util.NewSecretToken()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = s.store.CreatePasswordReset(ctx, db.CreatePasswordResetParams{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(s.config.PasswordResetTTL),
+			Valid: true,
+		},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	msg, err := mail.PasswordResetMessage(user.Email, user.Fullname, s.config.PasswordResetURL, token, s.config.PasswordResetTTL)
+
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, msg)
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// forgotPassword mails a reset link. Like resendVerifyEmail it answers the
+// same way for unknown addresses; only the rate limit is reported.
+func (s *Server) forgotPassword(ctx *gin.Context) {
+	var req ForgotPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	const sent = "if the address belongs to an account, a password reset email is on its way"
+
+	user, err := s.store.GetUserByUniqueID(ctx, db.GetUserByUniqueIDParams{
+		Email: pgtype.Text{
+			String: req.Email,
+			Valid:  true,
+		},
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusOK, sucessResponse(nil, sent))
+			return
+		}
+
+		renderError(ctx, err)
+		return
+	}
+
+	recent, err := s.store.CountPasswordResetsSince(ctx, db.CountPasswordResetsSinceParams{
+		UserID: user.ID,
+		Since: pgtype.Timestamptz{
+			Time:  time.Now().Add(-s.config.PasswordResetWindow),
+			Valid: true,
+		},
+	})
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	if recent >= s.config.PasswordResetLimit {
+		ctx.Header("Retry-After", fmt.Sprintf("%.0f", s.config.PasswordResetWindow.Seconds()))
+		renderError(ctx, ErrPasswordResetsExceeded)
+		return
+	}
+
+	if err = s.sendPasswordReset(ctx, user); err != nil {
+		util.Logger(ctx).Error("cannot send password reset email", "user_id", user.ID, "error", err)
+		renderError(ctx, ErrPasswordResetNotSent)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(nil, sent))
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+func (s *Server) resetPassword(ctx *gin.Context) {
+	var req ResetPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	tokenHash := util.HashSecretToken(req.Token)
+
+	// The policy needs the account the token belongs to. A spent or expired
+	// token is turned away before the policy can tell anything about it.
+	reset, err := s.store.GetPasswordResetUser(ctx, tokenHash)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			renderError(ctx, db.ErrResetTokenInvalid)
+			return
+		}
+
+		renderError(ctx, err)
+		return
+	}
+
+	if err = reset.Check(); err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	owner := reset.User
+
+	if !s.checkPasswordPolicy(ctx, "new_password", req.NewPassword, owner.Username, owner.Email) {
+		return
+	}
+
+	passwordHash, err := s.hashPassword(req.NewPassword)
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	user, err := s.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		TokenHash:      tokenHash,
+		HashedPassword: passwordHash,
+	})
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(newUserResponse(*user), "password reset successfully, sign in with your new password"))
+}
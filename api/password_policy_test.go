@@ -0,0 +1,85 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	"github.com/devphasex/cedar-bank-api/passwordpolicy"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordPolicyAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	breached, err := passwordpolicy.LoadBreachList("../passwordpolicy/testdata/breached.txt")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		password   string
+		violations []string
+	}{
+		{
+			name:     "Weak",
+			password: user.Username,
+			violations: []string{
+				"password must be at least 10 characters long",
+				"password must mix at least 3 of lower case letters, upper case letters, digits and symbols",
+				"password must not contain your username or email",
+			},
+		},
+		{
+			name:       "Breached",
+			password:   "Tr0ub4dor&3",
+			violations: []string{"password appears in a known data breach, choose another one"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Times(0)
+
+			server := newTestServer(t, store)
+			server.policy = &passwordpolicy.Policy{MinLength: 10, MaxLength: 64, MinClasses: 3, Breached: breached}
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(gin.H{
+				"username": user.Username,
+				"fullname": user.Fullname,
+				"password": tc.password,
+				"email":    user.Email,
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/auth/sign-up", bytes.NewBuffer(b))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+			var rsp FailedResponse
+			require.NoError(t, json.NewDecoder(recorder.Body).Decode(&rsp))
+			require.Equal(t, util.ErrWeakPassword.Code(), rsp.Error.Code)
+
+			violations := make([]string, len(rsp.Error.Violations))
+
+			for i, violation := range rsp.Error.Violations {
+				require.Equal(t, "password", violation.Field)
+				violations[i] = violation.Description
+			}
+
+			require.Equal(t, tc.violations, violations)
+		})
+	}
+}
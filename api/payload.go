@@ -1,24 +1,9 @@
 package api
 
-type FailedResponse struct {
-	Status bool `json:"status"`
-	Error  any  `json:"error"`
-}
-
-func errorResponse(err any) FailedResponse {
-	if err, ok := err.(error); ok {
-		if unwrapErr, ok := err.(interface{ Unwrap() []error }); ok {
-			errs := unwrapErr.Unwrap()
-			if len(errs) > 0 {
-				// Use the first error in the slice for the message
-				err = errs[0]
-			}
-		}
-		return FailedResponse{Status: false, Error: err.Error()}
-	}
+import "github.com/devphasex/cedar-bank-api/util"
 
-	return FailedResponse{Status: false, Error: err}
-}
+// FailedResponse is the body of every error, see renderError.
+type FailedResponse = util.ErrorResponse
 
 type SuccessResponse struct {
 	Status  bool   `json:"status"`
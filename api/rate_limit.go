@@ -0,0 +1,69 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/devphasex/cedar-bank-api/ratelimit"
+	"github.com/devphasex/cedar-bank-api/token"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitMiddleware takes a token for every request from the bucket its
+// route's rule selects and answers 429 when there is none. It runs ahead
+// of AuthMiddleware, so rules keyed by user read the bearer token
+// themselves. Should the limiter fail the request goes through; an outage
+// of the limiter is no reason to turn every client away.
+func RateLimitMiddleware(limiter ratelimit.Limiter, rules *ratelimit.Rules, tokenMaker token.Maker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		rule, ok := rules.Match(ctx.Request.Method + " " + ctx.FullPath())
+
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		var userID int64
+
+		if rule.By == ratelimit.ByUser {
+			userID = bearerUserID(ctx, tokenMaker)
+		}
+
+		key := rule.Key(ctx.ClientIP(), userID)
+		result, err := limiter.Take(ctx, key, rule)
+
+		if err != nil {
+			util.Logger(ctx).Error("cannot rate limit", "key", key, "error", err)
+			ctx.Next()
+			return
+		}
+
+		if !result.Allowed {
+			ctx.Header("Retry-After", fmt.Sprintf("%.0f", math.Ceil(result.RetryAfter.Seconds())))
+			renderError(ctx, util.ErrRateLimited)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// bearerUserID is the user of the request's bearer token, zero if there
+// is no valid one.
+func bearerUserID(ctx *gin.Context, tokenMaker token.Maker) int64 {
+	fields := strings.Fields(ctx.GetHeader(authorizationHeaderKey))
+
+	if len(fields) < 2 || strings.ToLower(fields[0]) != authorizationTypeBearer {
+		return 0
+	}
+
+	payload, err := tokenMaker.VerifyToken(fields[1])
+
+	if err != nil {
+		return 0
+	}
+
+	return payload.UserId
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	"github.com/devphasex/cedar-bank-api/ratelimit"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	user, _ := randomUser(t)
+	other, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		rules         string
+		requests      func(t *testing.T, server *Server) []*http.Request
+		checkResponse func(t *testing.T, recorders []*httptest.ResponseRecorder)
+	}{
+		{
+			name:  "ByIP",
+			rules: "GET /limited=2/m@ip",
+			requests: func(t *testing.T, server *Server) []*http.Request {
+				return []*http.Request{
+					newLimitedRequest(t, "10.0.0.1:1000"),
+					newLimitedRequest(t, "10.0.0.1:1001"),
+					newLimitedRequest(t, "10.0.0.1:1002"),
+					newLimitedRequest(t, "10.0.0.2:1000"),
+				}
+			},
+			checkResponse: func(t *testing.T, recorders []*httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorders[0].Code)
+				require.Equal(t, http.StatusOK, recorders[1].Code)
+				require.Equal(t, http.StatusTooManyRequests, recorders[2].Code)
+				require.Equal(t, "30", recorders[2].Header().Get("Retry-After"))
+				require.Contains(t, recorders[2].Body.String(), util.ErrRateLimited.Error())
+				require.Equal(t, http.StatusOK, recorders[3].Code)
+			},
+		},
+		{
+			name:  "ByUser",
+			rules: "GET /limited=1/m@user",
+			requests: func(t *testing.T, server *Server) []*http.Request {
+				first := newLimitedRequest(t, "10.0.0.1:1000")
+				addAuthorization(t, first, server.tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+
+				again := newLimitedRequest(t, "10.0.0.2:1000")
+				addAuthorization(t, again, server.tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+
+				otherUser := newLimitedRequest(t, "10.0.0.1:1000")
+				addAuthorization(t, otherUser, server.tokenMaker, authorizationTypeBearer, other.ID, other.Email, time.Minute)
+
+				return []*http.Request{first, again, otherUser}
+			},
+			checkResponse: func(t *testing.T, recorders []*httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorders[0].Code)
+				require.Equal(t, http.StatusTooManyRequests, recorders[1].Code)
+				require.Equal(t, http.StatusOK, recorders[2].Code)
+			},
+		},
+		{
+			name:  "ByRoute",
+			rules: "GET /limited=1/m@route",
+			requests: func(t *testing.T, server *Server) []*http.Request {
+				return []*http.Request{
+					newLimitedRequest(t, "10.0.0.1:1000"),
+					newLimitedRequest(t, "10.0.0.2:1000"),
+				}
+			},
+			checkResponse: func(t *testing.T, recorders []*httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorders[0].Code)
+				require.Equal(t, http.StatusTooManyRequests, recorders[1].Code)
+			},
+		},
+		{
+			name:  "OtherRoute",
+			rules: "GET /elsewhere=1/m@route",
+			requests: func(t *testing.T, server *Server) []*http.Request {
+				return []*http.Request{
+					newLimitedRequest(t, "10.0.0.1:1000"),
+					newLimitedRequest(t, "10.0.0.1:1000"),
+				}
+			},
+			checkResponse: func(t *testing.T, recorders []*httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorders[0].Code)
+				require.Equal(t, http.StatusOK, recorders[1].Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+			rules, err := ratelimit.ParseRules(tc.rules)
+			require.NoError(t, err)
+
+			router := gin.New()
+			router.GET("/limited", RateLimitMiddleware(ratelimit.NewMemoryLimiter(), rules, server.tokenMaker), func(ctx *gin.Context) {
+				ctx.JSON(http.StatusOK, gin.H{})
+			})
+
+			var recorders []*httptest.ResponseRecorder
+
+			for _, request := range tc.requests(t, server) {
+				recorder := httptest.NewRecorder()
+				router.ServeHTTP(recorder, request)
+				recorders = append(recorders, recorder)
+			}
+
+			tc.checkResponse(t, recorders)
+		})
+	}
+}
+
+func newLimitedRequest(t *testing.T, remoteAddr string) *http.Request {
+	request, err := http.NewRequest(http.MethodGet, "/limited", nil)
+	require.NoError(t, err)
+
+	request.RemoteAddr = remoteAddr
+	return request
+}
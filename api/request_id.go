@@ -0,0 +1,30 @@
+package api
+
+import (
+	"log/slog"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDMiddleware tags every request with an id, the client's own from
+// the X-Request-Id header if it sent a usable one. The id is echoed back in
+// the same header and kept in the request context, where error bodies pick
+// it up, along with a logger that tags records with it. It is also set on
+// the request's span.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := util.RequestID(ctx.GetHeader(util.RequestIDHeader))
+
+		logger := slog.Default().With("request_id", id)
+		reqCtx := util.WithLogger(util.WithRequestID(ctx.Request.Context(), id), logger)
+
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Header(util.RequestIDHeader, id)
+		trace.SpanFromContext(reqCtx).SetAttributes(attribute.String("request.id", id))
+
+		ctx.Next()
+	}
+}
@@ -1,9 +1,16 @@
 package api
 
 import (
+	"net/http"
+
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/mail"
+	"github.com/devphasex/cedar-bank-api/metrics"
+	"github.com/devphasex/cedar-bank-api/passwordpolicy"
+	"github.com/devphasex/cedar-bank-api/ratelimit"
 	"github.com/devphasex/cedar-bank-api/token"
 	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/devphasex/cedar-bank-api/util/hash"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
@@ -11,9 +18,19 @@ import (
 
 type Server struct {
 	tokenMaker token.Maker
+	// authMaker is tokenMaker counting the tokens it rejects. Only
+	// authentication uses it; a bearer token looked at for logging or
+	// rate limiting is not counted again.
+	authMaker  token.Maker
 	store      db.Store
 	router     *gin.Engine
 	config     *util.Config
+	mailer     mail.Mailer
+	hasher     hash.PasswordHasher
+	policy     *passwordpolicy.Policy
+	limiter    ratelimit.Limiter
+	rateLimits *ratelimit.Rules
+	timeouts   util.RouteTimeouts
 }
 
 func NewServer(store db.Store, config *util.Config) (*Server, error) {
@@ -24,10 +41,55 @@ func NewServer(store db.Store, config *util.Config) (*Server, error) {
 		return nil, err
 	}
 
+	mailer, err := mail.NewMailer(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hasher, err := hash.NewPasswordHasher(config.PasswordHasher)
+
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := passwordpolicy.NewPolicy(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimits, err := ratelimit.ParseRules(config.RateLimits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	timeouts, err := util.ParseRouteTimeouts(config.RequestTimeouts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var limiter ratelimit.Limiter
+
+	if !rateLimits.Empty() {
+		if limiter, err = ratelimit.NewLimiter(config, store); err != nil {
+			return nil, err
+		}
+	}
+
 	server := &Server{
 		store:      store,
 		config:     config,
 		tokenMaker: tokenMaker,
+		authMaker:  metrics.InstrumentMaker(tokenMaker),
+		mailer:     mailer,
+		hasher:     hasher,
+		policy:     policy,
+		limiter:    limiter,
+		rateLimits: rateLimits,
+		timeouts:   timeouts,
 	}
 
 	if validator, ok := binding.Validator.Engine().(*validator.Validate); ok {
@@ -39,22 +101,72 @@ func NewServer(store db.Store, config *util.Config) (*Server, error) {
 }
 
 func (s *Server) Start(address string) error {
-	return s.router.Run(address)
+	server := &http.Server{
+		Addr:              address,
+		Handler:           s.router,
+		ReadHeaderTimeout: s.config.HTTPReadHeaderTimeout,
+		ReadTimeout:       s.config.HTTPReadTimeout,
+		WriteTimeout:      s.config.HTTPWriteTimeout,
+		IdleTimeout:       s.config.HTTPIdleTimeout,
+	}
+
+	return server.ListenAndServe()
 }
 
 func (s *Server) setupRouter() {
-	router := gin.Default()
+	router := gin.New()
+	// Handlers pass the gin context on as a context.Context; with the
+	// fallback it carries the request's logger, deadline and cancellation.
+	router.ContextWithFallback = true
+	// Nothing sits in front of this server, so X-Forwarded-For comes from
+	// the client itself; ClientIP, which throttling and rate limits key
+	// on, is then the remote address. Trusting no one cannot fail.
+	_ = router.SetTrustedProxies(nil)
+	router.Use(TracingMiddleware(), RequestIDMiddleware(), AccessLogMiddleware(), MetricsMiddleware(), gin.Recovery(), TimeoutMiddleware(s.timeouts))
+
+	if s.limiter != nil {
+		router.Use(RateLimitMiddleware(s.limiter, s.rateLimits, s.tokenMaker))
+	}
 
 	router.POST("/auth/sign-up", s.createUser)
 	router.POST("/auth/sign-in", s.signin)
 	router.POST("/auth/token/refresh", s.renewAccessToken)
+	router.GET("/auth/verify-email", s.verifyEmail)
+	router.POST("/auth/verify-email/resend", s.resendVerifyEmail)
+	router.POST("/auth/password/forgot", s.forgotPassword)
+	router.POST("/auth/password/reset", s.resetPassword)
+	router.POST("/auth/2fa/verify", s.verifySigninChallenge)
 
-	authProtectedRoute := router.Group("/").Use(AuthMiddleware(s.tokenMaker))
+	authProtectedRoute := router.Group("/").Use(AuthMiddleware(s.authMaker, s.store))
 
+	authProtectedRoute.PUT("/auth/password", s.changePassword)
+	authProtectedRoute.POST("/auth/2fa/enroll", s.enrollTOTP)
+	authProtectedRoute.POST("/auth/2fa/confirm", s.confirmTOTP)
+	authProtectedRoute.POST("/auth/2fa/disable", s.disableTOTP)
+	authProtectedRoute.POST("/auth/step-up", s.stepUp)
 	authProtectedRoute.POST("/accounts", s.createAccount)
 	authProtectedRoute.GET("/accounts/:id", s.getAccountByID)
 	authProtectedRoute.GET("/accounts", s.getAccountList)
-	authProtectedRoute.POST("/transfer", s.createTransfer)
+	authProtectedRoute.GET("/accounts/:id/statement", s.getAccountStatement)
+	authProtectedRoute.POST("/transfer/quote", s.quoteTransfer)
+	authProtectedRoute.GET("/transfer/holds/:id", s.getTransferHold)
+
+	// Moving money additionally requires a verified email address.
+	verifiedRoute := router.Group("/").Use(AuthMiddleware(s.authMaker, s.store), VerifiedEmailMiddleware())
+
+	verifiedRoute.POST("/transfer", s.createTransfer)
+	verifiedRoute.POST("/transfer/holds", s.authorizeTransfer)
+	verifiedRoute.POST("/transfer/holds/:id/capture", s.captureTransferHold)
+	verifiedRoute.POST("/transfer/holds/:id/void", s.voidTransferHold)
+
+	adminRoute := router.Group("/admin").Use(AuthMiddleware(s.authMaker, s.store), RoleMiddleware(util.AdminRole))
+
+	adminRoute.POST("/transfers/:id/reverse", s.reverseTransfer)
+	adminRoute.GET("/transfers/:id/reversals", s.getTransferReversals)
+	adminRoute.PUT("/accounts/:id/overdraft", s.updateOverdraftLimit)
+	adminRoute.POST("/interest-rates", s.createInterestRate)
+	adminRoute.GET("/interest-rates", s.getInterestRates)
+	adminRoute.POST("/users/:id/unlock", s.unlockUser)
 
 	s.router = router
 }
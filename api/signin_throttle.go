@@ -0,0 +1,78 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+)
+
+// checkSigninThrottle answers with 429 and records the refused attempt when
+// the user or client ip is still delayed or locked out. It reports whether
+// the sign-in may go ahead, the attempt then reserved so that parallel
+// guesses are throttled too.
+func (s *Server) checkSigninThrottle(ctx *gin.Context, attempt *db.RecordSigninAttemptTxParams) bool {
+	retryAt, err := s.store.ReserveSigninAttemptTx(ctx, db.SigninRetryAtParams{
+		UserID:   attempt.UserID,
+		ClientIP: attempt.ClientIP,
+		Policy:   attempt.Policy,
+	})
+
+	if err != nil {
+		renderError(ctx, err)
+		return false
+	}
+
+	if retryAt.IsZero() {
+		attempt.Reserved = true
+		return true
+	}
+
+	if !s.recordSigninAttempt(ctx, *attempt, db.SigninThrottled) {
+		return false
+	}
+
+	ctx.Header("Retry-After", fmt.Sprintf("%.0f", math.Ceil(time.Until(retryAt).Seconds())))
+	renderError(ctx, util.ErrSigninThrottled)
+	return false
+}
+
+// recordSigninAttempt writes the attempt to the auth audit and counts bad
+// credentials towards a lockout. A sign-in that cannot be recorded fails,
+// otherwise guesses would go uncounted.
+func (s *Server) recordSigninAttempt(ctx *gin.Context, attempt db.RecordSigninAttemptTxParams, outcome string) bool {
+	attempt.Outcome = outcome
+
+	if _, err := s.store.RecordSigninAttemptTx(ctx, attempt); err != nil {
+		renderError(ctx, err)
+		return false
+	}
+
+	return true
+}
+
+type UnlockUserUri struct {
+	ID int64 `uri:"id" binding:"min=1"`
+}
+
+// unlockUser lifts a user's sign-in lockout before it runs out. Resetting
+// the password does the same for the user themselves.
+func (s *Server) unlockUser(ctx *gin.Context) {
+	var uri UnlockUserUri
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	if err := s.store.DeleteSigninThrottle(ctx, db.SigninThrottleUserKey(uri.ID)); err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(nil, "user sign-in unlocked"))
+}
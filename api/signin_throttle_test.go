@@ -0,0 +1,226 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type eqSigninAttempt struct {
+	userID  int64
+	outcome string
+}
+
+func (e eqSigninAttempt) Matches(x interface{}) bool {
+	arg, ok := x.(db.RecordSigninAttemptTxParams)
+
+	if !ok {
+		return false
+	}
+
+	// Attempts let through the throttle are reserved.
+	return arg.UserID == e.userID && arg.Outcome == e.outcome && arg.Reserved == (e.outcome != db.SigninThrottled)
+}
+
+func (e eqSigninAttempt) String() string {
+	return fmt.Sprintf("sign-in attempt of user %d with outcome %s", e.userID, e.outcome)
+}
+
+// expectSigninAttempt lets the sign-in past the throttle and expects it to
+// be recorded with the given outcome.
+func expectSigninAttempt(store *mockdb.MockStore, userID int64, outcome string) {
+	store.EXPECT().ReserveSigninAttemptTx(gomock.Any(), gomock.Any()).Times(1).Return(time.Time{}, nil)
+	store.EXPECT().
+		RecordSigninAttemptTx(gomock.Any(), eqSigninAttempt{userID: userID, outcome: outcome}).
+		Times(1).
+		Return(&db.AuthAudit{}, nil)
+}
+
+func TestSigninThrottle(t *testing.T) {
+	user, p := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Throttled",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					ReserveSigninAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.SigninRetryAtParams) (time.Time, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						return time.Now().Add(90 * time.Second), nil
+					})
+				store.EXPECT().
+					RecordSigninAttemptTx(gomock.Any(), eqSigninAttempt{userID: user.ID, outcome: db.SigninThrottled}).
+					Times(1).
+					Return(&db.AuthAudit{}, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.Contains(t, recorder.Body.String(), util.ErrSigninThrottled.Error())
+
+				retryAfter, err := strconv.Atoi(recorder.Header().Get("Retry-After"))
+				require.NoError(t, err)
+				require.InDelta(t, 90, retryAfter, 1)
+			},
+		},
+		{
+			name: "UnknownUser",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, pgx.ErrNoRows)
+				expectSigninAttempt(store, 0, db.SigninBadCredentials)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "RecordFailed",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().ReserveSigninAttemptTx(gomock.Any(), gomock.Any()).Times(1).Return(time.Time{}, nil)
+				store.EXPECT().RecordSigninAttemptTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, pgx.ErrTxClosed)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(gin.H{"id": user.Email, "password": p})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/auth/sign-in", bytes.NewBuffer(b))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestUnlockUserAPI(t *testing.T) {
+	admin, _ := randomUser(t)
+	admin.Role = util.AdminRole
+
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		requester     db.User
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			requester: admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().
+					DeleteSigninThrottle(gomock.Any(), gomock.Eq(db.SigninThrottleUserKey(user.ID))).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "NotAdmin",
+			requester: user,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().DeleteSigninThrottle(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/admin/users/%d/unlock", user.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.requester.ID, tc.requester.Email, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSigninIgnoresForwardedFor(t *testing.T) {
+	user, p := randomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+	store.EXPECT().
+		ReserveSigninAttemptTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg db.SigninRetryAtParams) (time.Time, error) {
+			require.Equal(t, "203.0.113.7", arg.ClientIP)
+			return time.Now().Add(time.Minute), nil
+		})
+	store.EXPECT().RecordSigninAttemptTx(gomock.Any(), gomock.Any()).Times(1).Return(&db.AuthAudit{}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	b, err := json.Marshal(gin.H{"id": user.Email, "password": p})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/auth/sign-in", bytes.NewBuffer(b))
+	require.NoError(t, err)
+
+	request.RemoteAddr = "203.0.113.7:51234"
+	request.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+}
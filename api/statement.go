@@ -0,0 +1,105 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/statement"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type GetStatementUri struct {
+	ID int64 `uri:"id" binding:"min=1"`
+}
+
+type GetStatementQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv ndjson pdf"`
+	From   string `form:"from" binding:"required,datetime=2006-01-02"`
+	To     string `form:"to" binding:"required,datetime=2006-01-02"`
+}
+
+func (s *Server) getAccountStatement(ctx *gin.Context) {
+	var uri GetStatementUri
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	var req GetStatementQuery
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = string(statement.CSV)
+	}
+
+	from, _ := time.Parse(time.DateOnly, req.From)
+	to, _ := time.Parse(time.DateOnly, req.To)
+
+	if to.Before(from) {
+		renderError(ctx, util.ErrInvalidArgument.WithViolations(util.FieldViolation{
+			Field:       "to",
+			Description: "must not be before from",
+		}))
+		return
+	}
+
+	account, err := s.store.GetAccountByID(ctx, uri.ID)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			renderError(ctx, db.ErrAccountNotFound)
+			return
+		}
+
+		renderError(ctx, err)
+		return
+	}
+
+	authUser, ok := Auth(ctx)
+
+	if !ok {
+		return
+	}
+
+	if account.OwnerID != authUser.UserId {
+		renderError(ctx, util.ErrNotAuthorized)
+		return
+	}
+
+	format := statement.Format(req.Format)
+	w, err := statement.NewWriter(format, ctx.Writer)
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	filename := fmt.Sprintf("statement-%d-%s-%s.%s", account.ID, req.From, req.To, format)
+	ctx.Header("Content-Type", format.ContentType())
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	ctx.Status(http.StatusOK)
+
+	if err := statement.Generate(ctx, s.store, w, account, from, to); err != nil {
+		if !ctx.Writer.Written() {
+			ctx.Writer.Header().Del("Content-Type")
+			ctx.Writer.Header().Del("Content-Disposition")
+			renderError(ctx, err)
+			return
+		}
+
+		// Once streaming has started the status line is out, so a
+		// failure can only cut the body short.
+		util.Logger(ctx).Error("statement aborted", "account_id", account.ID, "error", err)
+		ctx.Abort()
+	}
+}
@@ -0,0 +1,185 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetAccountStatementAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	account := randomAccount(user.ID)
+
+	entries := []db.GetStatementEntriesRow{
+		{
+			ID:            1,
+			Amount:        25,
+			Kind:          db.EntryKindTransfer,
+			CreatedAt:     pgtype.Timestamptz{Time: time.Date(2024, 3, 2, 10, 0, 0, 0, time.UTC), Valid: true},
+			TransferID:    pgtype.Int8{Int64: 11, Valid: true},
+			FromAccountID: pgtype.Int8{Int64: account.ID + 1, Valid: true},
+			ToAccountID:   pgtype.Int8{Int64: account.ID, Valid: true},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "CSV",
+			userID: user.ID,
+			query:  "from=2024-03-01&to=2024-03-31",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().
+					GetAccountBalanceAt(gomock.Any(), gomock.Eq(db.GetAccountBalanceAtParams{
+						ID: account.ID,
+						At: pgtype.Timestamptz{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+					})).
+					Times(1).
+					Return(float64(100), nil)
+				store.EXPECT().
+					GetStatementEntries(gomock.Any(), gomock.Eq(db.GetStatementEntriesParams{
+						AccountID: pgtype.Int8{Int64: account.ID, Valid: true},
+						From:      pgtype.Timestamptz{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+						To:        pgtype.Timestamptz{Time: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+						Limit:     500,
+					})).
+					Times(1).
+					Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), "statement-")
+
+				body := recorder.Body.String()
+				require.Contains(t, body, "Opening balance,,100.00")
+				require.Contains(t, body, fmt.Sprintf("Transfer from account #%d,25.00,125.00", account.ID+1))
+				require.True(t, strings.HasSuffix(body, "Closing balance,,125.00\n"))
+			},
+		},
+		{
+			name:   "PDF",
+			userID: user.ID,
+			query:  "format=pdf&from=2024-03-01&to=2024-03-31",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountBalanceAt(gomock.Any(), gomock.Any()).Times(1).Return(float64(0), nil)
+				store.EXPECT().GetStatementEntries(gomock.Any(), gomock.Any()).Times(1).Return(entries, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/pdf", recorder.Header().Get("Content-Type"))
+				require.True(t, strings.HasPrefix(recorder.Body.String(), "%PDF-"))
+			},
+		},
+		{
+			name:   "UnknownFormat",
+			userID: user.ID,
+			query:  "format=xlsx&from=2024-03-01&to=2024-03-31",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "ToBeforeFrom",
+			userID: user.ID,
+			query:  "from=2024-03-31&to=2024-03-01",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingRange",
+			userID: user.ID,
+			query:  "format=csv",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "NotOwner",
+			userID: user.ID + 1,
+			query:  "from=2024-03-01&to=2024-03-31",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetStatementEntries(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "AccountNotFound",
+			userID: user.ID,
+			query:  "from=2024-03-01&to=2024-03-31",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(db.Account{}, pgx.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "FailsBeforeStreaming",
+			userID: user.ID,
+			query:  "from=2024-03-01&to=2024-03-31",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Eq(account.ID)).Times(1).Return(account, nil)
+				store.EXPECT().GetAccountBalanceAt(gomock.Any(), gomock.Any()).Times(1).Return(float64(0), errors.New("connection reset"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			expectAuthUser(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/accounts/%d/statement?%s", account.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, user.Email, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const stepUpTokenHeaderKey = "x-step-up-token"
+
+var (
+	ErrStepUpRequired         = util.NewCustomError(util.KindPermissionDenied, "ErrStepUpRequired", "step-up authentication required")
+	ErrStepUpTokenInvalid     = util.NewCustomError(util.KindPermissionDenied, "ErrStepUpTokenInvalid", "step-up token is invalid, expired or issued for another request")
+	ErrStepUpChallengeInvalid = util.NewCustomError(util.KindInvalidArgument, "ErrStepUpChallengeInvalid", "step-up challenge is invalid or already used")
+	ErrStepUpChallengeExpired = util.NewCustomError(util.KindExpired, "ErrStepUpChallengeExpired", "step-up challenge has expired")
+	ErrStepUpFailed           = util.NewCustomError(util.KindPermissionDenied, "ErrStepUpFailed", "re-authentication failed")
+)
+
+// Methods a step-up challenge can be answered with.
+const (
+	stepUpMethodPassword = "password"
+	stepUpMethodTOTP     = "totp"
+)
+
+// stepUpRequiredDetails tells the client which challenge to answer, see
+// stepUp.
+type stepUpRequiredDetails struct {
+	ChallengeID string    `json:"challenge_id"`
+	Methods     []string  `json:"methods"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// requireStepUp guards actions on amounts above the step-up threshold. The
+// request proceeds when it carries a step-up token issued for exactly this
+// action and parameters, which is spent. Otherwise a new challenge is
+// raised and reported, and requireStepUp returns false.
+func (s *Server) requireStepUp(ctx *gin.Context, action string, amount float64, paramsHash string) bool {
+	if s.config.StepUpThreshold <= 0 || amount <= s.config.StepUpThreshold {
+		return true
+	}
+
+	user, ok := AuthUser(ctx)
+
+	if !ok {
+		return false
+	}
+
+	reason := ErrStepUpRequired
+
+	if stepUpToken := ctx.GetHeader(stepUpTokenHeaderKey); stepUpToken != "" {
+		_, err := s.store.ConsumeStepUpToken(ctx, db.ConsumeStepUpTokenParams{
+			TokenHash:  util.HashSecretToken(stepUpToken),
+			UserID:     user.ID,
+			Action:     action,
+			ParamsHash: paramsHash,
+		})
+
+		if err == nil {
+			return true
+		}
+
+		if !errors.Is(err, pgx.ErrNoRows) {
+			renderError(ctx, err)
+			return false
+		}
+
+		reason = ErrStepUpTokenInvalid
+	}
+
+	challenge, err := s.store.CreateStepUpChallenge(ctx, db.CreateStepUpChallengeParams{
+		ID:         pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		UserID:     user.ID,
+		Action:     action,
+		ParamsHash: paramsHash,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(s.config.StepUpTTL),
+			Valid: true,
+		},
+	})
+
+	if err != nil {
+		renderError(ctx, err)
+		return false
+	}
+
+	methods := []string{stepUpMethodPassword}
+	if user.IsTotpEnabled {
+		methods = append(methods, stepUpMethodTOTP)
+	}
+
+	renderErrorDetails(ctx, reason, stepUpRequiredDetails{
+		ChallengeID: uuid.UUID(challenge.ID.Bytes).String(),
+		Methods:     methods,
+		ExpiresAt:   challenge.ExpiresAt.Time,
+	})
+
+	return false
+}
+
+type StepUpRequest struct {
+	ChallengeID string `json:"challenge_id" binding:"required,uuid"`
+	Password    string `json:"password" binding:"required_without=Code"`
+	Code        string `json:"code" binding:"required_without=Password"`
+}
+
+type stepUpResponse struct {
+	StepUpToken string    `json:"step_up_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// stepUp answers a step-up challenge with the user's password or a 2FA
+// code. The token it returns is sent back in the X-Step-Up-Token header of
+// the request that raised the challenge.
+func (s *Server) stepUp(ctx *gin.Context) {
+	var req StepUpRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	user, ok := AuthUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	challengeID := pgtype.UUID{Bytes: uuid.MustParse(req.ChallengeID), Valid: true}
+
+	challenge, err := s.store.GetStepUpChallenge(ctx, challengeID)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			renderError(ctx, ErrStepUpChallengeInvalid)
+			return
+		}
+
+		renderError(ctx, err)
+		return
+	}
+
+	if challenge.UserID != user.ID || challenge.VerifiedAt.Valid || challenge.Attempts >= db.MaxStepUpAttempts {
+		renderError(ctx, ErrStepUpChallengeInvalid)
+		return
+	}
+
+	if time.Now().After(challenge.ExpiresAt.Time) {
+		renderError(ctx, ErrStepUpChallengeExpired)
+		return
+	}
+
+	if req.Password == "" && !user.IsTotpEnabled {
+		renderError(ctx, db.ErrTwoFactorDisabled)
+		return
+	}
+
+	_, err = s.store.TakeStepUpAttempt(ctx, db.TakeStepUpAttemptParams{
+		ID:          challengeID,
+		MaxAttempts: db.MaxStepUpAttempts,
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			renderError(ctx, ErrStepUpChallengeInvalid)
+			return
+		}
+
+		renderError(ctx, err)
+		return
+	}
+
+	// Wrong answers count as failed sign-ins, so raising challenges afresh
+	// does not make for unlimited guesses, and a locked out user cannot
+	// step up either.
+	attempt := db.RecordSigninAttemptTxParams{
+		UserID:     user.ID,
+		Identifier: user.Username,
+		ClientIP:   ctx.ClientIP(),
+		UserAgent:  ctx.Request.UserAgent(),
+		Policy:     db.NewSigninPolicy(s.config),
+	}
+
+	if !s.checkSigninThrottle(ctx, &attempt) {
+		return
+	}
+
+	if req.Password != "" {
+		err = checkPassword(user, req.Password)
+	} else {
+		err = s.store.VerifySecondFactorTx(ctx, db.VerifySecondFactorTxParams{UserID: user.ID, Code: req.Code})
+
+		if err != nil && !errors.Is(err, db.ErrTwoFactorCodeInvalid) {
+			renderError(ctx, err)
+			return
+		}
+	}
+
+	if err != nil {
+		if s.recordSigninAttempt(ctx, attempt, db.SigninBadCredentials) {
+			renderError(ctx, ErrStepUpFailed)
+		}
+		return
+	}
+
+	if !s.recordSigninAttempt(ctx, attempt, db.SigninSteppedUp) {
+		return
+	}
+
+	stepUpToken, tokenHash, err := util.NewSecretToken()
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	challenge, err = s.store.IssueStepUpToken(ctx, db.IssueStepUpTokenParams{
+		ID:        challengeID,
+		TokenHash: tokenHash,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(s.config.StepUpTTL),
+			Valid: true,
+		},
+		MaxAttempts: db.MaxStepUpAttempts,
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			renderError(ctx, ErrStepUpChallengeInvalid)
+			return
+		}
+
+		renderError(ctx, err)
+		return
+	}
+
+	response := stepUpResponse{
+		StepUpToken: stepUpToken,
+		ExpiresAt:   challenge.ExpiresAt.Time,
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(response, "re-authenticated, retry the request with the step-up token"))
+}
@@ -0,0 +1,379 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferStepUp(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := randomAccount(user1.ID)
+	account2 := randomAccount(user2.ID)
+	account1.Currency = string(util.USD)
+	account2.Currency = string(util.USD)
+
+	stepUpToken, tokenHash, err := util.NewSecretToken()
+	require.NoError(t, err)
+
+	paramsHash := db.TransferStepUpParams(account1.ID, account2.ID, 500, string(util.USD))
+
+	testCases := []struct {
+		name          string
+		amount        float64
+		stepUpToken   string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "BelowThreshold",
+			amount: 100,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateStepUpChallenge(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "StepUpRequired",
+			amount: 500,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateStepUpChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateStepUpChallengeParams) (db.StepUpChallenge, error) {
+						require.Equal(t, user1.ID, arg.UserID)
+						require.Equal(t, db.StepUpActionTransfer, arg.Action)
+						require.Equal(t, paramsHash, arg.ParamsHash)
+						return db.StepUpChallenge{ID: arg.ID, ExpiresAt: arg.ExpiresAt}, nil
+					})
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+
+				var rsp struct {
+					Error struct {
+						Code    string                `json:"code"`
+						Details stepUpRequiredDetails `json:"details"`
+					} `json:"error"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, ErrStepUpRequired.Code(), rsp.Error.Code)
+				require.Equal(t, []string{stepUpMethodPassword}, rsp.Error.Details.Methods)
+
+				_, err := uuid.Parse(rsp.Error.Details.ChallengeID)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:        "ValidStepUpToken",
+			amount:      500,
+			stepUpToken: stepUpToken,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ConsumeStepUpToken(gomock.Any(), gomock.Eq(db.ConsumeStepUpTokenParams{
+						TokenHash:  tokenHash,
+						UserID:     user1.ID,
+						Action:     db.StepUpActionTransfer,
+						ParamsHash: paramsHash,
+					})).
+					Times(1)
+				store.EXPECT().CreateStepUpChallenge(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "SpentOrMismatchedToken",
+			amount:      500,
+			stepUpToken: stepUpToken,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ConsumeStepUpToken(gomock.Any(), gomock.Any()).Times(1).Return(db.StepUpChallenge{}, pgx.ErrNoRows)
+				store.EXPECT().
+					CreateStepUpChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateStepUpChallengeParams) (db.StepUpChallenge, error) {
+						return db.StepUpChallenge{ID: arg.ID, ExpiresAt: arg.ExpiresAt}, nil
+					})
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), ErrStepUpTokenInvalid.Error())
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user1, nil)
+			store.EXPECT().GetAccountByID(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+			store.EXPECT().GetAccountByID(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.StepUpThreshold = 200
+			server.config.StepUpTTL = time.Minute
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(TransferRequest{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        tc.amount,
+				Currency:      string(util.USD),
+			})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/transfer", bytes.NewBuffer(b))
+			require.NoError(t, err)
+
+			if tc.stepUpToken != "" {
+				request.Header.Set(stepUpTokenHeaderKey, tc.stepUpToken)
+			}
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user1.ID, user1.Email, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestStepUpAPI(t *testing.T) {
+	user, password := randomUser(t)
+	other, _ := randomUser(t)
+
+	challengeID := pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	challenge := db.StepUpChallenge{
+		ID:        challengeID,
+		UserID:    user.ID,
+		Action:    db.StepUpActionTransfer,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	}
+
+	issue := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			IssueStepUpToken(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ any, arg db.IssueStepUpTokenParams) (db.StepUpChallenge, error) {
+				require.Equal(t, challengeID, arg.ID)
+				require.Equal(t, int32(db.MaxStepUpAttempts), arg.MaxAttempts)
+
+				issued := challenge
+				issued.ExpiresAt = arg.ExpiresAt
+				return issued, nil
+			})
+	}
+
+	take := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			TakeStepUpAttempt(gomock.Any(), gomock.Eq(db.TakeStepUpAttemptParams{ID: challengeID, MaxAttempts: db.MaxStepUpAttempts})).
+			Times(1).
+			Return(challenge, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		twoFactor     bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Password",
+			body: gin.H{"challenge_id": uuid.UUID(challengeID.Bytes).String(), "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(challenge, nil)
+				take(store)
+				expectSigninAttempt(store, user.ID, db.SigninSteppedUp)
+				issue(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data stepUpResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.Data.StepUpToken)
+			},
+		},
+		{
+			name:      "TOTPCode",
+			body:      gin.H{"challenge_id": uuid.UUID(challengeID.Bytes).String(), "code": "123456"},
+			twoFactor: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(challenge, nil)
+				take(store)
+				expectSigninAttempt(store, user.ID, db.SigninSteppedUp)
+				store.EXPECT().
+					VerifySecondFactorTx(gomock.Any(), gomock.Eq(db.VerifySecondFactorTxParams{UserID: user.ID, Code: "123456"})).
+					Times(1).
+					Return(nil)
+				issue(store)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{"challenge_id": uuid.UUID(challengeID.Bytes).String(), "password": "wrong-password"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(challenge, nil)
+				take(store)
+				expectSigninAttempt(store, user.ID, db.SigninBadCredentials)
+				store.EXPECT().IssueStepUpToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "WrongCode",
+			body:      gin.H{"challenge_id": uuid.UUID(challengeID.Bytes).String(), "code": "000000"},
+			twoFactor: true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(challenge, nil)
+				take(store)
+				expectSigninAttempt(store, user.ID, db.SigninBadCredentials)
+				store.EXPECT().VerifySecondFactorTx(gomock.Any(), gomock.Any()).Times(1).Return(db.ErrTwoFactorCodeInvalid)
+				store.EXPECT().IssueStepUpToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "LockedOut",
+			body: gin.H{"challenge_id": uuid.UUID(challengeID.Bytes).String(), "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(challenge, nil)
+				take(store)
+				store.EXPECT().ReserveSigninAttemptTx(gomock.Any(), gomock.Any()).Times(1).Return(time.Now().Add(time.Minute), nil)
+				store.EXPECT().
+					RecordSigninAttemptTx(gomock.Any(), eqSigninAttempt{userID: user.ID, outcome: db.SigninThrottled}).
+					Times(1).
+					Return(&db.AuthAudit{}, nil)
+				store.EXPECT().IssueStepUpToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
+		{
+			name: "AttemptsUsedUp",
+			body: gin.H{"challenge_id": uuid.UUID(challengeID.Bytes).String(), "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				// Taken by answers in parallel with this one.
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(challenge, nil)
+				store.EXPECT().TakeStepUpAttempt(gomock.Any(), gomock.Any()).Times(1).Return(db.StepUpChallenge{}, pgx.ErrNoRows)
+				store.EXPECT().ReserveSigninAttemptTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TwoFactorDisabled",
+			body: gin.H{"challenge_id": uuid.UUID(challengeID.Bytes).String(), "code": "123456"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(challenge, nil)
+				store.EXPECT().TakeStepUpAttempt(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().VerifySecondFactorTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "OtherUsersChallenge",
+			body: gin.H{"challenge_id": uuid.UUID(challengeID.Bytes).String(), "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				theirs := challenge
+				theirs.UserID = other.ID
+
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(theirs, nil)
+				store.EXPECT().IssueStepUpToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredChallenge",
+			body: gin.H{"challenge_id": uuid.UUID(challengeID.Bytes).String(), "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				expired := challenge
+				expired.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Second), Valid: true}
+
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Eq(challengeID)).Times(1).Return(expired, nil)
+				store.EXPECT().IssueStepUpToken(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusGone, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCredential",
+			body: gin.H{"challenge_id": uuid.UUID(challengeID.Bytes).String()},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStepUpChallenge(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			requester := user
+			requester.IsTotpEnabled = tc.twoFactor
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(requester, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.StepUpTTL = time.Minute
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/auth/step-up", bytes.NewBuffer(b))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"context"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutMiddleware gives every request the deadline timeouts sets for its
+// route. Handlers pass the request context on to the store, so queries
+// still running when it passes are cancelled and the request fails
+// instead of holding a connection.
+func TimeoutMiddleware(timeouts util.RouteTimeouts) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		timeout := timeouts.For(ctx.Request.Method + " " + ctx.FullPath())
+
+		if timeout <= 0 {
+			ctx.Next()
+			return
+		}
+
+		reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Next()
+	}
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	router := gin.New()
+	router.ContextWithFallback = true
+	router.Use(TimeoutMiddleware(util.RouteTimeouts{"GET /slow": 20 * time.Millisecond}))
+
+	router.GET("/slow", func(ctx *gin.Context) {
+		// What a query given the handler's context runs into.
+		<-ctx.Done()
+		renderError(ctx, ctx.Err())
+	})
+
+	router.GET("/fast", func(ctx *gin.Context) {
+		_, ok := ctx.Deadline()
+		require.False(t, ok)
+		ctx.Status(http.StatusNoContent)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	require.Equal(t, http.StatusGatewayTimeout, recorder.Code)
+	require.Contains(t, recorder.Body.String(), util.ErrDeadlineExceeded.Code())
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+	require.Equal(t, http.StatusNoContent, recorder.Code)
+}
@@ -7,11 +7,18 @@ import (
 
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
 	"github.com/devphasex/cedar-bank-api/token"
+	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+var (
+	ErrSessionInvalid = util.NewCustomError(util.KindUnauthenticated, "ErrSessionInvalid", "invalid session")
+	ErrSessionBlocked = util.NewCustomError(util.KindUnauthenticated, "ErrSessionBlocked", "session revoked")
+	ErrSessionExpired = util.NewCustomError(util.KindUnauthenticated, "ErrSessionExpired", "session expired")
+)
+
 type renewAccessTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
@@ -25,7 +32,7 @@ func (s *Server) renewAccessToken(ctx *gin.Context) {
 	var req renewAccessTokenRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, prettyValidateError(err))
+		renderError(ctx, validationError(err))
 		return
 	}
 
@@ -38,38 +45,39 @@ func (s *Server) renewAccessToken(ctx *gin.Context) {
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("invalid session")))
+			renderError(ctx, ErrSessionInvalid)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		renderError(ctx, err)
 		return
 	}
 
+	// Sessions are blocked when the user changes their password.
 	if session.IsBlocked.Bool {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(errors.New("invalid session")))
+		renderError(ctx, ErrSessionBlocked)
 		return
 	}
 
-	payload, err := s.tokenMaker.VerifyToken(session.RefreshToken)
-
-	if payload.UserId != session.OwnerID {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(errors.New("invalid session")))
-		return
-	}
+	payload, err := s.authMaker.VerifyToken(session.RefreshToken)
 
 	if err != nil {
 		if errors.Is(err, token.ErrExpiredToken) {
-			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("session expired")))
+			renderError(ctx, ErrSessionExpired)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(errors.New("failed to refresh token")))
+		renderError(ctx, ErrSessionInvalid)
+		return
+	}
+
+	if payload.UserId != session.OwnerID {
+		renderError(ctx, ErrSessionInvalid)
 		return
 	}
 
 	if time.Now().After(session.ExpiredAt.Time) {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("session expired")))
+		renderError(ctx, ErrSessionExpired)
 		return
 	}
 
@@ -82,11 +90,11 @@ func (s *Server) renewAccessToken(ctx *gin.Context) {
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("session expired")))
+			renderError(ctx, ErrSessionExpired)
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		renderError(ctx, err)
 
 		return
 	}
@@ -94,7 +102,7 @@ func (s *Server) renewAccessToken(ctx *gin.Context) {
 	accessTokenStr, accessPayload, err := s.tokenMaker.CreateToken(user.ID, user.Email, s.config.AccessTokenTime)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		renderError(ctx, err)
 		return
 	}
 
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/devphasex/cedar-bank-api/api")
+
+// TracingMiddleware starts a span for every request, continuing the trace
+// of the caller if it sent a traceparent header. The span is named after
+// the route rather than the path, so /accounts/1 and /accounts/2 group
+// together. It must come first so every other middleware runs inside it.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reqCtx := otel.GetTextMapPropagator().Extract(ctx.Request.Context(), propagation.HeaderCarrier(ctx.Request.Header))
+
+		route := ctx.FullPath()
+
+		if route == "" {
+			route = "unmatched"
+		}
+
+		reqCtx, span := tracer.Start(reqCtx, fmt.Sprintf("%s %s", ctx.Request.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(ctx.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(ctx.Request.URL.Path),
+			),
+		)
+		defer span.End()
+
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+		if status >= 500 {
+			span.SetStatus(codes.Error, "")
+		}
+
+		if err := ctx.Errors.Last(); err != nil {
+			span.RecordError(err.Err)
+		}
+	}
+}
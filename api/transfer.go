@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
 )
@@ -17,14 +18,13 @@ type TransferRequest struct {
 	Currency      string  `json:"currency" binding:"required,currency"`
 }
 
-var ErrFailed = errors.New("failed")
+var ErrCurrencyMismatch = util.NewCustomError(util.KindPermissionDenied, "ErrCurrencyMismatch", "account currency does not match the transfer currency")
 
 func (s *Server) createTransfer(ctx *gin.Context) {
 	var req TransferRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		fmt.Println("data error", prettyValidateError(err))
-		ctx.JSON(http.StatusBadRequest, prettyValidateError(err))
+		renderError(ctx, validationError(err))
 		return
 	}
 
@@ -40,9 +40,14 @@ func (s *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
-	authUser := Auth(ctx)
+	authUser, ok := Auth(ctx)
+
+	if !ok {
+		return
+	}
+
 	if authUser.UserId != fromAccount.OwnerID {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("user not authorized")))
+		renderError(ctx, util.ErrNotAuthorized)
 		return
 	}
 
@@ -52,20 +57,16 @@ func (s *Server) createTransfer(ctx *gin.Context) {
 		return
 	}
 
-	tx, err := s.store.TransferTx(ctx, arg)
+	paramsHash := db.TransferStepUpParams(req.FromAccountID, req.ToAccountID, req.Amount, req.Currency)
 
-	if err != nil {
-		if errors.Is(err, db.ErrAccountNotFound) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
-			return
-		}
+	if !s.requireStepUp(ctx, db.StepUpActionTransfer, req.Amount, paramsHash) {
+		return
+	}
 
-		if errors.Is(err, db.ErrFundNotSufficient) {
-			ctx.JSON(http.StatusForbidden, errorResponse(err))
-			return
-		}
+	tx, err := s.store.TransferTx(ctx, arg)
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+	if err != nil {
+		renderError(ctx, err)
 		return
 	}
 
@@ -77,21 +78,74 @@ func (s *Server) validateAccount(ctx *gin.Context, accountID int64, currency str
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("account [%d] not found", accountID)))
+			renderError(ctx, db.ErrAccountNotFound)
 			return account, false
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		renderError(ctx, err)
 		return account, false
 	}
 
 	if account.Currency != currency {
-		ctx.JSON(http.StatusForbidden, errorResponse(
-			fmt.Errorf("account [%d] currency mismatch: expected %v but got %v",
-				accountID, account.Currency, currency)),
-		)
+		renderError(ctx, ErrCurrencyMismatch.WithViolations(util.FieldViolation{
+			Field:       "currency",
+			Description: fmt.Sprintf("account %d holds %s, not %s", accountID, account.Currency, currency),
+		}))
 		return account, false
 	}
 
 	return account, true
 }
+
+type transferQuoteResponse struct {
+	FromAccountID int64 `json:"from_account_id"`
+	ToAccountID   int64 `json:"to_account_id"`
+	*db.TransferFeeQuote
+}
+
+func (s *Server) quoteTransfer(ctx *gin.Context) {
+	var req TransferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	fromAccount, valid := s.validateAccount(ctx, req.FromAccountID, req.Currency)
+
+	if !valid {
+		return
+	}
+
+	authUser, ok := Auth(ctx)
+
+	if !ok {
+		return
+	}
+
+	if authUser.UserId != fromAccount.OwnerID {
+		renderError(ctx, util.ErrNotAuthorized)
+		return
+	}
+
+	_, valid = s.validateAccount(ctx, req.ToAccountID, req.Currency)
+
+	if !valid {
+		return
+	}
+
+	quote, err := s.store.QuoteTransferFee(ctx, fromAccount.Currency, req.Amount)
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	response := transferQuoteResponse{
+		FromAccountID:    req.FromAccountID,
+		ToAccountID:      req.ToAccountID,
+		TransferFeeQuote: quote,
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(response))
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+type AuthorizeTransferRequest struct {
+	FromAccountID int64   `json:"from_account_id" binding:"required"`
+	ToAccountID   int64   `json:"to_account_id" binding:"required"`
+	Amount        float64 `json:"amount" binding:"required,gt=0"`
+	Currency      string  `json:"currency" binding:"required,currency"`
+	// TTLSeconds overrides the default hold lifetime, up to the configured maximum.
+	TTLSeconds int64 `json:"ttl_seconds" binding:"min=0"`
+}
+
+type TransferHoldUri struct {
+	ID int64 `uri:"id" binding:"min=1"`
+}
+
+func (s *Server) authorizeTransfer(ctx *gin.Context) {
+	var req AuthorizeTransferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	ttl := s.config.TransferHoldTTL
+	if req.TTLSeconds > 0 {
+		ttl = time.Duration(req.TTLSeconds) * time.Second
+	}
+
+	if ttl > s.config.TransferHoldTTL {
+		renderError(ctx, util.ErrInvalidArgument.WithViolations(util.FieldViolation{
+			Field:       "ttl_seconds",
+			Description: fmt.Sprintf("must not exceed %.0f", s.config.TransferHoldTTL.Seconds()),
+		}))
+		return
+	}
+
+	fromAccount, valid := s.validateAccount(ctx, req.FromAccountID, req.Currency)
+
+	if !valid {
+		return
+	}
+
+	authUser, ok := Auth(ctx)
+
+	if !ok {
+		return
+	}
+
+	if authUser.UserId != fromAccount.OwnerID {
+		renderError(ctx, util.ErrNotAuthorized)
+		return
+	}
+
+	_, valid = s.validateAccount(ctx, req.ToAccountID, req.Currency)
+
+	if !valid {
+		return
+	}
+
+	paramsHash := db.TransferStepUpParams(req.FromAccountID, req.ToAccountID, req.Amount, req.Currency)
+
+	if !s.requireStepUp(ctx, db.StepUpActionTransferHold, req.Amount, paramsHash) {
+		return
+	}
+
+	hold, err := s.store.AuthorizeTransferTx(ctx, db.AuthorizeTransferTxParams{
+		FromAccountID: req.FromAccountID,
+		ToAccountID:   req.ToAccountID,
+		Amount:        req.Amount,
+		TTL:           ttl,
+	})
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, sucessResponse(hold, "transfer authorized"))
+}
+
+func (s *Server) getTransferHold(ctx *gin.Context) {
+	hold, ok := s.authorizedHold(ctx)
+
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(hold))
+}
+
+func (s *Server) captureTransferHold(ctx *gin.Context) {
+	hold, ok := s.authorizedHold(ctx)
+
+	if !ok {
+		return
+	}
+
+	result, err := s.store.CaptureTransferHoldTx(ctx, hold.ID)
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(result, "transfer captured"))
+}
+
+func (s *Server) voidTransferHold(ctx *gin.Context) {
+	hold, ok := s.authorizedHold(ctx)
+
+	if !ok {
+		return
+	}
+
+	voided, err := s.store.VoidTransferHoldTx(ctx, hold.ID)
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(voided, "transfer hold voided"))
+}
+
+// authorizedHold loads the hold named in the uri and checks that the
+// caller owns the account the funds are held on.
+func (s *Server) authorizedHold(ctx *gin.Context) (db.TransferHold, bool) {
+	var uri TransferHoldUri
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderError(ctx, validationError(err))
+		return db.TransferHold{}, false
+	}
+
+	hold, err := s.store.GetTransferHold(ctx, uri.ID)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			renderError(ctx, db.ErrHoldNotFound)
+			return hold, false
+		}
+
+		renderError(ctx, err)
+		return hold, false
+	}
+
+	account, err := s.store.GetAccountByID(ctx, hold.FromAccountID)
+
+	if err != nil {
+		renderError(ctx, err)
+		return hold, false
+	}
+
+	authUser, ok := Auth(ctx)
+
+	if !ok {
+		return hold, false
+	}
+
+	if account.OwnerID != authUser.UserId {
+		renderError(ctx, util.ErrNotAuthorized)
+		return hold, false
+	}
+
+	return hold, true
+}
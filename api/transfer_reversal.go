@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/gin-gonic/gin"
+)
+
+type ReverseTransferUri struct {
+	ID int64 `uri:"id" binding:"min=1"`
+}
+
+type ReverseTransferRequest struct {
+	// Amount left at zero refunds the rest of the transfer.
+	Amount float64 `json:"amount" binding:"min=0"`
+	Reason string  `json:"reason" binding:"required,min=3"`
+}
+
+func (s *Server) reverseTransfer(ctx *gin.Context) {
+	var uri ReverseTransferUri
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	var req ReverseTransferRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	authUser, ok := Auth(ctx)
+
+	if !ok {
+		return
+	}
+
+	result, err := s.store.ReverseTransferTx(ctx, db.ReverseTransferTxParams{
+		TransferID: uri.ID,
+		Amount:     req.Amount,
+		Reason:     req.Reason,
+		ReversedBy: authUser.UserId,
+	})
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(result, "transfer reversed successfully"))
+}
+
+func (s *Server) getTransferReversals(ctx *gin.Context) {
+	var uri ReverseTransferUri
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	reversals, err := s.store.GetTransferReversals(ctx, uri.ID)
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(reversals))
+}
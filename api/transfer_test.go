@@ -102,6 +102,7 @@ func TestTransferTx(t *testing.T) {
 
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
+			expectAuthUser(store)
 
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
@@ -126,3 +127,92 @@ func TestTransferTx(t *testing.T) {
 	}
 
 }
+
+func TestQuoteTransferAPI(t *testing.T) {
+	user1, _ := randomUser(t)
+	user2, _ := randomUser(t)
+
+	account1 := randomAccount(user1.ID)
+	account2 := randomAccount(user2.ID)
+
+	account1.Currency = string(util.USD)
+	account2.Currency = string(util.USD)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user1.ID, user1.Email, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Eq(account2.ID)).Times(1).Return(account2, nil)
+				store.EXPECT().
+					QuoteTransferFee(gomock.Any(), gomock.Eq(account1.Currency), gomock.Eq(float64(20))).
+					Times(1).
+					Return(&db.TransferFeeQuote{Amount: 20, Fee: 0.5, Total: 20.5, Currency: account1.Currency}, nil)
+				store.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var resp struct {
+					Data transferQuoteResponse `json:"data"`
+				}
+				require.NoError(t, json.NewDecoder(recorder.Body).Decode(&resp))
+				require.Equal(t, 0.5, resp.Data.Fee)
+				require.Equal(t, 20.5, resp.Data.Total)
+			},
+		},
+		{
+			name: "UnauthorizedAccountUser",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user2.ID, user2.Email, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetAccountByID(gomock.Any(), gomock.Eq(account1.ID)).Times(1).Return(account1, nil)
+				store.EXPECT().QuoteTransferFee(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+			expectAuthUser(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(TransferRequest{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        20,
+				Currency:      string(util.USD),
+			})
+
+			require.NoError(t, err)
+			request, err := http.NewRequest(http.MethodPost, "/transfer/quote", bytes.NewBuffer(b))
+
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/totp"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type signinChallengeResponse struct {
+	TwoFactorRequired  bool      `json:"two_factor_required"`
+	ChallengeToken     string    `json:"challenge_token"`
+	ChallengeExpiredAt time.Time `json:"challenge_expired_at"`
+}
+
+// createSigninChallenge records a password sign-in that still needs its
+// second factor. Only the token hash is stored.
+func (s *Server) createSigninChallenge(ctx context.Context, user db.User) (*signinChallengeResponse, error) {
+	token, tokenHash, err := util.NewSecretToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.store.CreateSigninChallenge(ctx, db.CreateSigninChallengeParams{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(s.config.SigninChallengeTTL),
+			Valid: true,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &signinChallengeResponse{
+		TwoFactorRequired:  true,
+		ChallengeToken:     token,
+		ChallengeExpiredAt: challenge.ExpiresAt.Time,
+	}, nil
+}
+
+type VerifySigninChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// verifySigninChallenge completes a two-factor sign-in. Code is a TOTP code
+// or one of the user's recovery codes.
+func (s *Server) verifySigninChallenge(ctx *gin.Context) {
+	var req VerifySigninChallengeRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	user, err := s.store.CompleteSigninChallengeTx(ctx, db.CompleteSigninChallengeTxParams{
+		TokenHash: util.HashSecretToken(req.ChallengeToken),
+		Code:      req.Code,
+		Policy:    db.NewSigninPolicy(s.config),
+	})
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	response, err := s.createSession(ctx, *user)
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(response, "account signin successfully"))
+}
+
+type enrollTOTPResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// enrollTOTP generates a new secret for the signed in user. Two-factor
+// authentication stays off until confirmTOTP sees a code for it, so an
+// abandoned enrollment locks nobody out.
+func (s *Server) enrollTOTP(ctx *gin.Context) {
+	user, ok := AuthUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	if user.IsTotpEnabled {
+		renderError(ctx, db.ErrTwoFactorEnabled)
+		return
+	}
+
+	secret, err := totp.NewSecret()
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	_, err = s.store.SetUserTOTPSecret(ctx, db.SetUserTOTPSecretParams{
+		ID:         user.ID,
+		TotpSecret: secret,
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			renderError(ctx, db.ErrTwoFactorEnabled)
+			return
+		}
+
+		renderError(ctx, err)
+		return
+	}
+
+	response := enrollTOTPResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, s.config.TOTPIssuer, user.Email),
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(response, "scan the provisioning uri and confirm with a code"))
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type confirmTOTPResponse struct {
+	User          userResponse `json:"user"`
+	RecoveryCodes []string     `json:"recovery_codes"`
+}
+
+func (s *Server) confirmTOTP(ctx *gin.Context) {
+	var req TOTPCodeRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	user, ok := AuthUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	result, err := s.store.EnableTOTPTx(ctx, db.EnableTOTPTxParams{
+		UserID: user.ID,
+		Code:   req.Code,
+	})
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	response := confirmTOTPResponse{
+		User:          newUserResponse(result.User),
+		RecoveryCodes: result.RecoveryCodes,
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(response, "two-factor authentication enabled, store the recovery codes safely"))
+}
+
+type DisableTOTPRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+func (s *Server) disableTOTP(ctx *gin.Context) {
+	var req DisableTOTPRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	user, ok := AuthUser(ctx)
+
+	if !ok {
+		return
+	}
+
+	if err := checkPassword(user, req.Password); err != nil {
+		renderError(ctx, ErrPasswordMismatch)
+		return
+	}
+
+	updated, err := s.store.DisableTOTPTx(ctx, db.DisableTOTPTxParams{
+		UserID: user.ID,
+		Code:   req.Code,
+	})
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(newUserResponse(*updated), "two-factor authentication disabled"))
+}
@@ -0,0 +1,352 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySigninChallengeAPI(t *testing.T) {
+	user, _ := randomUser(t)
+	user.IsTotpEnabled = true
+
+	token, tokenHash, err := util.NewSecretToken()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"challenge_token": token, "code": "123456"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CompleteSigninChallengeTx(gomock.Any(), gomock.Eq(db.CompleteSigninChallengeTxParams{
+						TokenHash: tokenHash,
+						Code:      "123456",
+					})).
+					Times(1).
+					Return(&user, nil)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "access_token")
+			},
+		},
+		{
+			name: "InvalidCode",
+			body: gin.H{"challenge_token": token, "code": "000000"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CompleteSigninChallengeTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, db.ErrChallengeCodeInvalid)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredChallenge",
+			body: gin.H{"challenge_token": token, "code": "123456"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CompleteSigninChallengeTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, db.ErrChallengeExpired)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCode",
+			body: gin.H{"challenge_token": token},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CompleteSigninChallengeTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/auth/2fa/verify", bytes.NewBuffer(b))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestEnrollTOTPAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().
+					SetUserTOTPSecret(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.SetUserTOTPSecretParams) (db.User, error) {
+						require.Equal(t, user.ID, arg.ID)
+						require.Len(t, arg.TotpSecret, 32)
+						return user, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data enrollTOTPResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+
+				u, err := url.Parse(rsp.Data.ProvisioningURI)
+				require.NoError(t, err)
+				require.Equal(t, "otpauth", u.Scheme)
+				require.Equal(t, rsp.Data.Secret, u.Query().Get("secret"))
+				require.Equal(t, "Cedar Bank", u.Query().Get("issuer"))
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			buildStubs: func(store *mockdb.MockStore) {
+				enabled := user
+				enabled.IsTotpEnabled = true
+
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(enabled, nil)
+				store.EXPECT().SetUserTOTPSecret(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "EnabledConcurrently",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+				store.EXPECT().SetUserTOTPSecret(gomock.Any(), gomock.Any()).Times(1).Return(db.User{}, pgx.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/auth/2fa/enroll", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestConfirmTOTPAPI(t *testing.T) {
+	user, _ := randomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"code": "123456"},
+			buildStubs: func(store *mockdb.MockStore) {
+				enabled := user
+				enabled.IsTotpEnabled = true
+
+				store.EXPECT().
+					EnableTOTPTx(gomock.Any(), gomock.Eq(db.EnableTOTPTxParams{UserID: user.ID, Code: "123456"})).
+					Times(1).
+					Return(&db.EnableTOTPTxResult{User: enabled, RecoveryCodes: []string{"abcd-efgh-jkmn-pqrs"}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data confirmTOTPResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.Data.User.IsTotpEnabled)
+				require.Equal(t, []string{"abcd-efgh-jkmn-pqrs"}, rsp.Data.RecoveryCodes)
+			},
+		},
+		{
+			name: "InvalidCode",
+			body: gin.H{"code": "000000"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().EnableTOTPTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, db.ErrTwoFactorCodeInvalid)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotEnrolled",
+			body: gin.H{"code": "123456"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().EnableTOTPTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, db.ErrTwoFactorNotEnrolled)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			body: gin.H{"code": "123456"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().EnableTOTPTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, db.ErrTwoFactorEnabled)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/auth/2fa/confirm", bytes.NewBuffer(b))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDisableTOTPAPI(t *testing.T) {
+	user, password := randomUser(t)
+	user.IsTotpEnabled = true
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"password": password, "code": "abcd-efgh-jkmn-pqrs"},
+			buildStubs: func(store *mockdb.MockStore) {
+				disabled := user
+				disabled.IsTotpEnabled = false
+
+				store.EXPECT().
+					DisableTOTPTx(gomock.Any(), gomock.Eq(db.DisableTOTPTxParams{UserID: user.ID, Code: "abcd-efgh-jkmn-pqrs"})).
+					Times(1).
+					Return(&disabled, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"is_totp_enabled":false`)
+			},
+		},
+		{
+			name: "WrongPassword",
+			body: gin.H{"password": "wrong-password", "code": "123456"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DisableTOTPTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCode",
+			body: gin.H{"password": password, "code": "000000"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().DisableTOTPTx(gomock.Any(), gomock.Any()).Times(1).Return(nil, db.ErrTwoFactorCodeInvalid)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetUserByUniqueID(gomock.Any(), gomock.Any()).Times(1).Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			b, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/auth/2fa/disable", bytes.NewBuffer(b))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, user.Email, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
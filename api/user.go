@@ -2,12 +2,12 @@ package api
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"time"
 
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
-	"github.com/devphasex/cedar-bank-api/util/hash"
+	"github.com/devphasex/cedar-bank-api/mail"
+	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -19,7 +19,7 @@ type CreateUserRequest struct {
 	Username string `json:"username" binding:"min=2,required"`
 	Email    string `json:"email" binding:"email,required"`
 	Fullname string `json:"fullname" binding:"min=2,required"`
-	Password string `json:"password" binding:"min=8,required"`
+	Password string `json:"password" binding:"required"`
 }
 
 type userResponse struct {
@@ -27,6 +27,8 @@ type userResponse struct {
 	Username          string    `json:"username"`
 	Email             string    `json:"email"`
 	Fullname          string    `json:"fullname"`
+	IsEmailVerified   bool      `json:"is_email_verified"`
+	IsTotpEnabled     bool      `json:"is_totp_enabled"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
 }
@@ -37,6 +39,8 @@ func newUserResponse(user db.User) userResponse {
 		Fullname:          user.Fullname,
 		Username:          user.Username,
 		Email:             user.Email,
+		IsEmailVerified:   user.IsEmailVerified,
+		IsTotpEnabled:     user.IsTotpEnabled,
 		PasswordChangedAt: user.PasswordChangedAt.Time,
 		CreatedAt:         user.CreatedAt.Time,
 	}
@@ -45,27 +49,26 @@ func newUserResponse(user db.User) userResponse {
 func (s *Server) createUser(ctx *gin.Context) {
 	var req CreateUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, prettyValidateError(err))
+		renderError(ctx, validationError(err))
 		return
 	}
 
-	ag := hash.DefaultArgonHash()
+	if !s.checkPasswordPolicy(ctx, "password", req.Password, req.Username, req.Email) {
+		return
+	}
 
-	passwordHash, err := ag.GenerateHash([]byte(req.Password), nil)
+	passwordHash, err := s.hashPassword(req.Password)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errors.New("an error occurred while creating your account"))
+		renderError(ctx, err)
 		return
 	}
 
-	passwordHashStr, passwordSaltStr := hash.ArgonStringEncode(passwordHash)
-
 	arg := db.CreateUserParams{
 		Username:       req.Username,
 		Email:          req.Email,
 		Fullname:       req.Fullname,
-		HashedPassword: passwordHashStr,
-		PasswordSalt:   passwordSaltStr,
+		HashedPassword: passwordHash,
 	}
 	user, err := s.store.CreateUser(ctx, arg)
 
@@ -73,22 +76,28 @@ func (s *Server) createUser(ctx *gin.Context) {
 		if err, ok := err.(*pgconn.PgError); ok {
 			switch err.ConstraintName {
 			case "users_username_key":
-				ctx.JSON(http.StatusConflict, errors.New(fmt.Sprintf("username already taken")))
+				renderError(ctx, util.ErrUsernameTaken)
 				return
 			case "users_email_key":
-				ctx.JSON(http.StatusConflict, errors.New(fmt.Sprintf("email already taken")))
+				renderError(ctx, util.ErrEmailTaken)
 				return
 			}
 
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		renderError(ctx, err)
 		return
 	}
 
+	// The user can ask for another link, so a failed send does not undo
+	// the sign-up.
+	if err := mail.SendVerifyEmail(ctx, s.store, s.mailer, s.config, user); err != nil {
+		util.Logger(ctx).Error("cannot send verification email", "user_id", user.ID, "error", err)
+	}
+
 	resp := newUserResponse(user)
 
-	ctx.JSON(http.StatusCreated, sucessResponse(resp, "user created successfully"))
+	ctx.JSON(http.StatusCreated, sucessResponse(resp, "user created successfully, check your email to verify your address"))
 	return
 }
 
@@ -110,12 +119,10 @@ func (s *Server) signin(ctx *gin.Context) {
 
 	var req SigninRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, prettyValidateError(err))
+		renderError(ctx, validationError(err))
 		return
 	}
 
-	ag := hash.DefaultArgonHash()
-
 	user, err := s.store.GetUserByUniqueID(ctx, db.GetUserByUniqueIDParams{
 		Email: pgtype.Text{
 			String: req.ID,
@@ -128,35 +135,84 @@ func (s *Server) signin(ctx *gin.Context) {
 		},
 	})
 
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("Invalid credential email or password mismatch")))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		renderError(ctx, err)
+		return
+	}
+
+	found := err == nil
+
+	attempt := db.RecordSigninAttemptTxParams{
+		UserID:     user.ID,
+		Identifier: req.ID,
+		ClientIP:   ctx.ClientIP(),
+		UserAgent:  ctx.Request.UserAgent(),
+		Policy:     db.NewSigninPolicy(s.config),
+	}
+
+	if !s.checkSigninThrottle(ctx, &attempt) {
+		return
+	}
+
+	if !found || checkPassword(user, req.Password) != nil {
+		if s.recordSigninAttempt(ctx, attempt, db.SigninBadCredentials) {
+			renderError(ctx, util.ErrInvalidCredentials)
+		}
+		return
+	}
+
+	s.rehashPassword(ctx, user, req.Password)
+
+	if !user.IsEmailVerified {
+		if s.recordSigninAttempt(ctx, attempt, db.SigninUnverified) {
+			renderError(ctx, util.ErrEmailNotVerified)
+		}
+		return
+	}
+
+	if user.IsTotpEnabled {
+		if !s.recordSigninAttempt(ctx, attempt, db.SigninChallenged) {
 			return
 		}
 
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		challenge, err := s.createSigninChallenge(ctx, user)
+
+		if err != nil {
+			renderError(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, sucessResponse(challenge, "two-factor code required"))
+		return
+	}
+
+	if !s.recordSigninAttempt(ctx, attempt, db.SigninSucceeded) {
 		return
 	}
 
-	passwordHashByte, passwordSaltByte := hash.ArgonStringDecode(user.HashedPassword, user.PasswordSalt)
+	response, err := s.createSession(ctx, user)
 
-	if err = ag.Compare(passwordHashByte, passwordSaltByte, []byte(req.Password)); err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(errors.New("Invalid credential email or password mismatch")))
+	if err != nil {
+		renderError(ctx, err)
 		return
 	}
 
+	ctx.JSON(http.StatusOK, sucessResponse(response, "account signin successfully"))
+}
+
+// createSession issues the access and refresh token pair of a completed
+// sign-in and records the refresh token as a session.
+func (s *Server) createSession(ctx *gin.Context, user db.User) (*signinResponse, error) {
 	accessToken, accessPayload, err := s.tokenMaker.CreateToken(user.ID, user.Email, s.config.AccessTokenTime)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return nil, err
 	}
 
 	refreshToken, refreshPayload, err := s.tokenMaker.CreateToken(user.ID, user.Email, s.config.RefreshTokenTime)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return nil, err
 	}
 
 	session, err := s.store.CreateSession(ctx, db.CreateSessionParams{
@@ -175,19 +231,15 @@ func (s *Server) signin(ctx *gin.Context) {
 	})
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return nil, err
 	}
 
-	resp := newUserResponse(user)
-	response := signinResponse{
+	return &signinResponse{
 		SessionID:             uuid.UUID(session.ID.Bytes).String(),
 		AccessToken:           accessToken,
 		AccessTokenExpiredAt:  accessPayload.ExpiresAt.Time,
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiredAt: refreshPayload.ExpiresAt.Time,
-		User:                  resp,
-	}
-
-	ctx.JSON(http.StatusOK, sucessResponse(response, "account signin successfully"))
+		User:                  newUserResponse(user),
+	}, nil
 }
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 
 	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
@@ -22,19 +23,17 @@ import (
 )
 
 func randomUser(t *testing.T) (db.User, string) {
-	ag := hash.DefaultArgonHash()
 	p := util.RandomString(8)
-	passwordHash, err := ag.GenerateHash([]byte(p), nil)
+	passwordHash, err := hash.DefaultArgonHash().Hash([]byte(p))
 
 	require.NoError(t, err)
-	passwordHashStr, passwordSaltStr := hash.ArgonStringEncode(passwordHash)
 	return db.User{
-		ID:             util.RandomInt(2000, 2500),
-		Username:       util.RandomOwner(),
-		Email:          util.RandomEmail(),
-		Fullname:       util.RandomOwner(),
-		HashedPassword: passwordHashStr,
-		PasswordSalt:   passwordSaltStr,
+		ID:              util.RandomInt(2000, 2500),
+		Username:        util.RandomOwner(),
+		Email:           util.RandomEmail(),
+		Fullname:        util.RandomOwner(),
+		HashedPassword:  passwordHash,
+		IsEmailVerified: true,
 	}, p
 }
 
@@ -50,16 +49,11 @@ func (e eqCreateUserParams) Matches(x interface{}) bool {
 		return false
 	}
 
-	arg := hash.DefaultArgonHash()
-	passwordHashByte, passwordSaltByte := hash.ArgonStringDecode(req.HashedPassword, req.PasswordSalt)
-	err := arg.Compare(passwordHashByte, passwordSaltByte, []byte(e.password))
-
-	if err != nil {
+	if err := hash.Verify(req.HashedPassword, []byte(e.password)); err != nil {
 		return false
 	}
 
 	e.params.HashedPassword = req.HashedPassword
-	e.params.PasswordSalt = req.PasswordSalt
 
 	return reflect.DeepEqual(e.params, req)
 }
@@ -103,6 +97,15 @@ func TestCreateUserApi(t *testing.T) {
 					CreateUser(gomock.Any(), EqCreateUserParams(req, p)).
 					Times(1).Return(user, nil)
 
+				store.EXPECT().
+					CreateVerifyEmail(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateVerifyEmailParams) (db.VerifyEmail, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, user.Email, arg.Email)
+						require.NotEmpty(t, arg.TokenHash)
+						return db.VerifyEmail{}, nil
+					})
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
@@ -110,6 +113,29 @@ func TestCreateUserApi(t *testing.T) {
 			},
 		},
 
+		{
+			name: "VerificationEmailFails",
+			body: map[string]any{
+				"username": user.Username,
+				"fullname": user.Fullname,
+				"password": p,
+				"email":    user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).Return(user, nil)
+
+				store.EXPECT().
+					CreateVerifyEmail(gomock.Any(), gomock.Any()).
+					Times(1).Return(db.VerifyEmail{}, pgx.ErrTxClosed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				// The user can request another link, so sign-up still succeeds.
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+
 		{
 			name: "InternalServerError",
 			body: map[string]any{
@@ -213,10 +239,11 @@ func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user db.User) {
 	err = json.Unmarshal(dataJSON, &gotUser)
 	require.NoError(t, err)
 	require.Equal(t, userResponse{
-		ID:       user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-		Fullname: user.Fullname,
+		ID:              user.ID,
+		Username:        user.Username,
+		Email:           user.Email,
+		Fullname:        user.Fullname,
+		IsEmailVerified: user.IsEmailVerified,
 	}, gotUser)
 }
 
@@ -258,6 +285,7 @@ func TestLoginUserApi(t *testing.T) {
 	store.EXPECT().
 		CreateUser(gomock.Any(), EqCreateUserParams(req, p)).
 		Times(1).Return(user, nil)
+	store.EXPECT().CreateVerifyEmail(gomock.Any(), gomock.Any()).Times(1)
 
 	// Sign up the user first
 	signUpRecorder := httptest.NewRecorder()
@@ -295,6 +323,7 @@ func TestLoginUserApi(t *testing.T) {
 					})).
 					Times(1).
 					Return(user, nil)
+				expectSigninAttempt(store, user.ID, db.SigninSucceeded)
 				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
 
 			},
@@ -318,6 +347,7 @@ func TestLoginUserApi(t *testing.T) {
 					})).
 					Times(1).
 					Return(user, nil)
+				expectSigninAttempt(store, user.ID, db.SigninSucceeded)
 				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
 
 			},
@@ -341,6 +371,7 @@ func TestLoginUserApi(t *testing.T) {
 					})).
 					Times(1).
 					Return(user, nil)
+				expectSigninAttempt(store, user.ID, db.SigninBadCredentials)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
@@ -362,11 +393,136 @@ func TestLoginUserApi(t *testing.T) {
 					})).
 					Times(1).
 					Return(user, nil)
+				expectSigninAttempt(store, user.ID, db.SigninBadCredentials)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusUnauthorized, recorder.Code)
 			},
 		},
+		{
+			name: "EmailNotVerified",
+			body: gin.H{
+				"id":       user.Email,
+				"password": p,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				unverified := user
+				unverified.IsEmailVerified = false
+
+				store.EXPECT().
+					GetUserByUniqueID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(unverified, nil)
+				expectSigninAttempt(store, user.ID, db.SigninUnverified)
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "RehashOutdatedPassword",
+			body: gin.H{
+				"id":       user.Email,
+				"password": p,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				outdatedHash, err := hash.NewArgon2idHash(1, 16, 8*1024, 1, 32).Hash([]byte(p))
+				require.NoError(t, err)
+
+				outdated := user
+				outdated.HashedPassword = outdatedHash
+
+				store.EXPECT().
+					GetUserByUniqueID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(outdated, nil)
+				expectSigninAttempt(store, user.ID, db.SigninSucceeded)
+				store.EXPECT().
+					RehashUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.RehashUserPasswordParams) (int64, error) {
+						require.Equal(t, user.ID, arg.ID)
+						require.Equal(t, outdatedHash, arg.OldHashedPassword)
+						require.NoError(t, hash.Verify(arg.HashedPassword, []byte(p)))
+						require.False(t, hash.DefaultArgonHash().NeedsRehash(arg.HashedPassword))
+						return 1, nil
+					})
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "RehashLegacyBcrypt",
+			body: gin.H{
+				"id":       user.Email,
+				"password": p,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				bcryptHash, err := hash.NewBcryptHash(4).Hash([]byte(p))
+				require.NoError(t, err)
+
+				migrated := user
+				migrated.HashedPassword = bcryptHash
+
+				store.EXPECT().
+					GetUserByUniqueID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(migrated, nil)
+				expectSigninAttempt(store, user.ID, db.SigninSucceeded)
+				store.EXPECT().
+					RehashUserPassword(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.RehashUserPasswordParams) (int64, error) {
+						require.Equal(t, bcryptHash, arg.OldHashedPassword)
+						require.True(t, strings.HasPrefix(arg.HashedPassword, "$argon2id$"))
+						require.NoError(t, hash.Verify(arg.HashedPassword, []byte(p)))
+						return 1, nil
+					})
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(1)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "TwoFactorRequired",
+			body: gin.H{
+				"id":       user.Email,
+				"password": p,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				withTOTP := user
+				withTOTP.IsTotpEnabled = true
+
+				store.EXPECT().
+					GetUserByUniqueID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(withTOTP, nil)
+				expectSigninAttempt(store, user.ID, db.SigninChallenged)
+				store.EXPECT().
+					CreateSigninChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateSigninChallengeParams) (db.SigninChallenge, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						return db.SigninChallenge{UserID: arg.UserID, TokenHash: arg.TokenHash, ExpiresAt: arg.ExpiresAt}, nil
+					})
+				store.EXPECT().CreateSession(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp struct {
+					Data signinChallengeResponse `json:"data"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.Data.TwoFactorRequired)
+				require.NotEmpty(t, rsp.Data.ChallengeToken)
+				require.NotContains(t, recorder.Body.String(), "access_token")
+			},
+		},
 	}
 
 	for _, tc := range testCases {
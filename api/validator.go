@@ -1,6 +1,9 @@
 package api
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/go-playground/validator/v10"
 )
@@ -9,7 +12,21 @@ var currencyValidator validator.Func = func(fl validator.FieldLevel) bool {
 	return util.IsCurrencySupported(fl.Field().String())
 }
 
+// fieldName names fields in validation errors the way clients send them.
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "uri", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+
+	return strings.ToLower(field.Name)
+}
+
 // Register custom validators
 func registerCustomValidators(v *validator.Validate) {
 	v.RegisterValidation("currency", currencyValidator)
+	v.RegisterTagNameFunc(fieldName)
 }
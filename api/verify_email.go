@@ -0,0 +1,83 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/mail"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type VerifyEmailRequest struct {
+	Token string `form:"token" binding:"required"`
+}
+
+func (s *Server) verifyEmail(ctx *gin.Context) {
+	var req VerifyEmailRequest
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	user, err := s.store.VerifyEmailTx(ctx, util.HashSecretToken(req.Token))
+
+	if err != nil {
+		renderError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(newUserResponse(*user), "email verified successfully"))
+}
+
+type ResendVerifyEmailRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// resendVerifyEmail answers the same way whether or not the address
+// belongs to an unverified user, and whether or not a new email went out,
+// so it cannot be used to probe for accounts. Reaching the resend limit
+// or failing to send is only logged.
+func (s *Server) resendVerifyEmail(ctx *gin.Context) {
+	var req ResendVerifyEmailRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		renderError(ctx, validationError(err))
+		return
+	}
+
+	const sent = "if the address belongs to an unverified account, a new verification email is on its way"
+
+	user, err := s.store.GetUserByUniqueID(ctx, db.GetUserByUniqueIDParams{
+		Email: pgtype.Text{
+			String: req.Email,
+			Valid:  true,
+		},
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			ctx.JSON(http.StatusOK, sucessResponse(nil, sent))
+			return
+		}
+
+		renderError(ctx, err)
+		return
+	}
+
+	if !user.IsEmailVerified {
+		err = mail.ResendVerifyEmail(ctx, s.store, s.mailer, s.config, user)
+
+		if errors.Is(err, db.ErrVerifyEmailsExceeded) {
+			util.Logger(ctx).Warn("verification email resend limit reached", "user_id", user.ID)
+		} else if err != nil {
+			util.Logger(ctx).Error("cannot send verification email", "user_id", user.ID, "error", err)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, sucessResponse(nil, sent))
+}
@@ -0,0 +1,248 @@
+// Package certs loads the TLS certificates of the servers and keeps them
+// current, so a rotated certificate or CA is picked up by the next
+// handshake without a restart.
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Reloader holds a certificate and key, a CA bundle, or both, and reloads
+// them whenever their files change. A change that leaves the files
+// unusable, such as a certificate written before its key, is logged and
+// the last good ones kept until the next change.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+// NewReloader loads certFile and keyFile, unless empty, and caFile, unless
+// empty, and starts watching them. Close stops the watch.
+func NewReloader(certFile, keyFile, caFile string) (*Reloader, error) {
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("certificate and key must be given together")
+	}
+
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+		done:     make(chan struct{}),
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Directories rather than files are watched, since secrets are often
+	// rotated by swapping a symlink, which a watch on the file misses.
+	dirs := map[string]bool{}
+
+	for _, file := range []string{certFile, keyFile, caFile} {
+		if file == "" {
+			continue
+		}
+
+		if dir := filepath.Dir(file); !dirs[dir] {
+			if err := watcher.Add(dir); err != nil {
+				watcher.Close()
+				return nil, err
+			}
+
+			dirs[dir] = true
+		}
+	}
+
+	r.watcher = watcher
+	go r.watch()
+
+	return r, nil
+}
+
+func (r *Reloader) reload() error {
+	var cert *tls.Certificate
+	var pool *x509.CertPool
+
+	if r.certFile != "" {
+		loaded, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+
+		if err != nil {
+			return fmt.Errorf("cannot load certificate: %w", err)
+		}
+
+		cert = &loaded
+	}
+
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+
+		if err != nil {
+			return fmt.Errorf("cannot load CA: %w", err)
+		}
+
+		pool = x509.NewCertPool()
+
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates in CA file %s", r.caFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = cert
+	r.pool = pool
+
+	return nil
+}
+
+func (r *Reloader) watch() {
+	defer close(r.done)
+
+	for {
+		select {
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+
+			if event.Op == fsnotify.Chmod {
+				continue
+			}
+
+			if err := r.reload(); err != nil {
+				slog.Error("cannot reload certificates", "cert", r.certFile, "ca", r.caFile, "error", err)
+				continue
+			}
+
+			slog.Info("reloaded certificates", "cert", r.certFile, "ca", r.caFile)
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+
+			slog.Error("cannot watch certificates", "cert", r.certFile, "ca", r.caFile, "error", err)
+		}
+	}
+}
+
+// Close stops watching the files.
+func (r *Reloader) Close() error {
+	err := r.watcher.Close()
+	<-r.done
+
+	return err
+}
+
+func (r *Reloader) certificate() (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.cert == nil {
+		return nil, errors.New("no certificate configured")
+	}
+
+	return r.cert, nil
+}
+
+// verify checks the chain a peer presented against the current CA.
+func (r *Reloader) verify(chain []*x509.Certificate, usage x509.ExtKeyUsage, serverName string) error {
+	if len(chain) == 0 {
+		return errors.New("peer presented no certificate")
+	}
+
+	r.mu.RLock()
+	pool := r.pool
+	r.mu.RUnlock()
+
+	opts := x509.VerifyOptions{
+		Roots:         pool,
+		DNSName:       serverName,
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	}
+
+	for _, cert := range chain[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	_, err := chain[0].Verify(opts)
+	return err
+}
+
+// ServerConfig returns the config of a server presenting the certificate.
+// With a CA, clients must present a certificate it issued.
+func (r *Reloader) ServerConfig() *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.certificate()
+		},
+	}
+
+	if r.caFile != "" {
+		// ClientCAs would be fixed when the config is made, so client
+		// certificates are checked against the current CA here instead.
+		config.ClientAuth = tls.RequireAnyClientCert
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			return r.verify(cs.PeerCertificates, x509.ExtKeyUsageClientAuth, "")
+		}
+	}
+
+	return config
+}
+
+// ClientConfig returns the config of a client connecting to serverName,
+// a host name or IP address. The client presents the certificate, if
+// any, and trusts servers the CA issued, or the system's CAs without one.
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+	}
+
+	if r.certFile != "" {
+		config.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return r.certificate()
+		}
+	}
+
+	if r.caFile != "" {
+		// As with ServerConfig, RootCAs would be fixed. The standard
+		// verification is skipped only to be done below against the
+		// current CA, server name included.
+		config.InsecureSkipVerify = true
+		config.VerifyConnection = func(cs tls.ConnectionState) error {
+			if serverName == "" {
+				return errors.New("no server name to verify the server certificate against")
+			}
+
+			return r.verify(cs.PeerCertificates, x509.ExtKeyUsageServerAuth, serverName)
+		}
+	}
+
+	return config
+}
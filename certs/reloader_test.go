@@ -0,0 +1,143 @@
+package certs
+
+import (
+	"crypto/tls"
+	"math/big"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/certs/certstest"
+	"github.com/stretchr/testify/require"
+)
+
+// handshake connects client to server over loopback and returns the
+// serial number of the certificate the server presented.
+func handshake(serverConfig, clientConfig *tls.Config) (*big.Int, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	defer ln.Close()
+
+	serverErr := make(chan error, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+
+		serverErr <- tls.Server(conn, serverConfig).Handshake()
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	client := tls.Client(conn, clientConfig)
+
+	if err := client.Handshake(); err != nil {
+		return nil, err
+	}
+
+	// With TLS 1.3 the server checks the client certificate after the
+	// client is done, so its verdict is the server's.
+	if err := <-serverErr; err != nil {
+		return nil, err
+	}
+
+	return client.ConnectionState().PeerCertificates[0].SerialNumber, nil
+}
+
+func newReloader(t *testing.T, certFile, keyFile, caFile string) *Reloader {
+	r, err := NewReloader(certFile, keyFile, caFile)
+	require.NoError(t, err)
+	t.Cleanup(func() { r.Close() })
+
+	return r
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := certstest.NewCA(t, "cedar test CA")
+	dir := t.TempDir()
+
+	serverCert, serverKey := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	clientCert, clientKey := filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+
+	ca.Issue(t, "grpc.cedar.local", serverCert, serverKey)
+	ca.Issue(t, "gateway", clientCert, clientKey)
+
+	server := newReloader(t, serverCert, serverKey, ca.CertFile)
+	client := newReloader(t, clientCert, clientKey, ca.CertFile)
+
+	_, err := handshake(server.ServerConfig(), client.ClientConfig("grpc.cedar.local"))
+	require.NoError(t, err)
+
+	t.Run("WrongServerName", func(t *testing.T) {
+		_, err := handshake(server.ServerConfig(), client.ClientConfig("other.cedar.local"))
+		require.Error(t, err)
+	})
+
+	t.Run("NoClientCertificate", func(t *testing.T) {
+		anonymous := newReloader(t, "", "", ca.CertFile)
+
+		_, err := handshake(server.ServerConfig(), anonymous.ClientConfig("grpc.cedar.local"))
+		require.Error(t, err)
+	})
+
+	t.Run("ClientFromOtherCA", func(t *testing.T) {
+		other := certstest.NewCA(t, "other CA")
+		otherDir := t.TempDir()
+		other.Issue(t, "gateway", filepath.Join(otherDir, "client.pem"), filepath.Join(otherDir, "client-key.pem"))
+
+		stranger := newReloader(t, filepath.Join(otherDir, "client.pem"), filepath.Join(otherDir, "client-key.pem"), ca.CertFile)
+
+		_, err := handshake(server.ServerConfig(), stranger.ClientConfig("grpc.cedar.local"))
+		require.Error(t, err)
+	})
+}
+
+func TestReloadOnChange(t *testing.T) {
+	ca := certstest.NewCA(t, "cedar test CA")
+	dir := t.TempDir()
+
+	certFile, keyFile := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	first := ca.Issue(t, "127.0.0.1", certFile, keyFile)
+
+	server := newReloader(t, certFile, keyFile, "")
+	client := newReloader(t, "", "", ca.CertFile)
+
+	serverConfig, clientConfig := server.ServerConfig(), client.ClientConfig("127.0.0.1")
+
+	serial, err := handshake(serverConfig, clientConfig)
+	require.NoError(t, err)
+	require.Equal(t, first, serial)
+
+	second := ca.Issue(t, "127.0.0.1", certFile, keyFile)
+
+	// The configs made before the rotation pick up the new certificate.
+	require.Eventually(t, func() bool {
+		serial, err := handshake(serverConfig, clientConfig)
+		return err == nil && serial.Cmp(second) == 0
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestNewReloaderInvalid(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := NewReloader(filepath.Join(dir, "cert.pem"), "", "")
+	require.Error(t, err)
+
+	_, err = NewReloader(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), "")
+	require.Error(t, err)
+
+	_, err = NewReloader("", "", filepath.Join(dir, "ca.pem"))
+	require.Error(t, err)
+}
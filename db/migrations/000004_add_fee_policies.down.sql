@@ -0,0 +1,15 @@
+ALTER TABLE "transfer"
+  DROP COLUMN IF EXISTS "fee_policy_id",
+  DROP COLUMN IF EXISTS "fee";
+
+DELETE FROM "entries"
+WHERE "account_id" IN (SELECT "account_id" FROM "system_accounts" WHERE "purpose" = 'fee_revenue');
+
+DROP TABLE IF EXISTS "system_accounts";
+
+DELETE FROM "accounts"
+WHERE "owner_id" IN (SELECT "id" FROM "users" WHERE "username" = 'system_fee_revenue');
+
+DELETE FROM "users" WHERE "username" = 'system_fee_revenue';
+
+DROP TABLE IF EXISTS "fee_policies";
@@ -0,0 +1,55 @@
+CREATE TABLE IF NOT EXISTS "fee_policies" (
+  "id" bigserial PRIMARY KEY,
+  "currency" varchar,
+  "version" int NOT NULL,
+  "kind" varchar(20) NOT NULL,
+  "flat_amount" float NOT NULL DEFAULT '0.0',
+  "percentage" float NOT NULL DEFAULT '0.0',
+  "min_fee" float NOT NULL DEFAULT '0.0',
+  "max_fee" float,
+  "tiers" jsonb NOT NULL DEFAULT '[]',
+  "active" boolean NOT NULL DEFAULT TRUE,
+  "effective_from" timestamptz NOT NULL DEFAULT (now()),
+  "created_at" timestamptz DEFAULT (now())
+);
+
+COMMENT ON COLUMN "fee_policies"."currency" IS 'NULL applies to every currency without a specific policy';
+COMMENT ON COLUMN "fee_policies"."percentage" IS 'percent of the transfer amount, 1.5 means 1.5%';
+
+ALTER TABLE "fee_policies"
+ADD CONSTRAINT unique_fee_policy_version UNIQUE NULLS NOT DISTINCT ("currency", "version");
+
+ALTER TABLE "fee_policies"
+ADD CONSTRAINT check_fee_policy_kind CHECK ("kind" IN ('flat', 'percentage', 'tiered'));
+
+CREATE TABLE IF NOT EXISTS "system_accounts" (
+  "purpose" varchar(50) NOT NULL,
+  "currency" varchar NOT NULL,
+  "account_id" bigint NOT NULL,
+  "created_at" timestamptz DEFAULT (now()),
+  PRIMARY KEY ("purpose", "currency")
+);
+
+ALTER TABLE "system_accounts" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer"
+  ADD COLUMN "fee" float NOT NULL DEFAULT '0.0',
+  ADD COLUMN "fee_policy_id" bigint;
+
+ALTER TABLE "transfer" ADD FOREIGN KEY ("fee_policy_id") REFERENCES "fee_policies" ("id");
+
+-- System owner for the fee revenue accounts. The empty password hash
+-- can never match, so the user is unable to sign in.
+INSERT INTO "users" ("username", "email", "fullname", "hashed_password", "password_salt")
+VALUES ('system_fee_revenue', 'fee-revenue@system.cedar-bank.local', 'Fee Revenue', '', '');
+
+WITH "owner" AS (
+  SELECT "id" FROM "users" WHERE "username" = 'system_fee_revenue'
+), "revenue_accounts" AS (
+  INSERT INTO "accounts" ("owner_id", "currency")
+  SELECT "owner"."id", "currencies"."currency"
+  FROM "owner", (VALUES ('USD'), ('EUR'), ('CAD')) AS "currencies" ("currency")
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'fee_revenue', "currency", "id" FROM "revenue_accounts";
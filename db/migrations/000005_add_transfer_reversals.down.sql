@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS "transfer_reversals";
+
+ALTER TABLE "transfer"
+  DROP CONSTRAINT IF EXISTS check_transfer_reversed_amount;
+
+ALTER TABLE "transfer"
+  DROP COLUMN IF EXISTS "original_transfer_id",
+  DROP COLUMN IF EXISTS "reversed_amount";
+
+ALTER TABLE "users"
+  DROP COLUMN IF EXISTS "role";
@@ -0,0 +1,32 @@
+ALTER TABLE "users"
+  ADD COLUMN "role" varchar(20) NOT NULL DEFAULT 'depositor';
+
+ALTER TABLE "transfer"
+  ADD COLUMN "reversed_amount" float NOT NULL DEFAULT '0.0',
+  ADD COLUMN "original_transfer_id" bigint;
+
+ALTER TABLE "transfer" ADD FOREIGN KEY ("original_transfer_id") REFERENCES "transfer" ("id");
+
+COMMENT ON COLUMN "transfer"."reversed_amount" IS 'total refunded through reversals, never above amount';
+COMMENT ON COLUMN "transfer"."original_transfer_id" IS 'set on compensating transfers created by a reversal';
+
+ALTER TABLE "transfer"
+ADD CONSTRAINT check_transfer_reversed_amount CHECK ("reversed_amount" >= 0 AND "reversed_amount" <= "amount");
+
+CREATE TABLE IF NOT EXISTS "transfer_reversals" (
+  "id" bigserial PRIMARY KEY,
+  "transfer_id" bigint NOT NULL,
+  "reversal_transfer_id" bigint NOT NULL,
+  "amount" float NOT NULL,
+  "reason" text NOT NULL,
+  "reversed_by" bigint NOT NULL,
+  "created_at" timestamptz DEFAULT (now())
+);
+
+CREATE INDEX ON "transfer_reversals" ("transfer_id");
+
+ALTER TABLE "transfer_reversals" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfer" ("id");
+
+ALTER TABLE "transfer_reversals" ADD FOREIGN KEY ("reversal_transfer_id") REFERENCES "transfer" ("id");
+
+ALTER TABLE "transfer_reversals" ADD FOREIGN KEY ("reversed_by") REFERENCES "users" ("id");
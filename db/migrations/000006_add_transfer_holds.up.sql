@@ -0,0 +1,37 @@
+ALTER TABLE "accounts"
+  ADD COLUMN "held_balance" float NOT NULL DEFAULT '0.0',
+  ADD COLUMN "available_balance" float NOT NULL GENERATED ALWAYS AS ("balance" - "held_balance") STORED;
+
+COMMENT ON COLUMN "accounts"."balance" IS 'ledger balance, only moved by settled transfers';
+COMMENT ON COLUMN "accounts"."held_balance" IS 'sum of pending transfer holds';
+
+CREATE TABLE IF NOT EXISTS "transfer_holds" (
+  "id" bigserial PRIMARY KEY,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" float NOT NULL,
+  "fee" float NOT NULL DEFAULT '0.0',
+  "fee_policy_id" bigint,
+  "status" varchar(20) NOT NULL DEFAULT 'pending',
+  "transfer_id" bigint,
+  "expires_at" timestamptz NOT NULL,
+  "created_at" timestamptz DEFAULT (now()),
+  "updated_at" timestamptz DEFAULT (now())
+);
+
+ALTER TABLE "transfer_holds"
+ADD CONSTRAINT check_transfer_hold_status CHECK ("status" IN ('pending', 'captured', 'voided', 'expired'));
+
+CREATE INDEX ON "transfer_holds" ("from_account_id");
+
+CREATE INDEX ON "transfer_holds" ("status", "expires_at");
+
+ALTER TABLE "transfer_holds" ADD FOREIGN KEY ("from_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_holds" ADD FOREIGN KEY ("to_account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "transfer_holds" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfer" ("id");
+
+ALTER TABLE "transfer_holds" ADD FOREIGN KEY ("fee_policy_id") REFERENCES "fee_policies" ("id");
+
+COMMENT ON COLUMN "transfer_holds"."fee" IS 'fee quoted at authorization, charged on capture';
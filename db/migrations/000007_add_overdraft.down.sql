@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS "overdraft_accruals";
+
+DELETE FROM "entries"
+WHERE "account_id" IN (SELECT "account_id" FROM "system_accounts" WHERE "purpose" = 'overdraft_revenue');
+
+DELETE FROM "system_accounts" WHERE "purpose" = 'overdraft_revenue';
+
+DELETE FROM "accounts"
+WHERE "owner_id" IN (SELECT "id" FROM "users" WHERE "username" = 'system_overdraft_revenue');
+
+DELETE FROM "users" WHERE "username" = 'system_overdraft_revenue';
+
+ALTER TABLE "accounts"
+  DROP CONSTRAINT IF EXISTS check_accounts_overdraft_limit;
+
+ALTER TABLE "accounts"
+  DROP COLUMN IF EXISTS "overdraft_limit";
@@ -0,0 +1,34 @@
+ALTER TABLE "accounts"
+  ADD COLUMN "overdraft_limit" float NOT NULL DEFAULT '0.0';
+
+ALTER TABLE "accounts"
+ADD CONSTRAINT check_accounts_overdraft_limit CHECK ("overdraft_limit" >= 0);
+
+COMMENT ON COLUMN "accounts"."overdraft_limit" IS 'how far below zero the balance may go, set by admins';
+
+CREATE TABLE IF NOT EXISTS "overdraft_accruals" (
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" float NOT NULL,
+  "amount" float NOT NULL,
+  "created_at" timestamptz DEFAULT (now()),
+  PRIMARY KEY ("account_id", "accrual_date")
+);
+
+COMMENT ON TABLE "overdraft_accruals" IS 'one row per account and day, makes the accrual job idempotent';
+
+ALTER TABLE "overdraft_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+INSERT INTO "users" ("username", "email", "fullname", "hashed_password", "password_salt")
+VALUES ('system_overdraft_revenue', 'overdraft-revenue@system.cedar-bank.local', 'Overdraft Revenue', '', '');
+
+WITH "owner" AS (
+  SELECT "id" FROM "users" WHERE "username" = 'system_overdraft_revenue'
+), "revenue_accounts" AS (
+  INSERT INTO "accounts" ("owner_id", "currency")
+  SELECT "owner"."id", "currencies"."currency"
+  FROM "owner", (VALUES ('USD'), ('EUR'), ('CAD')) AS "currencies" ("currency")
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'overdraft_revenue', "currency", "id" FROM "revenue_accounts";
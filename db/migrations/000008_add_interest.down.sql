@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS "interest_accruals";
+
+DROP TABLE IF EXISTS "interest_rates";
+
+DELETE FROM "entries"
+WHERE "account_id" IN (SELECT "account_id" FROM "system_accounts" WHERE "purpose" = 'interest_expense');
+
+DELETE FROM "system_accounts" WHERE "purpose" = 'interest_expense';
+
+DELETE FROM "accounts"
+WHERE "owner_id" IN (SELECT "id" FROM "users" WHERE "username" = 'system_interest_expense');
+
+DELETE FROM "users" WHERE "username" = 'system_interest_expense';
+
+ALTER TABLE "accounts"
+  DROP CONSTRAINT IF EXISTS unique_owner_currency_type;
+
+ALTER TABLE "accounts"
+ADD CONSTRAINT unique_owner_currency UNIQUE ("owner_id", "currency");
+
+ALTER TABLE "accounts"
+  DROP CONSTRAINT IF EXISTS check_accounts_type;
+
+ALTER TABLE "accounts"
+  DROP COLUMN IF EXISTS "type";
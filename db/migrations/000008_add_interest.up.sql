@@ -0,0 +1,65 @@
+ALTER TABLE "accounts"
+  ADD COLUMN "type" varchar(20) NOT NULL DEFAULT 'checking';
+
+ALTER TABLE "accounts"
+ADD CONSTRAINT check_accounts_type CHECK ("type" IN ('checking', 'savings'));
+
+-- A user may hold one account of each type per currency.
+ALTER TABLE "accounts"
+  DROP CONSTRAINT IF EXISTS unique_owner_currency;
+
+ALTER TABLE "accounts"
+ADD CONSTRAINT unique_owner_currency_type UNIQUE ("owner_id", "currency", "type");
+
+CREATE INDEX ON "accounts" ("type", "id");
+
+CREATE TABLE IF NOT EXISTS "interest_rates" (
+  "id" bigserial PRIMARY KEY,
+  "account_type" varchar(20) NOT NULL,
+  "currency" varchar NOT NULL,
+  "annual_rate" float NOT NULL,
+  "effective_from" date NOT NULL,
+  "created_at" timestamptz DEFAULT (now()),
+  CONSTRAINT unique_interest_rate_effective_from UNIQUE ("account_type", "currency", "effective_from"),
+  CONSTRAINT check_interest_rates_annual_rate CHECK ("annual_rate" >= 0)
+);
+
+COMMENT ON COLUMN "interest_rates"."annual_rate" IS 'yearly rate in percent, applies from effective_from until the next row';
+
+CREATE TABLE IF NOT EXISTS "interest_accruals" (
+  "account_id" bigint NOT NULL,
+  "accrual_date" date NOT NULL,
+  "balance" float NOT NULL,
+  "annual_rate" float NOT NULL,
+  "amount" float NOT NULL,
+  "entry_id" bigint,
+  "posted_at" timestamptz,
+  "created_at" timestamptz DEFAULT (now()),
+  PRIMARY KEY ("account_id", "accrual_date")
+);
+
+COMMENT ON TABLE "interest_accruals" IS 'one row per account and day, makes the accrual job idempotent';
+
+COMMENT ON COLUMN "interest_accruals"."balance" IS 'end-of-day balance the interest was computed on';
+
+COMMENT ON COLUMN "interest_accruals"."amount" IS 'unrounded daily interest, rounded when the month is posted';
+
+CREATE INDEX ON "interest_accruals" ("account_id") WHERE "posted_at" IS NULL;
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("account_id") REFERENCES "accounts" ("id");
+
+ALTER TABLE "interest_accruals" ADD FOREIGN KEY ("entry_id") REFERENCES "entries" ("id");
+
+INSERT INTO "users" ("username", "email", "fullname", "hashed_password", "password_salt")
+VALUES ('system_interest_expense', 'interest-expense@system.cedar-bank.local', 'Interest Expense', '', '');
+
+WITH "owner" AS (
+  SELECT "id" FROM "users" WHERE "username" = 'system_interest_expense'
+), "expense_accounts" AS (
+  INSERT INTO "accounts" ("owner_id", "currency")
+  SELECT "owner"."id", "currencies"."currency"
+  FROM "owner", (VALUES ('USD'), ('EUR'), ('CAD')) AS "currencies" ("currency")
+  RETURNING "id", "currency"
+)
+INSERT INTO "system_accounts" ("purpose", "currency", "account_id")
+SELECT 'interest_expense', "currency", "id" FROM "expense_accounts";
@@ -0,0 +1,11 @@
+ALTER TABLE "entries"
+  ADD COLUMN "transfer_id" bigint,
+  ADD COLUMN "kind" varchar(30) NOT NULL DEFAULT 'transfer';
+
+COMMENT ON COLUMN "entries"."transfer_id" IS 'transfer the entry was booked for, including its fee';
+
+COMMENT ON COLUMN "entries"."kind" IS 'transfer, or the system account purpose for fee, interest and overdraft entries';
+
+ALTER TABLE "entries" ADD FOREIGN KEY ("transfer_id") REFERENCES "transfer" ("id");
+
+CREATE INDEX ON "entries" ("account_id", "created_at");
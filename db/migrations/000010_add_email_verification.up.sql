@@ -0,0 +1,23 @@
+ALTER TABLE "users"
+  ADD COLUMN "is_email_verified" boolean NOT NULL DEFAULT false,
+  ADD COLUMN "email_verified_at" timestamptz;
+
+-- Users created before verification existed keep their access.
+UPDATE "users" SET "is_email_verified" = true, "email_verified_at" = now();
+
+CREATE TABLE IF NOT EXISTS "verify_emails" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "email" varchar NOT NULL,
+  "token_hash" varchar NOT NULL UNIQUE,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "verify_emails"."email" IS 'address the token was sent to, the token is void once the user changes it';
+COMMENT ON COLUMN "verify_emails"."token_hash" IS 'sha256 of the emailed token, the token itself is never stored';
+
+CREATE INDEX ON "verify_emails" ("user_id", "created_at");
+
+ALTER TABLE "verify_emails" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
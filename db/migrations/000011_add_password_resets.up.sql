@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS "password_resets" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "token_hash" varchar NOT NULL UNIQUE,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "password_resets"."token_hash" IS 'sha256 of the emailed token, the token itself is never stored';
+
+CREATE INDEX ON "password_resets" ("user_id", "created_at");
+
+ALTER TABLE "password_resets" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE INDEX ON "sessions" ("owner_id");
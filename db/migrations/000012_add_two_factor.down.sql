@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS "signin_challenges";
+
+DROP TABLE IF EXISTS "recovery_codes";
+
+ALTER TABLE "users"
+  DROP COLUMN IF EXISTS "totp_last_step",
+  DROP COLUMN IF EXISTS "is_totp_enabled",
+  DROP COLUMN IF EXISTS "totp_secret";
@@ -0,0 +1,36 @@
+ALTER TABLE "users"
+  ADD COLUMN "totp_secret" varchar,
+  ADD COLUMN "is_totp_enabled" boolean NOT NULL DEFAULT false,
+  ADD COLUMN "totp_last_step" bigint;
+
+COMMENT ON COLUMN "users"."totp_secret" IS 'base32 secret, set at enrollment and only in force once is_totp_enabled';
+COMMENT ON COLUMN "users"."totp_last_step" IS 'time step of the last accepted code, older or equal steps are replays';
+
+CREATE TABLE IF NOT EXISTS "recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON COLUMN "recovery_codes"."code_hash" IS 'sha256 of the normalized code';
+
+ALTER TABLE "recovery_codes" ADD CONSTRAINT unique_user_recovery_code UNIQUE ("user_id", "code_hash");
+
+ALTER TABLE "recovery_codes" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+CREATE TABLE IF NOT EXISTS "signin_challenges" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "token_hash" varchar NOT NULL UNIQUE,
+  "attempts" int NOT NULL DEFAULT 0,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "signin_challenges" IS 'password sign-ins of 2FA users waiting for their second factor';
+COMMENT ON COLUMN "signin_challenges"."token_hash" IS 'sha256 of the challenge token, the token itself is never stored';
+
+ALTER TABLE "signin_challenges" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
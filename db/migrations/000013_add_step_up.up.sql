@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS "step_up_challenges" (
+  "id" uuid PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "action" varchar NOT NULL,
+  "params_hash" varchar NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "token_hash" varchar UNIQUE,
+  "expires_at" timestamptz NOT NULL,
+  "verified_at" timestamptz,
+  "used_at" timestamptz,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "step_up_challenges" IS 're-authentication demanded before a sensitive action, such as a large transfer';
+COMMENT ON COLUMN "step_up_challenges"."params_hash" IS 'sha256 of the action parameters, the step-up token is only good for exactly these';
+COMMENT ON COLUMN "step_up_challenges"."token_hash" IS 'sha256 of the step-up token issued once the user re-authenticated';
+
+CREATE INDEX ON "step_up_challenges" ("user_id", "created_at");
+
+ALTER TABLE "step_up_challenges" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "signin_throttles";
+DROP TABLE IF EXISTS "auth_audit";
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS "auth_audit" (
+  "id" bigserial PRIMARY KEY,
+  "user_id" bigint,
+  "identifier" varchar NOT NULL,
+  "client_ip" varchar NOT NULL,
+  "user_agent" varchar NOT NULL,
+  "outcome" varchar NOT NULL,
+  "success" boolean NOT NULL DEFAULT false,
+  "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE IF NOT EXISTS "signin_throttles" (
+  "key" varchar PRIMARY KEY,
+  "failures" int NOT NULL DEFAULT 0,
+  "last_failed_at" timestamptz NOT NULL DEFAULT (now()),
+  "locked_until" timestamptz
+);
+
+COMMENT ON TABLE "auth_audit" IS 'every sign-in attempt, successful or not';
+COMMENT ON COLUMN "auth_audit"."user_id" IS 'null when the identifier matched no user';
+COMMENT ON COLUMN "auth_audit"."identifier" IS 'the username or email the attempt was made with';
+COMMENT ON TABLE "signin_throttles" IS 'failed sign-in counters, per user and per client ip';
+COMMENT ON COLUMN "signin_throttles"."key" IS 'user:<id> or ip:<address>';
+
+CREATE INDEX ON "auth_audit" ("user_id", "created_at");
+
+CREATE INDEX ON "auth_audit" ("client_ip", "created_at");
+
+ALTER TABLE "auth_audit" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
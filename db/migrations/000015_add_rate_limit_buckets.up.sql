@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS "rate_limit_buckets" (
+  "key" varchar PRIMARY KEY,
+  "tokens" double precision NOT NULL,
+  "allowed" boolean NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "rate_limit_buckets" IS 'token buckets shared by every instance when rate limits are kept in postgres';
+COMMENT ON COLUMN "rate_limit_buckets"."allowed" IS 'whether the last take found a token';
+
+CREATE INDEX ON "rate_limit_buckets" ("updated_at");
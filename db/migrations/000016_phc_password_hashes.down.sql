@@ -0,0 +1,12 @@
+COMMENT ON COLUMN "users"."hashed_password" IS NULL;
+
+ALTER TABLE "users" ADD COLUMN "password_salt" text NOT NULL DEFAULT '';
+
+-- Only hashes made with the old fixed parameters can be split back; any
+-- other stays in PHC form and no longer verifies.
+UPDATE "users"
+SET "password_salt" = rpad(split_part("hashed_password", '$', 5), (length(split_part("hashed_password", '$', 5)) + 3) / 4 * 4, '='),
+    "hashed_password" = rpad(split_part("hashed_password", '$', 6), (length(split_part("hashed_password", '$', 6)) + 3) / 4 * 4, '=')
+WHERE "hashed_password" LIKE '$argon2id$v=19$m=65536,t=1,p=32$%';
+
+ALTER TABLE "users" ALTER COLUMN "password_salt" DROP DEFAULT;
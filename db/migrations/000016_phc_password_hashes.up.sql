@@ -0,0 +1,13 @@
+-- Stored hashes used to be bare base64 with the salt in its own column and
+-- the argon2id parameters implied by the code: t=1, m=64MiB, p=32. Fold
+-- both into a PHC string so the parameters travel with the hash; the
+-- padding is dropped as PHC uses unpadded base64. Users without a
+-- password, such as the system owners, keep the empty hash.
+UPDATE "users"
+SET "hashed_password" = '$argon2id$v=19$m=65536,t=1,p=32$'
+    || rtrim("password_salt", '=') || '$' || rtrim("hashed_password", '=')
+WHERE "hashed_password" <> '' AND "hashed_password" NOT LIKE '$%';
+
+ALTER TABLE "users" DROP COLUMN "password_salt";
+
+COMMENT ON COLUMN "users"."hashed_password" IS 'PHC string, $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>';
@@ -0,0 +1 @@
+COMMENT ON COLUMN "users"."hashed_password" IS 'PHC string, $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>';
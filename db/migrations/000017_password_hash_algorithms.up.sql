@@ -0,0 +1 @@
+COMMENT ON COLUMN "users"."hashed_password" IS 'argon2id or scrypt PHC string, or bcrypt modular crypt string; the prefix names the algorithm';
@@ -0,0 +1,3 @@
+ALTER TABLE "signin_throttles"
+  DROP COLUMN IF EXISTS "reserved_at",
+  DROP COLUMN IF EXISTS "reserved";
@@ -0,0 +1,5 @@
+ALTER TABLE "signin_throttles"
+  ADD COLUMN "reserved" int NOT NULL DEFAULT 0,
+  ADD COLUMN "reserved_at" timestamptz NOT NULL DEFAULT (now());
+
+COMMENT ON COLUMN "signin_throttles"."reserved" IS 'attempts let through that are not recorded yet';
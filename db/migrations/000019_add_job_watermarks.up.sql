@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS "job_watermarks" (
+  "job" varchar PRIMARY KEY,
+  "done_through" date NOT NULL,
+  "updated_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+COMMENT ON TABLE "job_watermarks" IS 'how far each daily job got through its dates';
+COMMENT ON COLUMN "job_watermarks"."done_through" IS 'last date the job finished for every account';
@@ -0,0 +1,36 @@
+// Package migrations embeds the schema migrations so the server knows the
+// version the database must be at.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.up.sql
+var files embed.FS
+
+// LatestVersion is the version of the newest migration, the one
+// golang-migrate records in schema_migrations once it has run.
+func LatestVersion() uint {
+	entries, err := fs.ReadDir(files, ".")
+
+	if err != nil {
+		panic(err)
+	}
+
+	var latest uint
+
+	for _, entry := range entries {
+		prefix, _, _ := strings.Cut(entry.Name(), "_")
+		version, err := strconv.ParseUint(prefix, 10, 0)
+
+		if err == nil && uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+
+	return latest
+}
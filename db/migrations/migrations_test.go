@@ -0,0 +1,17 @@
+package migrations
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestVersion(t *testing.T) {
+	files, err := filepath.Glob("*.up.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	// Migrations are numbered from 1 without gaps.
+	require.EqualValues(t, len(files), LatestVersion())
+}
@@ -7,6 +7,7 @@ package mockdb
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
 	gomock "github.com/golang/mock/gomock"
@@ -37,228 +38,1586 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AccrueInterestTx mocks base method.
+func (m *MockStore) AccrueInterestTx(arg0 context.Context, arg1 db.AccrueInterestTxParams) (*db.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueInterestTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrueInterestTx indicates an expected call of AccrueInterestTx.
+func (mr *MockStoreMockRecorder) AccrueInterestTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueInterestTx", reflect.TypeOf((*MockStore)(nil).AccrueInterestTx), arg0, arg1)
+}
+
+// AccrueOverdraftTx mocks base method.
+func (m *MockStore) AccrueOverdraftTx(arg0 context.Context, arg1 db.AccrueOverdraftTxParams) (*db.OverdraftAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueOverdraftTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.OverdraftAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrueOverdraftTx indicates an expected call of AccrueOverdraftTx.
+func (mr *MockStoreMockRecorder) AccrueOverdraftTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueOverdraftTx", reflect.TypeOf((*MockStore)(nil).AccrueOverdraftTx), arg0, arg1)
+}
+
+// AddAccountBalance mocks base method.
+func (m *MockStore) AddAccountBalance(arg0 context.Context, arg1 db.AddAccountBalanceParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccountBalance indicates an expected call of AddAccountBalance.
+func (mr *MockStoreMockRecorder) AddAccountBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountBalance", reflect.TypeOf((*MockStore)(nil).AddAccountBalance), arg0, arg1)
+}
+
+// AddAccountHeldBalance mocks base method.
+func (m *MockStore) AddAccountHeldBalance(arg0 context.Context, arg1 db.AddAccountHeldBalanceParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddAccountHeldBalance", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddAccountHeldBalance indicates an expected call of AddAccountHeldBalance.
+func (mr *MockStoreMockRecorder) AddAccountHeldBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddAccountHeldBalance", reflect.TypeOf((*MockStore)(nil).AddAccountHeldBalance), arg0, arg1)
+}
+
+// AddTransferReversedAmount mocks base method.
+func (m *MockStore) AddTransferReversedAmount(arg0 context.Context, arg1 db.AddTransferReversedAmountParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTransferReversedAmount", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTransferReversedAmount indicates an expected call of AddTransferReversedAmount.
+func (mr *MockStoreMockRecorder) AddTransferReversedAmount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTransferReversedAmount", reflect.TypeOf((*MockStore)(nil).AddTransferReversedAmount), arg0, arg1)
+}
+
+// AdvanceUserTOTPStep mocks base method.
+func (m *MockStore) AdvanceUserTOTPStep(arg0 context.Context, arg1 db.AdvanceUserTOTPStepParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceUserTOTPStep", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdvanceUserTOTPStep indicates an expected call of AdvanceUserTOTPStep.
+func (mr *MockStoreMockRecorder) AdvanceUserTOTPStep(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceUserTOTPStep", reflect.TypeOf((*MockStore)(nil).AdvanceUserTOTPStep), arg0, arg1)
+}
+
+// AuthorizeTransferTx mocks base method.
+func (m *MockStore) AuthorizeTransferTx(arg0 context.Context, arg1 db.AuthorizeTransferTxParams) (*db.TransferHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthorizeTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.TransferHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthorizeTransferTx indicates an expected call of AuthorizeTransferTx.
+func (mr *MockStoreMockRecorder) AuthorizeTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthorizeTransferTx", reflect.TypeOf((*MockStore)(nil).AuthorizeTransferTx), arg0, arg1)
+}
+
+// BlockUserSessions mocks base method.
+func (m *MockStore) BlockUserSessions(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BlockUserSessions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// BlockUserSessions indicates an expected call of BlockUserSessions.
+func (mr *MockStoreMockRecorder) BlockUserSessions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockUserSessions", reflect.TypeOf((*MockStore)(nil).BlockUserSessions), arg0, arg1)
+}
+
+// CaptureTransferHoldTx mocks base method.
+func (m *MockStore) CaptureTransferHoldTx(arg0 context.Context, arg1 int64) (*db.CaptureTransferHoldTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CaptureTransferHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.CaptureTransferHoldTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CaptureTransferHoldTx indicates an expected call of CaptureTransferHoldTx.
+func (mr *MockStoreMockRecorder) CaptureTransferHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CaptureTransferHoldTx", reflect.TypeOf((*MockStore)(nil).CaptureTransferHoldTx), arg0, arg1)
+}
+
+// ChangePasswordTx mocks base method.
+func (m *MockStore) ChangePasswordTx(arg0 context.Context, arg1 db.UpdateUserPasswordParams) (*db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePasswordTx indicates an expected call of ChangePasswordTx.
+func (mr *MockStoreMockRecorder) ChangePasswordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePasswordTx", reflect.TypeOf((*MockStore)(nil).ChangePasswordTx), arg0, arg1)
+}
+
+// ClaimSigninThrottles mocks base method.
+func (m *MockStore) ClaimSigninThrottles(arg0 context.Context, arg1 []string) ([]db.SigninThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimSigninThrottles", arg0, arg1)
+	ret0, _ := ret[0].([]db.SigninThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimSigninThrottles indicates an expected call of ClaimSigninThrottles.
+func (mr *MockStoreMockRecorder) ClaimSigninThrottles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimSigninThrottles", reflect.TypeOf((*MockStore)(nil).ClaimSigninThrottles), arg0, arg1)
+}
+
+// CompleteSigninChallengeTx mocks base method.
+func (m *MockStore) CompleteSigninChallengeTx(arg0 context.Context, arg1 db.CompleteSigninChallengeTxParams) (*db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteSigninChallengeTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteSigninChallengeTx indicates an expected call of CompleteSigninChallengeTx.
+func (mr *MockStoreMockRecorder) CompleteSigninChallengeTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteSigninChallengeTx", reflect.TypeOf((*MockStore)(nil).CompleteSigninChallengeTx), arg0, arg1)
+}
+
+// ConsumeStepUpToken mocks base method.
+func (m *MockStore) ConsumeStepUpToken(arg0 context.Context, arg1 db.ConsumeStepUpTokenParams) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeStepUpToken", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeStepUpToken indicates an expected call of ConsumeStepUpToken.
+func (mr *MockStoreMockRecorder) ConsumeStepUpToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeStepUpToken", reflect.TypeOf((*MockStore)(nil).ConsumeStepUpToken), arg0, arg1)
+}
+
+// CountPasswordResetsSince mocks base method.
+func (m *MockStore) CountPasswordResetsSince(arg0 context.Context, arg1 db.CountPasswordResetsSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPasswordResetsSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPasswordResetsSince indicates an expected call of CountPasswordResetsSince.
+func (mr *MockStoreMockRecorder) CountPasswordResetsSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPasswordResetsSince", reflect.TypeOf((*MockStore)(nil).CountPasswordResetsSince), arg0, arg1)
+}
+
+// CountUnusedRecoveryCodes mocks base method.
+func (m *MockStore) CountUnusedRecoveryCodes(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnusedRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnusedRecoveryCodes indicates an expected call of CountUnusedRecoveryCodes.
+func (mr *MockStoreMockRecorder) CountUnusedRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnusedRecoveryCodes", reflect.TypeOf((*MockStore)(nil).CountUnusedRecoveryCodes), arg0, arg1)
+}
+
+// CountVerifyEmailsSince mocks base method.
+func (m *MockStore) CountVerifyEmailsSince(arg0 context.Context, arg1 db.CountVerifyEmailsSinceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountVerifyEmailsSince", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountVerifyEmailsSince indicates an expected call of CountVerifyEmailsSince.
+func (mr *MockStoreMockRecorder) CountVerifyEmailsSince(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountVerifyEmailsSince", reflect.TypeOf((*MockStore)(nil).CountVerifyEmailsSince), arg0, arg1)
+}
+
 // CreateAccount mocks base method.
 func (m *MockStore) CreateAccount(arg0 context.Context, arg1 db.CreateAccountParams) (db.Account, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "CreateAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccount indicates an expected call of CreateAccount.
+func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
+}
+
+// CreateAuthAudit mocks base method.
+func (m *MockStore) CreateAuthAudit(arg0 context.Context, arg1 db.CreateAuthAuditParams) (db.AuthAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuthAudit", arg0, arg1)
+	ret0, _ := ret[0].(db.AuthAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuthAudit indicates an expected call of CreateAuthAudit.
+func (mr *MockStoreMockRecorder) CreateAuthAudit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuthAudit", reflect.TypeOf((*MockStore)(nil).CreateAuthAudit), arg0, arg1)
+}
+
+// CreateBalanceEntry mocks base method.
+func (m *MockStore) CreateBalanceEntry(arg0 context.Context, arg1 db.CreateBalanceEntryParams) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBalanceEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBalanceEntry indicates an expected call of CreateBalanceEntry.
+func (mr *MockStoreMockRecorder) CreateBalanceEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBalanceEntry", reflect.TypeOf((*MockStore)(nil).CreateBalanceEntry), arg0, arg1)
+}
+
+// CreateFeePolicy mocks base method.
+func (m *MockStore) CreateFeePolicy(arg0 context.Context, arg1 db.CreateFeePolicyParams) (db.FeePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeePolicy", arg0, arg1)
+	ret0, _ := ret[0].(db.FeePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFeePolicy indicates an expected call of CreateFeePolicy.
+func (mr *MockStoreMockRecorder) CreateFeePolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeePolicy", reflect.TypeOf((*MockStore)(nil).CreateFeePolicy), arg0, arg1)
+}
+
+// CreateInterestAccrual mocks base method.
+func (m *MockStore) CreateInterestAccrual(arg0 context.Context, arg1 db.CreateInterestAccrualParams) (db.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestAccrual", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterestAccrual indicates an expected call of CreateInterestAccrual.
+func (mr *MockStoreMockRecorder) CreateInterestAccrual(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestAccrual", reflect.TypeOf((*MockStore)(nil).CreateInterestAccrual), arg0, arg1)
+}
+
+// CreateInterestRate mocks base method.
+func (m *MockStore) CreateInterestRate(arg0 context.Context, arg1 db.CreateInterestRateParams) (db.InterestRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInterestRate", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateInterestRate indicates an expected call of CreateInterestRate.
+func (mr *MockStoreMockRecorder) CreateInterestRate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInterestRate", reflect.TypeOf((*MockStore)(nil).CreateInterestRate), arg0, arg1)
+}
+
+// CreateOverdraftAccrual mocks base method.
+func (m *MockStore) CreateOverdraftAccrual(arg0 context.Context, arg1 db.CreateOverdraftAccrualParams) (db.OverdraftAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOverdraftAccrual", arg0, arg1)
+	ret0, _ := ret[0].(db.OverdraftAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOverdraftAccrual indicates an expected call of CreateOverdraftAccrual.
+func (mr *MockStoreMockRecorder) CreateOverdraftAccrual(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOverdraftAccrual", reflect.TypeOf((*MockStore)(nil).CreateOverdraftAccrual), arg0, arg1)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *MockStore) CreatePasswordReset(arg0 context.Context, arg1 db.CreatePasswordResetParams) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockStoreMockRecorder) CreatePasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockStore)(nil).CreatePasswordReset), arg0, arg1)
+}
+
+// CreateRecoveryCode mocks base method.
+func (m *MockStore) CreateRecoveryCode(arg0 context.Context, arg1 db.CreateRecoveryCodeParams) (db.RecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.RecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecoveryCode indicates an expected call of CreateRecoveryCode.
+func (mr *MockStoreMockRecorder) CreateRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCode", reflect.TypeOf((*MockStore)(nil).CreateRecoveryCode), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+}
+
+// CreateSigninChallenge mocks base method.
+func (m *MockStore) CreateSigninChallenge(arg0 context.Context, arg1 db.CreateSigninChallengeParams) (db.SigninChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSigninChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.SigninChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSigninChallenge indicates an expected call of CreateSigninChallenge.
+func (mr *MockStoreMockRecorder) CreateSigninChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSigninChallenge", reflect.TypeOf((*MockStore)(nil).CreateSigninChallenge), arg0, arg1)
+}
+
+// CreateStepUpChallenge mocks base method.
+func (m *MockStore) CreateStepUpChallenge(arg0 context.Context, arg1 db.CreateStepUpChallengeParams) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStepUpChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStepUpChallenge indicates an expected call of CreateStepUpChallenge.
+func (mr *MockStoreMockRecorder) CreateStepUpChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStepUpChallenge", reflect.TypeOf((*MockStore)(nil).CreateStepUpChallenge), arg0, arg1)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+}
+
+// CreateTransferHold mocks base method.
+func (m *MockStore) CreateTransferHold(arg0 context.Context, arg1 db.CreateTransferHoldParams) (db.TransferHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferHold", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferHold indicates an expected call of CreateTransferHold.
+func (mr *MockStoreMockRecorder) CreateTransferHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferHold", reflect.TypeOf((*MockStore)(nil).CreateTransferHold), arg0, arg1)
+}
+
+// CreateTransferReversal mocks base method.
+func (m *MockStore) CreateTransferReversal(arg0 context.Context, arg1 db.CreateTransferReversalParams) (db.TransferReversal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferReversal", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferReversal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferReversal indicates an expected call of CreateTransferReversal.
+func (mr *MockStoreMockRecorder) CreateTransferReversal(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferReversal", reflect.TypeOf((*MockStore)(nil).CreateTransferReversal), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateVerifyEmail mocks base method.
+func (m *MockStore) CreateVerifyEmail(arg0 context.Context, arg1 db.CreateVerifyEmailParams) (db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVerifyEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifyEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVerifyEmail indicates an expected call of CreateVerifyEmail.
+func (mr *MockStoreMockRecorder) CreateVerifyEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVerifyEmail", reflect.TypeOf((*MockStore)(nil).CreateVerifyEmail), arg0, arg1)
+}
+
+// DeactivateFeePolicy mocks base method.
+func (m *MockStore) DeactivateFeePolicy(arg0 context.Context, arg1 int64) (db.FeePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateFeePolicy", arg0, arg1)
+	ret0, _ := ret[0].(db.FeePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeactivateFeePolicy indicates an expected call of DeactivateFeePolicy.
+func (mr *MockStoreMockRecorder) DeactivateFeePolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateFeePolicy", reflect.TypeOf((*MockStore)(nil).DeactivateFeePolicy), arg0, arg1)
+}
+
+// DeleteAccount mocks base method.
+func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccount indicates an expected call of DeleteAccount.
+func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+}
+
+// DeleteSigninThrottle mocks base method.
+func (m *MockStore) DeleteSigninThrottle(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSigninThrottle", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSigninThrottle indicates an expected call of DeleteSigninThrottle.
+func (mr *MockStoreMockRecorder) DeleteSigninThrottle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSigninThrottle", reflect.TypeOf((*MockStore)(nil).DeleteSigninThrottle), arg0, arg1)
+}
+
+// DeleteStaleRateLimitBuckets mocks base method.
+func (m *MockStore) DeleteStaleRateLimitBuckets(arg0 context.Context, arg1 pgtype.Timestamptz) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStaleRateLimitBuckets", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteStaleRateLimitBuckets indicates an expected call of DeleteStaleRateLimitBuckets.
+func (mr *MockStoreMockRecorder) DeleteStaleRateLimitBuckets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStaleRateLimitBuckets", reflect.TypeOf((*MockStore)(nil).DeleteStaleRateLimitBuckets), arg0, arg1)
+}
+
+// DeleteUserRecoveryCodes mocks base method.
+func (m *MockStore) DeleteUserRecoveryCodes(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserRecoveryCodes indicates an expected call of DeleteUserRecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteUserRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserRecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteUserRecoveryCodes), arg0, arg1)
+}
+
+// DisableTOTPTx mocks base method.
+func (m *MockStore) DisableTOTPTx(arg0 context.Context, arg1 db.DisableTOTPTxParams) (*db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTOTPTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableTOTPTx indicates an expected call of DisableTOTPTx.
+func (mr *MockStoreMockRecorder) DisableTOTPTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTOTPTx", reflect.TypeOf((*MockStore)(nil).DisableTOTPTx), arg0, arg1)
+}
+
+// DisableUserTOTP mocks base method.
+func (m *MockStore) DisableUserTOTP(arg0 context.Context, arg1 int64) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableUserTOTP indicates an expected call of DisableUserTOTP.
+func (mr *MockStoreMockRecorder) DisableUserTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUserTOTP", reflect.TypeOf((*MockStore)(nil).DisableUserTOTP), arg0, arg1)
+}
+
+// EnableTOTPTx mocks base method.
+func (m *MockStore) EnableTOTPTx(arg0 context.Context, arg1 db.EnableTOTPTxParams) (*db.EnableTOTPTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTPTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.EnableTOTPTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTOTPTx indicates an expected call of EnableTOTPTx.
+func (mr *MockStoreMockRecorder) EnableTOTPTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTPTx", reflect.TypeOf((*MockStore)(nil).EnableTOTPTx), arg0, arg1)
+}
+
+// EnableUserTOTP mocks base method.
+func (m *MockStore) EnableUserTOTP(arg0 context.Context, arg1 int64) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUserTOTP", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableUserTOTP indicates an expected call of EnableUserTOTP.
+func (mr *MockStoreMockRecorder) EnableUserTOTP(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUserTOTP", reflect.TypeOf((*MockStore)(nil).EnableUserTOTP), arg0, arg1)
+}
+
+// ExpireTransferHoldsTx mocks base method.
+func (m *MockStore) ExpireTransferHoldsTx(arg0 context.Context, arg1 int32) ([]db.TransferHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireTransferHoldsTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireTransferHoldsTx indicates an expected call of ExpireTransferHoldsTx.
+func (mr *MockStoreMockRecorder) ExpireTransferHoldsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireTransferHoldsTx", reflect.TypeOf((*MockStore)(nil).ExpireTransferHoldsTx), arg0, arg1)
+}
+
+// ExpireUserPasswordResets mocks base method.
+func (m *MockStore) ExpireUserPasswordResets(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireUserPasswordResets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireUserPasswordResets indicates an expected call of ExpireUserPasswordResets.
+func (mr *MockStoreMockRecorder) ExpireUserPasswordResets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireUserPasswordResets", reflect.TypeOf((*MockStore)(nil).ExpireUserPasswordResets), arg0, arg1)
+}
+
+// GetAccountBalanceAt mocks base method.
+func (m *MockStore) GetAccountBalanceAt(arg0 context.Context, arg1 db.GetAccountBalanceAtParams) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountBalanceAt", arg0, arg1)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountBalanceAt indicates an expected call of GetAccountBalanceAt.
+func (mr *MockStoreMockRecorder) GetAccountBalanceAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountBalanceAt", reflect.TypeOf((*MockStore)(nil).GetAccountBalanceAt), arg0, arg1)
+}
+
+// GetAccountBalanceEntries mocks base method.
+func (m *MockStore) GetAccountBalanceEntries(arg0 context.Context, arg1 pgtype.Int8) ([]db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountBalanceEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountBalanceEntries indicates an expected call of GetAccountBalanceEntries.
+func (mr *MockStoreMockRecorder) GetAccountBalanceEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountBalanceEntries", reflect.TypeOf((*MockStore)(nil).GetAccountBalanceEntries), arg0, arg1)
+}
+
+// GetAccountByID mocks base method.
+func (m *MockStore) GetAccountByID(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByID indicates an expected call of GetAccountByID.
+func (mr *MockStoreMockRecorder) GetAccountByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockStore)(nil).GetAccountByID), arg0, arg1)
+}
+
+// GetAccountByIDForUpdate mocks base method.
+func (m *MockStore) GetAccountByIDForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountByIDForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountByIDForUpdate indicates an expected call of GetAccountByIDForUpdate.
+func (mr *MockStoreMockRecorder) GetAccountByIDForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByIDForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountByIDForUpdate), arg0, arg1)
+}
+
+// GetAccounts mocks base method.
+func (m *MockStore) GetAccounts(arg0 context.Context, arg1 db.GetAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccounts indicates an expected call of GetAccounts.
+func (mr *MockStoreMockRecorder) GetAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccounts", reflect.TypeOf((*MockStore)(nil).GetAccounts), arg0, arg1)
+}
+
+// GetAccountsByType mocks base method.
+func (m *MockStore) GetAccountsByType(arg0 context.Context, arg1 db.GetAccountsByTypeParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountsByType", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountsByType indicates an expected call of GetAccountsByType.
+func (mr *MockStoreMockRecorder) GetAccountsByType(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountsByType", reflect.TypeOf((*MockStore)(nil).GetAccountsByType), arg0, arg1)
+}
+
+// GetActiveFeePolicy mocks base method.
+func (m *MockStore) GetActiveFeePolicy(arg0 context.Context, arg1 string) (db.FeePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveFeePolicy", arg0, arg1)
+	ret0, _ := ret[0].(db.FeePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveFeePolicy indicates an expected call of GetActiveFeePolicy.
+func (mr *MockStoreMockRecorder) GetActiveFeePolicy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveFeePolicy", reflect.TypeOf((*MockStore)(nil).GetActiveFeePolicy), arg0, arg1)
+}
+
+// GetBalanceEntry mocks base method.
+func (m *MockStore) GetBalanceEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceEntry", arg0, arg1)
+	ret0, _ := ret[0].(db.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceEntry indicates an expected call of GetBalanceEntry.
+func (mr *MockStoreMockRecorder) GetBalanceEntry(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceEntry", reflect.TypeOf((*MockStore)(nil).GetBalanceEntry), arg0, arg1)
+}
+
+// GetEffectiveInterestRate mocks base method.
+func (m *MockStore) GetEffectiveInterestRate(arg0 context.Context, arg1 db.GetEffectiveInterestRateParams) (db.InterestRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEffectiveInterestRate", arg0, arg1)
+	ret0, _ := ret[0].(db.InterestRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEffectiveInterestRate indicates an expected call of GetEffectiveInterestRate.
+func (mr *MockStoreMockRecorder) GetEffectiveInterestRate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEffectiveInterestRate", reflect.TypeOf((*MockStore)(nil).GetEffectiveInterestRate), arg0, arg1)
+}
+
+// GetExpiredTransferHolds mocks base method.
+func (m *MockStore) GetExpiredTransferHolds(arg0 context.Context, arg1 int32) ([]db.TransferHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredTransferHolds", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredTransferHolds indicates an expected call of GetExpiredTransferHolds.
+func (mr *MockStoreMockRecorder) GetExpiredTransferHolds(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredTransferHolds", reflect.TypeOf((*MockStore)(nil).GetExpiredTransferHolds), arg0, arg1)
+}
+
+// GetFeePolicies mocks base method.
+func (m *MockStore) GetFeePolicies(arg0 context.Context, arg1 pgtype.Text) ([]db.FeePolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFeePolicies", arg0, arg1)
+	ret0, _ := ret[0].([]db.FeePolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFeePolicies indicates an expected call of GetFeePolicies.
+func (mr *MockStoreMockRecorder) GetFeePolicies(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFeePolicies", reflect.TypeOf((*MockStore)(nil).GetFeePolicies), arg0, arg1)
+}
+
+// GetInterestAccruals mocks base method.
+func (m *MockStore) GetInterestAccruals(arg0 context.Context, arg1 int64) ([]db.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInterestAccruals", arg0, arg1)
+	ret0, _ := ret[0].([]db.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInterestAccruals indicates an expected call of GetInterestAccruals.
+func (mr *MockStoreMockRecorder) GetInterestAccruals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterestAccruals", reflect.TypeOf((*MockStore)(nil).GetInterestAccruals), arg0, arg1)
+}
+
+// GetInterestRates mocks base method.
+func (m *MockStore) GetInterestRates(arg0 context.Context) ([]db.InterestRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInterestRates", arg0)
+	ret0, _ := ret[0].([]db.InterestRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInterestRates indicates an expected call of GetInterestRates.
+func (mr *MockStoreMockRecorder) GetInterestRates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInterestRates", reflect.TypeOf((*MockStore)(nil).GetInterestRates), arg0)
+}
+
+// GetJobWatermark mocks base method.
+func (m *MockStore) GetJobWatermark(arg0 context.Context, arg1 string) (db.JobWatermark, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobWatermark", arg0, arg1)
+	ret0, _ := ret[0].(db.JobWatermark)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobWatermark indicates an expected call of GetJobWatermark.
+func (mr *MockStoreMockRecorder) GetJobWatermark(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobWatermark", reflect.TypeOf((*MockStore)(nil).GetJobWatermark), arg0, arg1)
+}
+
+// GetOldestAccountCreatedAt mocks base method.
+func (m *MockStore) GetOldestAccountCreatedAt(arg0 context.Context) (pgtype.Timestamptz, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOldestAccountCreatedAt", arg0)
+	ret0, _ := ret[0].(pgtype.Timestamptz)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOldestAccountCreatedAt indicates an expected call of GetOldestAccountCreatedAt.
+func (mr *MockStoreMockRecorder) GetOldestAccountCreatedAt(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOldestAccountCreatedAt", reflect.TypeOf((*MockStore)(nil).GetOldestAccountCreatedAt), arg0)
+}
+
+// GetOverdraftAccruals mocks base method.
+func (m *MockStore) GetOverdraftAccruals(arg0 context.Context, arg1 int64) ([]db.OverdraftAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverdraftAccruals", arg0, arg1)
+	ret0, _ := ret[0].([]db.OverdraftAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverdraftAccruals indicates an expected call of GetOverdraftAccruals.
+func (mr *MockStoreMockRecorder) GetOverdraftAccruals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverdraftAccruals", reflect.TypeOf((*MockStore)(nil).GetOverdraftAccruals), arg0, arg1)
+}
+
+// GetOverdrawnAccounts mocks base method.
+func (m *MockStore) GetOverdrawnAccounts(arg0 context.Context, arg1 db.GetOverdrawnAccountsParams) ([]db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOverdrawnAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOverdrawnAccounts indicates an expected call of GetOverdrawnAccounts.
+func (mr *MockStoreMockRecorder) GetOverdrawnAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverdrawnAccounts", reflect.TypeOf((*MockStore)(nil).GetOverdrawnAccounts), arg0, arg1)
+}
+
+// GetPasswordResetForUpdate mocks base method.
+func (m *MockStore) GetPasswordResetForUpdate(arg0 context.Context, arg1 string) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordResetForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordResetForUpdate indicates an expected call of GetPasswordResetForUpdate.
+func (mr *MockStoreMockRecorder) GetPasswordResetForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordResetForUpdate", reflect.TypeOf((*MockStore)(nil).GetPasswordResetForUpdate), arg0, arg1)
+}
+
+// GetPasswordResetUser mocks base method.
+func (m *MockStore) GetPasswordResetUser(arg0 context.Context, arg1 string) (db.GetPasswordResetUserRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordResetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.GetPasswordResetUserRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordResetUser indicates an expected call of GetPasswordResetUser.
+func (mr *MockStoreMockRecorder) GetPasswordResetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordResetUser", reflect.TypeOf((*MockStore)(nil).GetPasswordResetUser), arg0, arg1)
+}
+
+// GetSessionByUniqueID mocks base method.
+func (m *MockStore) GetSessionByUniqueID(arg0 context.Context, arg1 db.GetSessionByUniqueIDParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionByUniqueID", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionByUniqueID indicates an expected call of GetSessionByUniqueID.
+func (mr *MockStoreMockRecorder) GetSessionByUniqueID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionByUniqueID", reflect.TypeOf((*MockStore)(nil).GetSessionByUniqueID), arg0, arg1)
+}
+
+// GetSessionList mocks base method.
+func (m *MockStore) GetSessionList(arg0 context.Context, arg1 db.GetSessionListParams) ([]db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionList", arg0, arg1)
+	ret0, _ := ret[0].([]db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionList indicates an expected call of GetSessionList.
+func (mr *MockStoreMockRecorder) GetSessionList(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionList", reflect.TypeOf((*MockStore)(nil).GetSessionList), arg0, arg1)
+}
+
+// GetSigninChallengeForUpdate mocks base method.
+func (m *MockStore) GetSigninChallengeForUpdate(arg0 context.Context, arg1 string) (db.SigninChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSigninChallengeForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.SigninChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSigninChallengeForUpdate indicates an expected call of GetSigninChallengeForUpdate.
+func (mr *MockStoreMockRecorder) GetSigninChallengeForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSigninChallengeForUpdate", reflect.TypeOf((*MockStore)(nil).GetSigninChallengeForUpdate), arg0, arg1)
+}
+
+// GetSigninThrottles mocks base method.
+func (m *MockStore) GetSigninThrottles(arg0 context.Context, arg1 []string) ([]db.SigninThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSigninThrottles", arg0, arg1)
+	ret0, _ := ret[0].([]db.SigninThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSigninThrottles indicates an expected call of GetSigninThrottles.
+func (mr *MockStoreMockRecorder) GetSigninThrottles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSigninThrottles", reflect.TypeOf((*MockStore)(nil).GetSigninThrottles), arg0, arg1)
+}
+
+// GetStatementEntries mocks base method.
+func (m *MockStore) GetStatementEntries(arg0 context.Context, arg1 db.GetStatementEntriesParams) ([]db.GetStatementEntriesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatementEntries", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetStatementEntriesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatementEntries indicates an expected call of GetStatementEntries.
+func (mr *MockStoreMockRecorder) GetStatementEntries(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatementEntries", reflect.TypeOf((*MockStore)(nil).GetStatementEntries), arg0, arg1)
+}
+
+// GetStepUpChallenge mocks base method.
+func (m *MockStore) GetStepUpChallenge(arg0 context.Context, arg1 pgtype.UUID) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStepUpChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStepUpChallenge indicates an expected call of GetStepUpChallenge.
+func (mr *MockStoreMockRecorder) GetStepUpChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStepUpChallenge", reflect.TypeOf((*MockStore)(nil).GetStepUpChallenge), arg0, arg1)
+}
+
+// GetSystemAccount mocks base method.
+func (m *MockStore) GetSystemAccount(arg0 context.Context, arg1 db.GetSystemAccountParams) (db.SystemAccount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSystemAccount", arg0, arg1)
+	ret0, _ := ret[0].(db.SystemAccount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSystemAccount indicates an expected call of GetSystemAccount.
+func (mr *MockStoreMockRecorder) GetSystemAccount(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSystemAccount", reflect.TypeOf((*MockStore)(nil).GetSystemAccount), arg0, arg1)
+}
+
+// GetTransfer mocks base method.
+func (m *MockStore) GetTransfer(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransfer indicates an expected call of GetTransfer.
+func (mr *MockStoreMockRecorder) GetTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransfer", reflect.TypeOf((*MockStore)(nil).GetTransfer), arg0, arg1)
+}
+
+// GetTransferForUpdate mocks base method.
+func (m *MockStore) GetTransferForUpdate(arg0 context.Context, arg1 int64) (db.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferForUpdate indicates an expected call of GetTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferForUpdate), arg0, arg1)
+}
+
+// GetTransferHold mocks base method.
+func (m *MockStore) GetTransferHold(arg0 context.Context, arg1 int64) (db.TransferHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferHold", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferHold indicates an expected call of GetTransferHold.
+func (mr *MockStoreMockRecorder) GetTransferHold(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferHold", reflect.TypeOf((*MockStore)(nil).GetTransferHold), arg0, arg1)
+}
+
+// GetTransferHoldForUpdate mocks base method.
+func (m *MockStore) GetTransferHoldForUpdate(arg0 context.Context, arg1 int64) (db.TransferHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferHoldForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferHoldForUpdate indicates an expected call of GetTransferHoldForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferHoldForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferHoldForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferHoldForUpdate), arg0, arg1)
+}
+
+// GetTransferReversals mocks base method.
+func (m *MockStore) GetTransferReversals(arg0 context.Context, arg1 int64) ([]db.TransferReversal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferReversals", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransferReversal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferReversals indicates an expected call of GetTransferReversals.
+func (mr *MockStoreMockRecorder) GetTransferReversals(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferReversals", reflect.TypeOf((*MockStore)(nil).GetTransferReversals), arg0, arg1)
+}
+
+// GetUnpostedInterestAccountIDs mocks base method.
+func (m *MockStore) GetUnpostedInterestAccountIDs(arg0 context.Context, arg1 db.GetUnpostedInterestAccountIDsParams) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnpostedInterestAccountIDs", arg0, arg1)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnpostedInterestAccountIDs indicates an expected call of GetUnpostedInterestAccountIDs.
+func (mr *MockStoreMockRecorder) GetUnpostedInterestAccountIDs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnpostedInterestAccountIDs", reflect.TypeOf((*MockStore)(nil).GetUnpostedInterestAccountIDs), arg0, arg1)
+}
+
+// GetUnpostedInterestAccrualsForUpdate mocks base method.
+func (m *MockStore) GetUnpostedInterestAccrualsForUpdate(arg0 context.Context, arg1 db.GetUnpostedInterestAccrualsForUpdateParams) ([]db.InterestAccrual, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnpostedInterestAccrualsForUpdate", arg0, arg1)
+	ret0, _ := ret[0].([]db.InterestAccrual)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnpostedInterestAccrualsForUpdate indicates an expected call of GetUnpostedInterestAccrualsForUpdate.
+func (mr *MockStoreMockRecorder) GetUnpostedInterestAccrualsForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnpostedInterestAccrualsForUpdate", reflect.TypeOf((*MockStore)(nil).GetUnpostedInterestAccrualsForUpdate), arg0, arg1)
+}
+
+// GetUserByUniqueID mocks base method.
+func (m *MockStore) GetUserByUniqueID(arg0 context.Context, arg1 db.GetUserByUniqueIDParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUniqueID", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUniqueID indicates an expected call of GetUserByUniqueID.
+func (mr *MockStoreMockRecorder) GetUserByUniqueID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUniqueID", reflect.TypeOf((*MockStore)(nil).GetUserByUniqueID), arg0, arg1)
+}
+
+// GetUserForUpdate mocks base method.
+func (m *MockStore) GetUserForUpdate(arg0 context.Context, arg1 int64) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserForUpdate indicates an expected call of GetUserForUpdate.
+func (mr *MockStoreMockRecorder) GetUserForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserForUpdate", reflect.TypeOf((*MockStore)(nil).GetUserForUpdate), arg0, arg1)
+}
+
+// GetUsers mocks base method.
+func (m *MockStore) GetUsers(arg0 context.Context, arg1 db.GetUsersParams) ([]db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockStoreMockRecorder) GetUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockStore)(nil).GetUsers), arg0, arg1)
+}
+
+// GetVerifyEmailForUpdate mocks base method.
+func (m *MockStore) GetVerifyEmailForUpdate(arg0 context.Context, arg1 string) (db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVerifyEmailForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifyEmail)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateAccount indicates an expected call of CreateAccount.
-func (mr *MockStoreMockRecorder) CreateAccount(arg0, arg1 interface{}) *gomock.Call {
+// GetVerifyEmailForUpdate indicates an expected call of GetVerifyEmailForUpdate.
+func (mr *MockStoreMockRecorder) GetVerifyEmailForUpdate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockStore)(nil).CreateAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerifyEmailForUpdate", reflect.TypeOf((*MockStore)(nil).GetVerifyEmailForUpdate), arg0, arg1)
 }
 
-// CreateBalanceEntry mocks base method.
-func (m *MockStore) CreateBalanceEntry(arg0 context.Context, arg1 db.CreateBalanceEntryParams) (db.Entry, error) {
+// IncrementSigninChallengeAttempts mocks base method.
+func (m *MockStore) IncrementSigninChallengeAttempts(arg0 context.Context, arg1 int64) (db.SigninChallenge, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateBalanceEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "IncrementSigninChallengeAttempts", arg0, arg1)
+	ret0, _ := ret[0].(db.SigninChallenge)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateBalanceEntry indicates an expected call of CreateBalanceEntry.
-func (mr *MockStoreMockRecorder) CreateBalanceEntry(arg0, arg1 interface{}) *gomock.Call {
+// IncrementSigninChallengeAttempts indicates an expected call of IncrementSigninChallengeAttempts.
+func (mr *MockStoreMockRecorder) IncrementSigninChallengeAttempts(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBalanceEntry", reflect.TypeOf((*MockStore)(nil).CreateBalanceEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementSigninChallengeAttempts", reflect.TypeOf((*MockStore)(nil).IncrementSigninChallengeAttempts), arg0, arg1)
 }
 
-// CreateSession mocks base method.
-func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+// IssueStepUpToken mocks base method.
+func (m *MockStore) IssueStepUpToken(arg0 context.Context, arg1 db.IssueStepUpTokenParams) (db.StepUpChallenge, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "IssueStepUpToken", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSession indicates an expected call of CreateSession.
-func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 interface{}) *gomock.Call {
+// IssueStepUpToken indicates an expected call of IssueStepUpToken.
+func (mr *MockStoreMockRecorder) IssueStepUpToken(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueStepUpToken", reflect.TypeOf((*MockStore)(nil).IssueStepUpToken), arg0, arg1)
 }
 
-// CreateTransfer mocks base method.
-func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transfer, error) {
+// ListUserAuthAudit mocks base method.
+func (m *MockStore) ListUserAuthAudit(arg0 context.Context, arg1 db.ListUserAuthAuditParams) ([]db.AuthAudit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transfer)
+	ret := m.ctrl.Call(m, "ListUserAuthAudit", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuthAudit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransfer indicates an expected call of CreateTransfer.
-func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+// ListUserAuthAudit indicates an expected call of ListUserAuthAudit.
+func (mr *MockStoreMockRecorder) ListUserAuthAudit(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserAuthAudit", reflect.TypeOf((*MockStore)(nil).ListUserAuthAudit), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// LockSigninThrottle mocks base method.
+func (m *MockStore) LockSigninThrottle(arg0 context.Context, arg1 db.LockSigninThrottleParams) (db.SigninThrottle, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret := m.ctrl.Call(m, "LockSigninThrottle", arg0, arg1)
+	ret0, _ := ret[0].(db.SigninThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockSigninThrottle indicates an expected call of LockSigninThrottle.
+func (mr *MockStoreMockRecorder) LockSigninThrottle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockSigninThrottle", reflect.TypeOf((*MockStore)(nil).LockSigninThrottle), arg0, arg1)
+}
+
+// MarkInterestAccrualsPosted mocks base method.
+func (m *MockStore) MarkInterestAccrualsPosted(arg0 context.Context, arg1 db.MarkInterestAccrualsPostedParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkInterestAccrualsPosted", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkInterestAccrualsPosted indicates an expected call of MarkInterestAccrualsPosted.
+func (mr *MockStoreMockRecorder) MarkInterestAccrualsPosted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkInterestAccrualsPosted", reflect.TypeOf((*MockStore)(nil).MarkInterestAccrualsPosted), arg0, arg1)
+}
+
+// MarkPasswordResetUsed mocks base method.
+func (m *MockStore) MarkPasswordResetUsed(arg0 context.Context, arg1 int64) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPasswordResetUsed", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkPasswordResetUsed indicates an expected call of MarkPasswordResetUsed.
+func (mr *MockStoreMockRecorder) MarkPasswordResetUsed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPasswordResetUsed", reflect.TypeOf((*MockStore)(nil).MarkPasswordResetUsed), arg0, arg1)
+}
+
+// MarkSigninChallengeUsed mocks base method.
+func (m *MockStore) MarkSigninChallengeUsed(arg0 context.Context, arg1 int64) (db.SigninChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSigninChallengeUsed", arg0, arg1)
+	ret0, _ := ret[0].(db.SigninChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkSigninChallengeUsed indicates an expected call of MarkSigninChallengeUsed.
+func (mr *MockStoreMockRecorder) MarkSigninChallengeUsed(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSigninChallengeUsed", reflect.TypeOf((*MockStore)(nil).MarkSigninChallengeUsed), arg0, arg1)
+}
+
+// MarkUserEmailVerified mocks base method.
+func (m *MockStore) MarkUserEmailVerified(arg0 context.Context, arg1 db.MarkUserEmailVerifiedParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUserEmailVerified", arg0, arg1)
 	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// MarkUserEmailVerified indicates an expected call of MarkUserEmailVerified.
+func (mr *MockStoreMockRecorder) MarkUserEmailVerified(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUserEmailVerified", reflect.TypeOf((*MockStore)(nil).MarkUserEmailVerified), arg0, arg1)
 }
 
-// DeleteAccount mocks base method.
-func (m *MockStore) DeleteAccount(arg0 context.Context, arg1 int64) error {
+// MarkVerifyEmailUsed mocks base method.
+func (m *MockStore) MarkVerifyEmailUsed(arg0 context.Context, arg1 int64) (db.VerifyEmail, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteAccount", arg0, arg1)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "MarkVerifyEmailUsed", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifyEmail)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// DeleteAccount indicates an expected call of DeleteAccount.
-func (mr *MockStoreMockRecorder) DeleteAccount(arg0, arg1 interface{}) *gomock.Call {
+// MarkVerifyEmailUsed indicates an expected call of MarkVerifyEmailUsed.
+func (mr *MockStoreMockRecorder) MarkVerifyEmailUsed(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccount", reflect.TypeOf((*MockStore)(nil).DeleteAccount), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkVerifyEmailUsed", reflect.TypeOf((*MockStore)(nil).MarkVerifyEmailUsed), arg0, arg1)
 }
 
-// GetAccountBalanceEntries mocks base method.
-func (m *MockStore) GetAccountBalanceEntries(arg0 context.Context, arg1 pgtype.Int8) ([]db.Entry, error) {
+// PostInterestTx mocks base method.
+func (m *MockStore) PostInterestTx(arg0 context.Context, arg1 db.PostInterestTxParams) (*db.PostInterestTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountBalanceEntries", arg0, arg1)
-	ret0, _ := ret[0].([]db.Entry)
+	ret := m.ctrl.Call(m, "PostInterestTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.PostInterestTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountBalanceEntries indicates an expected call of GetAccountBalanceEntries.
-func (mr *MockStoreMockRecorder) GetAccountBalanceEntries(arg0, arg1 interface{}) *gomock.Call {
+// PostInterestTx indicates an expected call of PostInterestTx.
+func (mr *MockStoreMockRecorder) PostInterestTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountBalanceEntries", reflect.TypeOf((*MockStore)(nil).GetAccountBalanceEntries), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostInterestTx", reflect.TypeOf((*MockStore)(nil).PostInterestTx), arg0, arg1)
 }
 
-// GetAccountByID mocks base method.
-func (m *MockStore) GetAccountByID(arg0 context.Context, arg1 int64) (db.Account, error) {
+// QuoteTransferFee mocks base method.
+func (m *MockStore) QuoteTransferFee(arg0 context.Context, arg1 string, arg2 float64) (*db.TransferFeeQuote, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountByID", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "QuoteTransferFee", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*db.TransferFeeQuote)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountByID indicates an expected call of GetAccountByID.
-func (mr *MockStoreMockRecorder) GetAccountByID(arg0, arg1 interface{}) *gomock.Call {
+// QuoteTransferFee indicates an expected call of QuoteTransferFee.
+func (mr *MockStoreMockRecorder) QuoteTransferFee(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByID", reflect.TypeOf((*MockStore)(nil).GetAccountByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteTransferFee", reflect.TypeOf((*MockStore)(nil).QuoteTransferFee), arg0, arg1, arg2)
 }
 
-// GetAccountByIDForUpdate mocks base method.
-func (m *MockStore) GetAccountByIDForUpdate(arg0 context.Context, arg1 int64) (db.Account, error) {
+// RecordSigninAttemptTx mocks base method.
+func (m *MockStore) RecordSigninAttemptTx(arg0 context.Context, arg1 db.RecordSigninAttemptTxParams) (*db.AuthAudit, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccountByIDForUpdate", arg0, arg1)
-	ret0, _ := ret[0].(db.Account)
+	ret := m.ctrl.Call(m, "RecordSigninAttemptTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.AuthAudit)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccountByIDForUpdate indicates an expected call of GetAccountByIDForUpdate.
-func (mr *MockStoreMockRecorder) GetAccountByIDForUpdate(arg0, arg1 interface{}) *gomock.Call {
+// RecordSigninAttemptTx indicates an expected call of RecordSigninAttemptTx.
+func (mr *MockStoreMockRecorder) RecordSigninAttemptTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountByIDForUpdate", reflect.TypeOf((*MockStore)(nil).GetAccountByIDForUpdate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSigninAttemptTx", reflect.TypeOf((*MockStore)(nil).RecordSigninAttemptTx), arg0, arg1)
 }
 
-// GetAccounts mocks base method.
-func (m *MockStore) GetAccounts(arg0 context.Context, arg1 db.GetAccountsParams) ([]db.Account, error) {
+// RecordSigninFailure mocks base method.
+func (m *MockStore) RecordSigninFailure(arg0 context.Context, arg1 db.RecordSigninFailureParams) (db.SigninThrottle, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAccounts", arg0, arg1)
-	ret0, _ := ret[0].([]db.Account)
+	ret := m.ctrl.Call(m, "RecordSigninFailure", arg0, arg1)
+	ret0, _ := ret[0].(db.SigninThrottle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAccounts indicates an expected call of GetAccounts.
-func (mr *MockStoreMockRecorder) GetAccounts(arg0, arg1 interface{}) *gomock.Call {
+// RecordSigninFailure indicates an expected call of RecordSigninFailure.
+func (mr *MockStoreMockRecorder) RecordSigninFailure(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccounts", reflect.TypeOf((*MockStore)(nil).GetAccounts), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSigninFailure", reflect.TypeOf((*MockStore)(nil).RecordSigninFailure), arg0, arg1)
 }
 
-// GetBalanceEntry mocks base method.
-func (m *MockStore) GetBalanceEntry(arg0 context.Context, arg1 int64) (db.Entry, error) {
+// RehashUserPassword mocks base method.
+func (m *MockStore) RehashUserPassword(arg0 context.Context, arg1 db.RehashUserPasswordParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetBalanceEntry", arg0, arg1)
-	ret0, _ := ret[0].(db.Entry)
+	ret := m.ctrl.Call(m, "RehashUserPassword", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetBalanceEntry indicates an expected call of GetBalanceEntry.
-func (mr *MockStoreMockRecorder) GetBalanceEntry(arg0, arg1 interface{}) *gomock.Call {
+// RehashUserPassword indicates an expected call of RehashUserPassword.
+func (mr *MockStoreMockRecorder) RehashUserPassword(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceEntry", reflect.TypeOf((*MockStore)(nil).GetBalanceEntry), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RehashUserPassword", reflect.TypeOf((*MockStore)(nil).RehashUserPassword), arg0, arg1)
 }
 
-// GetSessionByUniqueID mocks base method.
-func (m *MockStore) GetSessionByUniqueID(arg0 context.Context, arg1 db.GetSessionByUniqueIDParams) (db.Session, error) {
+// ReleaseSigninReservation mocks base method.
+func (m *MockStore) ReleaseSigninReservation(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSessionByUniqueID", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "ReleaseSigninReservation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseSigninReservation indicates an expected call of ReleaseSigninReservation.
+func (mr *MockStoreMockRecorder) ReleaseSigninReservation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseSigninReservation", reflect.TypeOf((*MockStore)(nil).ReleaseSigninReservation), arg0, arg1)
+}
+
+// ResendVerifyEmailTx mocks base method.
+func (m *MockStore) ResendVerifyEmailTx(arg0 context.Context, arg1 db.ResendVerifyEmailTxParams) (*db.VerifyEmail, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerifyEmailTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.VerifyEmail)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSessionByUniqueID indicates an expected call of GetSessionByUniqueID.
-func (mr *MockStoreMockRecorder) GetSessionByUniqueID(arg0, arg1 interface{}) *gomock.Call {
+// ResendVerifyEmailTx indicates an expected call of ResendVerifyEmailTx.
+func (mr *MockStoreMockRecorder) ResendVerifyEmailTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionByUniqueID", reflect.TypeOf((*MockStore)(nil).GetSessionByUniqueID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerifyEmailTx", reflect.TypeOf((*MockStore)(nil).ResendVerifyEmailTx), arg0, arg1)
 }
 
-// GetSessionList mocks base method.
-func (m *MockStore) GetSessionList(arg0 context.Context, arg1 db.GetSessionListParams) ([]db.Session, error) {
+// ReserveSigninAttempt mocks base method.
+func (m *MockStore) ReserveSigninAttempt(arg0 context.Context, arg1 db.ReserveSigninAttemptParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSessionList", arg0, arg1)
-	ret0, _ := ret[0].([]db.Session)
+	ret := m.ctrl.Call(m, "ReserveSigninAttempt", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReserveSigninAttempt indicates an expected call of ReserveSigninAttempt.
+func (mr *MockStoreMockRecorder) ReserveSigninAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSigninAttempt", reflect.TypeOf((*MockStore)(nil).ReserveSigninAttempt), arg0, arg1)
+}
+
+// ReserveSigninAttemptTx mocks base method.
+func (m *MockStore) ReserveSigninAttemptTx(arg0 context.Context, arg1 db.SigninRetryAtParams) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveSigninAttemptTx", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSessionList indicates an expected call of GetSessionList.
-func (mr *MockStoreMockRecorder) GetSessionList(arg0, arg1 interface{}) *gomock.Call {
+// ReserveSigninAttemptTx indicates an expected call of ReserveSigninAttemptTx.
+func (mr *MockStoreMockRecorder) ReserveSigninAttemptTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionList", reflect.TypeOf((*MockStore)(nil).GetSessionList), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSigninAttemptTx", reflect.TypeOf((*MockStore)(nil).ReserveSigninAttemptTx), arg0, arg1)
 }
 
-// GetUserByUniqueID mocks base method.
-func (m *MockStore) GetUserByUniqueID(arg0 context.Context, arg1 db.GetUserByUniqueIDParams) (db.User, error) {
+// ResetPasswordTx mocks base method.
+func (m *MockStore) ResetPasswordTx(arg0 context.Context, arg1 db.ResetPasswordTxParams) (*db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByUniqueID", arg0, arg1)
+	ret := m.ctrl.Call(m, "ResetPasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPasswordTx indicates an expected call of ResetPasswordTx.
+func (mr *MockStoreMockRecorder) ResetPasswordTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTx", reflect.TypeOf((*MockStore)(nil).ResetPasswordTx), arg0, arg1)
+}
+
+// ReverseTransferTx mocks base method.
+func (m *MockStore) ReverseTransferTx(arg0 context.Context, arg1 db.ReverseTransferTxParams) (*db.ReverseTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReverseTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.ReverseTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReverseTransferTx indicates an expected call of ReverseTransferTx.
+func (mr *MockStoreMockRecorder) ReverseTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReverseTransferTx", reflect.TypeOf((*MockStore)(nil).ReverseTransferTx), arg0, arg1)
+}
+
+// SetJobWatermark mocks base method.
+func (m *MockStore) SetJobWatermark(arg0 context.Context, arg1 db.SetJobWatermarkParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetJobWatermark", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetJobWatermark indicates an expected call of SetJobWatermark.
+func (mr *MockStoreMockRecorder) SetJobWatermark(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJobWatermark", reflect.TypeOf((*MockStore)(nil).SetJobWatermark), arg0, arg1)
+}
+
+// SetUserTOTPSecret mocks base method.
+func (m *MockStore) SetUserTOTPSecret(arg0 context.Context, arg1 db.SetUserTOTPSecretParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserTOTPSecret", arg0, arg1)
 	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByUniqueID indicates an expected call of GetUserByUniqueID.
-func (mr *MockStoreMockRecorder) GetUserByUniqueID(arg0, arg1 interface{}) *gomock.Call {
+// SetUserTOTPSecret indicates an expected call of SetUserTOTPSecret.
+func (mr *MockStoreMockRecorder) SetUserTOTPSecret(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUniqueID", reflect.TypeOf((*MockStore)(nil).GetUserByUniqueID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserTOTPSecret", reflect.TypeOf((*MockStore)(nil).SetUserTOTPSecret), arg0, arg1)
 }
 
-// GetUsers mocks base method.
-func (m *MockStore) GetUsers(arg0 context.Context, arg1 db.GetUsersParams) ([]db.User, error) {
+// SigninRetryAt mocks base method.
+func (m *MockStore) SigninRetryAt(arg0 context.Context, arg1 db.SigninRetryAtParams) (time.Time, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUsers", arg0, arg1)
-	ret0, _ := ret[0].([]db.User)
+	ret := m.ctrl.Call(m, "SigninRetryAt", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUsers indicates an expected call of GetUsers.
-func (mr *MockStoreMockRecorder) GetUsers(arg0, arg1 interface{}) *gomock.Call {
+// SigninRetryAt indicates an expected call of SigninRetryAt.
+func (mr *MockStoreMockRecorder) SigninRetryAt(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockStore)(nil).GetUsers), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SigninRetryAt", reflect.TypeOf((*MockStore)(nil).SigninRetryAt), arg0, arg1)
+}
+
+// TakeRateLimitToken mocks base method.
+func (m *MockStore) TakeRateLimitToken(arg0 context.Context, arg1 db.TakeRateLimitTokenParams) (db.RateLimitBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRateLimitToken", arg0, arg1)
+	ret0, _ := ret[0].(db.RateLimitBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeRateLimitToken indicates an expected call of TakeRateLimitToken.
+func (mr *MockStoreMockRecorder) TakeRateLimitToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRateLimitToken", reflect.TypeOf((*MockStore)(nil).TakeRateLimitToken), arg0, arg1)
+}
+
+// TakeStepUpAttempt mocks base method.
+func (m *MockStore) TakeStepUpAttempt(arg0 context.Context, arg1 db.TakeStepUpAttemptParams) (db.StepUpChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeStepUpAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.StepUpChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeStepUpAttempt indicates an expected call of TakeStepUpAttempt.
+func (mr *MockStoreMockRecorder) TakeStepUpAttempt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeStepUpAttempt", reflect.TypeOf((*MockStore)(nil).TakeStepUpAttempt), arg0, arg1)
 }
 
 // TransferTx mocks base method.
@@ -276,6 +1635,21 @@ func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
 }
 
+// UpdateAccountOverdraftLimit mocks base method.
+func (m *MockStore) UpdateAccountOverdraftLimit(arg0 context.Context, arg1 db.UpdateAccountOverdraftLimitParams) (db.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountOverdraftLimit", arg0, arg1)
+	ret0, _ := ret[0].(db.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountOverdraftLimit indicates an expected call of UpdateAccountOverdraftLimit.
+func (mr *MockStoreMockRecorder) UpdateAccountOverdraftLimit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountOverdraftLimit", reflect.TypeOf((*MockStore)(nil).UpdateAccountOverdraftLimit), arg0, arg1)
+}
+
 // UpdateBalance mocks base method.
 func (m *MockStore) UpdateBalance(arg0 context.Context, arg1 db.UpdateBalanceParams) (db.Account, error) {
 	m.ctrl.T.Helper()
@@ -305,3 +1679,92 @@ func (mr *MockStoreMockRecorder) UpdateTransferAccountBalance(arg0, arg1 interfa
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransferAccountBalance", reflect.TypeOf((*MockStore)(nil).UpdateTransferAccountBalance), arg0, arg1)
 }
+
+// UpdateTransferHoldStatus mocks base method.
+func (m *MockStore) UpdateTransferHoldStatus(arg0 context.Context, arg1 db.UpdateTransferHoldStatusParams) (db.TransferHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransferHoldStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.TransferHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTransferHoldStatus indicates an expected call of UpdateTransferHoldStatus.
+func (mr *MockStoreMockRecorder) UpdateTransferHoldStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransferHoldStatus", reflect.TypeOf((*MockStore)(nil).UpdateTransferHoldStatus), arg0, arg1)
+}
+
+// UpdateUserPassword mocks base method.
+func (m *MockStore) UpdateUserPassword(arg0 context.Context, arg1 db.UpdateUserPasswordParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPassword", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *MockStoreMockRecorder) UpdateUserPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockStore)(nil).UpdateUserPassword), arg0, arg1)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockStore) UseRecoveryCode(arg0 context.Context, arg1 db.UseRecoveryCodeParams) (db.RecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.RecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockStoreMockRecorder) UseRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockStore)(nil).UseRecoveryCode), arg0, arg1)
+}
+
+// VerifyEmailTx mocks base method.
+func (m *MockStore) VerifyEmailTx(arg0 context.Context, arg1 string) (*db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmailTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmailTx indicates an expected call of VerifyEmailTx.
+func (mr *MockStoreMockRecorder) VerifyEmailTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmailTx", reflect.TypeOf((*MockStore)(nil).VerifyEmailTx), arg0, arg1)
+}
+
+// VerifySecondFactorTx mocks base method.
+func (m *MockStore) VerifySecondFactorTx(arg0 context.Context, arg1 db.VerifySecondFactorTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifySecondFactorTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifySecondFactorTx indicates an expected call of VerifySecondFactorTx.
+func (mr *MockStoreMockRecorder) VerifySecondFactorTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifySecondFactorTx", reflect.TypeOf((*MockStore)(nil).VerifySecondFactorTx), arg0, arg1)
+}
+
+// VoidTransferHoldTx mocks base method.
+func (m *MockStore) VoidTransferHoldTx(arg0 context.Context, arg1 int64) (*db.TransferHold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoidTransferHoldTx", arg0, arg1)
+	ret0, _ := ret[0].(*db.TransferHold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoidTransferHoldTx indicates an expected call of VoidTransferHoldTx.
+func (mr *MockStoreMockRecorder) VoidTransferHoldTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoidTransferHoldTx", reflect.TypeOf((*MockStore)(nil).VoidTransferHoldTx), arg0, arg1)
+}
@@ -1,6 +1,6 @@
 -- name: CreateAccount :one
-INSERT INTO accounts(owner_id, balance, currency)
-VALUES ($1, $2, $3)
+INSERT INTO accounts(owner_id, balance, currency, type)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 
 -- name: GetAccountByID :one
@@ -41,3 +41,50 @@ CASE
     WHEN id = sqlc.arg('to_account_id') THEN balance + sqlc.arg('amount')
 END
 WHERE id IN (sqlc.arg('from_account_id'), sqlc.arg('to_account_id'));
+
+-- name: AddAccountBalance :one
+UPDATE accounts
+SET balance = balance + sqlc.arg('amount')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: AddAccountHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + sqlc.arg('amount')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = sqlc.arg('overdraft_limit')
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: GetOverdrawnAccounts :many
+-- Returns the accounts whose balance was negative at the given time, backing
+-- out entries booked since. System accounts such as interest expense run
+-- negative by design and are never charged.
+SELECT a.* FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id AND e.created_at >= sqlc.arg('at')
+WHERE a.id > sqlc.arg('after_id')
+  AND a.id NOT IN (SELECT account_id FROM system_accounts)
+GROUP BY a.id
+HAVING a.balance - COALESCE(SUM(e.amount), 0) < 0
+ORDER BY a.id
+LIMIT sqlc.arg('limit');
+
+-- name: GetAccountsByType :many
+SELECT * FROM accounts
+WHERE type = sqlc.arg('type') AND id > sqlc.arg('after_id')
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: GetOldestAccountCreatedAt :one
+SELECT min(created_at)::timestamptz AS created_at FROM accounts;
+
+-- name: GetAccountBalanceAt :one
+SELECT (a.balance - COALESCE(SUM(e.amount), 0))::float AS balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id AND e.created_at >= sqlc.arg('at')
+WHERE a.id = sqlc.arg('id')
+GROUP BY a.id;
@@ -0,0 +1,11 @@
+-- name: CreateAuthAudit :one
+INSERT INTO auth_audit(user_id, identifier, client_ip, user_agent, outcome, success)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListUserAuthAudit :many
+SELECT * FROM auth_audit
+WHERE user_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3;
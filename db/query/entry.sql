@@ -1,6 +1,6 @@
 -- name: CreateBalanceEntry :one
-INSERT INTO entries (account_id, amount)
-VALUES($1, $2)
+INSERT INTO entries (account_id, amount, transfer_id, kind)
+VALUES($1, $2, $3, $4)
 RETURNING *;
 
 
@@ -13,3 +13,15 @@ LIMIT 1;
 SELECT * FROM entries
 WHERE account_id = $1
 LIMIT 1;
+
+-- name: GetStatementEntries :many
+SELECT e.id, e.amount, e.kind, e.created_at, e.transfer_id,
+  t.from_account_id, t.to_account_id, t.original_transfer_id
+FROM entries e
+LEFT JOIN transfer t ON t.id = e.transfer_id
+WHERE e.account_id = sqlc.arg('account_id')
+  AND e.created_at >= sqlc.arg('from')
+  AND e.created_at < sqlc.arg('to')
+  AND e.id > sqlc.arg('after_id')
+ORDER BY e.id
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,33 @@
+-- name: CreateFeePolicy :one
+INSERT INTO fee_policies(currency, version, kind, flat_amount, percentage, min_fee, max_fee, tiers, effective_from)
+VALUES (
+  sqlc.narg('currency'),
+  (SELECT COALESCE(MAX(version), 0) + 1 FROM fee_policies WHERE currency IS NOT DISTINCT FROM sqlc.narg('currency')),
+  sqlc.arg('kind'),
+  sqlc.arg('flat_amount'),
+  sqlc.arg('percentage'),
+  sqlc.arg('min_fee'),
+  sqlc.narg('max_fee'),
+  sqlc.arg('tiers'),
+  sqlc.arg('effective_from')
+)
+RETURNING *;
+
+-- name: GetActiveFeePolicy :one
+SELECT * FROM fee_policies
+WHERE active
+  AND effective_from <= now()
+  AND (currency IS NULL OR currency = sqlc.arg('currency')::varchar)
+ORDER BY currency NULLS LAST, version DESC
+LIMIT 1;
+
+-- name: GetFeePolicies :many
+SELECT * FROM fee_policies
+WHERE (sqlc.narg('currency')::varchar IS NULL OR currency = sqlc.narg('currency')::varchar)
+ORDER BY currency NULLS LAST, version DESC;
+
+-- name: DeactivateFeePolicy :one
+UPDATE fee_policies
+SET active = FALSE
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,42 @@
+-- name: CreateInterestRate :one
+INSERT INTO interest_rates(account_type, currency, annual_rate, effective_from)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetInterestRates :many
+SELECT * FROM interest_rates
+ORDER BY account_type, currency, effective_from DESC;
+
+-- name: GetEffectiveInterestRate :one
+SELECT * FROM interest_rates
+WHERE account_type = $1 AND currency = $2 AND effective_from <= sqlc.arg('date')
+ORDER BY effective_from DESC
+LIMIT 1;
+
+-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals(account_id, accrual_date, balance, annual_rate, amount)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING *;
+
+-- name: GetInterestAccruals :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date DESC;
+
+-- name: GetUnpostedInterestAccountIDs :many
+SELECT DISTINCT account_id FROM interest_accruals
+WHERE posted_at IS NULL AND accrual_date < sqlc.arg('before') AND account_id > sqlc.arg('after_id')
+ORDER BY account_id
+LIMIT sqlc.arg('limit');
+
+-- name: GetUnpostedInterestAccrualsForUpdate :many
+SELECT * FROM interest_accruals
+WHERE account_id = $1 AND posted_at IS NULL AND accrual_date < sqlc.arg('before')
+ORDER BY accrual_date
+FOR UPDATE;
+
+-- name: MarkInterestAccrualsPosted :execrows
+UPDATE interest_accruals
+SET posted_at = now(), entry_id = sqlc.narg('entry_id')
+WHERE account_id = sqlc.arg('account_id') AND posted_at IS NULL AND accrual_date < sqlc.arg('before');
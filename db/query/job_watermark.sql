@@ -0,0 +1,10 @@
+-- name: GetJobWatermark :one
+SELECT * FROM job_watermarks
+WHERE job = $1;
+
+-- name: SetJobWatermark :exec
+INSERT INTO job_watermarks(job, done_through)
+VALUES ($1, $2)
+ON CONFLICT (job) DO UPDATE
+SET done_through = EXCLUDED.done_through,
+    updated_at = now();
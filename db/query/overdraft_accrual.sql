@@ -0,0 +1,10 @@
+-- name: CreateOverdraftAccrual :one
+INSERT INTO overdraft_accruals(account_id, accrual_date, balance, amount)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING *;
+
+-- name: GetOverdraftAccruals :many
+SELECT * FROM overdraft_accruals
+WHERE account_id = $1
+ORDER BY accrual_date DESC;
@@ -0,0 +1,30 @@
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets(user_id, token_hash, expires_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetPasswordResetForUpdate :one
+SELECT * FROM password_resets
+WHERE token_hash = $1
+LIMIT 1 FOR UPDATE;
+
+-- name: MarkPasswordResetUsed :one
+UPDATE password_resets
+SET used_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: ExpireUserPasswordResets :exec
+UPDATE password_resets
+SET used_at = now()
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: CountPasswordResetsSince :one
+SELECT count(*) FROM password_resets
+WHERE user_id = $1 AND created_at >= sqlc.arg('since');
+
+-- name: GetPasswordResetUser :one
+SELECT sqlc.embed(users), password_resets.used_at, password_resets.expires_at
+FROM password_resets
+JOIN users ON users.id = password_resets.user_id
+WHERE password_resets.token_hash = $1;
@@ -24,3 +24,8 @@ AND (sqlc.narg('owner_id')::bigint IS NULL OR sqlc.narg('owner_id')::bigint  = s
 AND (sqlc.narg('refresh_token')::text IS NULL OR sqlc.narg('refresh_token')::text  = sessions.refresh_token)
 
 LIMIT 1;
+
+-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE owner_id = $1 AND is_blocked IS NOT TRUE;
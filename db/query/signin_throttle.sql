@@ -0,0 +1,51 @@
+-- name: GetSigninThrottles :many
+SELECT * FROM signin_throttles
+WHERE key = ANY(sqlc.arg('keys')::varchar[]);
+
+-- name: RecordSigninFailure :one
+INSERT INTO signin_throttles(key, failures, last_failed_at)
+VALUES (sqlc.arg('key'), 1, now())
+ON CONFLICT (key) DO UPDATE
+SET failures = CASE
+      WHEN signin_throttles.last_failed_at < sqlc.arg('reset_before')::timestamptz THEN 1
+      ELSE signin_throttles.failures + 1
+    END,
+    last_failed_at = now()
+RETURNING *;
+
+-- name: LockSigninThrottle :one
+UPDATE signin_throttles
+SET failures = 0,
+    locked_until = $2
+WHERE key = $1
+RETURNING *;
+
+-- name: DeleteSigninThrottle :exec
+DELETE FROM signin_throttles
+WHERE key = $1;
+
+-- name: ClaimSigninThrottles :many
+-- Locks the throttles of keys for the rest of the transaction, creating
+-- those that do not exist yet.
+INSERT INTO signin_throttles(key)
+SELECT unnest(sqlc.arg('keys')::varchar[])
+ORDER BY 1
+ON CONFLICT (key) DO UPDATE
+SET key = EXCLUDED.key
+RETURNING *;
+
+-- name: ReserveSigninAttempt :exec
+-- Counts an attempt let through until it is recorded. Reservations left
+-- over from before reset_before were never recorded and are dropped.
+UPDATE signin_throttles
+SET reserved = CASE
+      WHEN reserved_at < sqlc.arg('reset_before')::timestamptz THEN 1
+      ELSE reserved + 1
+    END,
+    reserved_at = now()
+WHERE key = sqlc.arg('key');
+
+-- name: ReleaseSigninReservation :exec
+UPDATE signin_throttles
+SET reserved = GREATEST(reserved - 1, 0)
+WHERE key = $1;
@@ -0,0 +1,42 @@
+-- name: CreateStepUpChallenge :one
+INSERT INTO step_up_challenges(id, user_id, action, params_hash, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetStepUpChallenge :one
+SELECT * FROM step_up_challenges
+WHERE id = $1
+LIMIT 1;
+
+-- name: TakeStepUpAttempt :one
+-- Takes one of the challenge's attempts before the answer is checked, so
+-- parallel answers cannot exceed max_attempts between them.
+UPDATE step_up_challenges
+SET attempts = attempts + 1
+WHERE id = sqlc.arg('id')
+  AND verified_at IS NULL
+  AND attempts < sqlc.arg('max_attempts')::int
+  AND expires_at > now()
+RETURNING *;
+
+-- name: IssueStepUpToken :one
+UPDATE step_up_challenges
+SET token_hash = sqlc.arg('token_hash')::varchar,
+    verified_at = now(),
+    expires_at = sqlc.arg('expires_at')
+WHERE id = sqlc.arg('id')
+  AND verified_at IS NULL
+  AND attempts <= sqlc.arg('max_attempts')::int
+  AND expires_at > now()
+RETURNING *;
+
+-- name: ConsumeStepUpToken :one
+UPDATE step_up_challenges
+SET used_at = now()
+WHERE token_hash = sqlc.arg('token_hash')::varchar
+  AND user_id = sqlc.arg('user_id')
+  AND action = sqlc.arg('action')
+  AND params_hash = sqlc.arg('params_hash')
+  AND used_at IS NULL
+  AND expires_at > now()
+RETURNING *;
@@ -1,4 +1,20 @@
 -- name: CreateTransfer :one
-INSERT INTO transfer(from_account_id, to_account_id, amount)
-VALUES ($1, $2, $3)
+INSERT INTO transfer(from_account_id, to_account_id, amount, fee, fee_policy_id, original_transfer_id)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING  *;
+
+-- name: GetTransfer :one
+SELECT * FROM transfer
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetTransferForUpdate :one
+SELECT * FROM transfer
+WHERE id = $1
+LIMIT 1 FOR NO KEY UPDATE;
+
+-- name: AddTransferReversedAmount :one
+UPDATE transfer
+SET reversed_amount = reversed_amount + sqlc.arg('amount')
+WHERE id = sqlc.arg('id')
+RETURNING *;
@@ -0,0 +1,29 @@
+-- name: CreateTransferHold :one
+INSERT INTO transfer_holds(from_account_id, to_account_id, amount, fee, fee_policy_id, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetTransferHold :one
+SELECT * FROM transfer_holds
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetTransferHoldForUpdate :one
+SELECT * FROM transfer_holds
+WHERE id = $1
+LIMIT 1 FOR NO KEY UPDATE;
+
+-- name: UpdateTransferHoldStatus :one
+UPDATE transfer_holds
+SET status = sqlc.arg('status'),
+    transfer_id = sqlc.narg('transfer_id'),
+    updated_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: GetExpiredTransferHolds :many
+SELECT * FROM transfer_holds
+WHERE status = 'pending' AND expires_at <= now()
+ORDER BY expires_at
+LIMIT sqlc.arg('limit')
+FOR NO KEY UPDATE SKIP LOCKED;
@@ -0,0 +1,9 @@
+-- name: CreateTransferReversal :one
+INSERT INTO transfer_reversals(transfer_id, reversal_transfer_id, amount, reason, reversed_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetTransferReversals :many
+SELECT * FROM transfer_reversals
+WHERE transfer_id = $1
+ORDER BY created_at;
@@ -0,0 +1,40 @@
+-- name: CreateRecoveryCode :one
+INSERT INTO recovery_codes(user_id, code_hash)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: DeleteUserRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1;
+
+-- name: UseRecoveryCode :one
+UPDATE recovery_codes
+SET used_at = now()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+RETURNING *;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT count(*) FROM recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: CreateSigninChallenge :one
+INSERT INTO signin_challenges(user_id, token_hash, expires_at)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetSigninChallengeForUpdate :one
+SELECT * FROM signin_challenges
+WHERE token_hash = $1
+LIMIT 1 FOR UPDATE;
+
+-- name: MarkSigninChallengeUsed :one
+UPDATE signin_challenges
+SET used_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: IncrementSigninChallengeAttempts :one
+UPDATE signin_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING *;
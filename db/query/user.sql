@@ -1,6 +1,6 @@
 -- name: CreateUser :one
-INSERT INTO users(username, email, fullname, hashed_password, password_salt)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO users(username, email, fullname, hashed_password)
+VALUES ($1, $2, $3, $4)
 RETURNING *;
 
 -- name: GetUserByUniqueID :one
@@ -10,8 +10,55 @@ or email ilike sqlc.narg('email')
 or username ilike sqlc.narg('username')
 LIMIT 1;
 
+-- name: GetUserForUpdate :one
+SELECT * FROM users
+WHERE id = $1
+LIMIT 1 FOR UPDATE;
+
 -- name: GetUsers :many
 SELECT * FROM users
 WHERE ($3::int[] IS NULL OR id = ANY($3::int[]))
 OFFSET sqlc.arg('offset')
 LIMIT sqlc.arg('limit');
+
+-- name: MarkUserEmailVerified :one
+UPDATE users
+SET is_email_verified = true, email_verified_at = now()
+WHERE id = sqlc.arg('id') AND email = sqlc.arg('email')
+RETURNING *;
+
+-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = sqlc.arg('hashed_password'),
+    password_changed_at = now()
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: RehashUserPassword :execrows
+UPDATE users
+SET hashed_password = sqlc.arg('hashed_password')
+WHERE id = sqlc.arg('id') AND hashed_password = sqlc.arg('old_hashed_password');
+
+-- name: SetUserTOTPSecret :one
+UPDATE users
+SET totp_secret = sqlc.arg('totp_secret')::varchar, is_totp_enabled = false, totp_last_step = NULL
+WHERE id = sqlc.arg('id') AND is_totp_enabled = false
+RETURNING *;
+
+-- name: EnableUserTOTP :one
+UPDATE users
+SET is_totp_enabled = true
+WHERE id = $1 AND totp_secret IS NOT NULL
+RETURNING *;
+
+-- name: DisableUserTOTP :one
+UPDATE users
+SET totp_secret = NULL, is_totp_enabled = false, totp_last_step = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: AdvanceUserTOTPStep :one
+UPDATE users
+SET totp_last_step = sqlc.arg('step')::bigint
+WHERE id = sqlc.arg('id') AND (totp_last_step IS NULL OR totp_last_step < sqlc.arg('step')::bigint)
+RETURNING *;
@@ -0,0 +1,19 @@
+-- name: CreateVerifyEmail :one
+INSERT INTO verify_emails(user_id, email, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetVerifyEmailForUpdate :one
+SELECT * FROM verify_emails
+WHERE token_hash = $1
+LIMIT 1 FOR UPDATE;
+
+-- name: MarkVerifyEmailUsed :one
+UPDATE verify_emails
+SET used_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CountVerifyEmailsSince :one
+SELECT count(*) FROM verify_emails
+WHERE user_id = $1 AND created_at >= sqlc.arg('since');
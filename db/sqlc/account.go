@@ -0,0 +1,7 @@
+package db
+
+// SpendableBalance is what the owner can still move out of the account:
+// the available balance plus any approved overdraft.
+func (a Account) SpendableBalance() float64 {
+	return a.AvailableBalance + a.OverdraftLimit
+}
@@ -12,20 +12,84 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addAccountBalance = `-- name: AddAccountBalance :one
+UPDATE accounts
+SET balance = balance + $1
+WHERE id = $2
+RETURNING id, owner_id, balance, currency, created_at, held_balance, available_balance, overdraft_limit, type
+`
+
+type AddAccountBalanceParams struct {
+	Amount float64 `json:"amount"`
+	ID     int64   `json:"id"`
+}
+
+func (q *Queries) AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error) {
+	row := q.db.QueryRow(ctx, addAccountBalance, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.OverdraftLimit,
+		&i.Type,
+	)
+	return i, err
+}
+
+const addAccountHeldBalance = `-- name: AddAccountHeldBalance :one
+UPDATE accounts
+SET held_balance = held_balance + $1
+WHERE id = $2
+RETURNING id, owner_id, balance, currency, created_at, held_balance, available_balance, overdraft_limit, type
+`
+
+type AddAccountHeldBalanceParams struct {
+	Amount float64 `json:"amount"`
+	ID     int64   `json:"id"`
+}
+
+func (q *Queries) AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error) {
+	row := q.db.QueryRow(ctx, addAccountHeldBalance, arg.Amount, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.OverdraftLimit,
+		&i.Type,
+	)
+	return i, err
+}
+
 const createAccount = `-- name: CreateAccount :one
-INSERT INTO accounts(owner_id, balance, currency)
-VALUES ($1, $2, $3)
-RETURNING id, owner_id, balance, currency, created_at
+INSERT INTO accounts(owner_id, balance, currency, type)
+VALUES ($1, $2, $3, $4)
+RETURNING id, owner_id, balance, currency, created_at, held_balance, available_balance, overdraft_limit, type
 `
 
 type CreateAccountParams struct {
 	OwnerID  int64   `json:"owner_id"`
 	Balance  float64 `json:"balance"`
 	Currency string  `json:"currency"`
+	Type     string  `json:"type"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
-	row := q.db.QueryRow(ctx, createAccount, arg.OwnerID, arg.Balance, arg.Currency)
+	row := q.db.QueryRow(ctx, createAccount,
+		arg.OwnerID,
+		arg.Balance,
+		arg.Currency,
+		arg.Type,
+	)
 	var i Account
 	err := row.Scan(
 		&i.ID,
@@ -33,6 +97,10 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.OverdraftLimit,
+		&i.Type,
 	)
 	return i, err
 }
@@ -47,8 +115,28 @@ func (q *Queries) DeleteAccount(ctx context.Context, id int64) error {
 	return err
 }
 
+const getAccountBalanceAt = `-- name: GetAccountBalanceAt :one
+SELECT (a.balance - COALESCE(SUM(e.amount), 0))::float AS balance
+FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id AND e.created_at >= $1
+WHERE a.id = $2
+GROUP BY a.id
+`
+
+type GetAccountBalanceAtParams struct {
+	At pgtype.Timestamptz `json:"at"`
+	ID int64              `json:"id"`
+}
+
+func (q *Queries) GetAccountBalanceAt(ctx context.Context, arg GetAccountBalanceAtParams) (float64, error) {
+	row := q.db.QueryRow(ctx, getAccountBalanceAt, arg.At, arg.ID)
+	var balance float64
+	err := row.Scan(&balance)
+	return balance, err
+}
+
 const getAccountByID = `-- name: GetAccountByID :one
-SELECT id, owner_id, balance, currency, created_at FROM accounts
+SELECT id, owner_id, balance, currency, created_at, held_balance, available_balance, overdraft_limit, type FROM accounts
 WHERE id = $1
 LIMIT 1
 `
@@ -62,12 +150,16 @@ func (q *Queries) GetAccountByID(ctx context.Context, id int64) (Account, error)
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.OverdraftLimit,
+		&i.Type,
 	)
 	return i, err
 }
 
 const getAccountByIDForUpdate = `-- name: GetAccountByIDForUpdate :one
-SELECT id, owner_id, balance, currency, created_at FROM accounts
+SELECT id, owner_id, balance, currency, created_at, held_balance, available_balance, overdraft_limit, type FROM accounts
 WHERE id = $1
 LIMIT 1 FOR NO KEY UPDATE
 `
@@ -81,12 +173,16 @@ func (q *Queries) GetAccountByIDForUpdate(ctx context.Context, id int64) (Accoun
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.OverdraftLimit,
+		&i.Type,
 	)
 	return i, err
 }
 
 const getAccounts = `-- name: GetAccounts :many
-SELECT id, owner_id, balance, currency, created_at FROM accounts
+SELECT id, owner_id, balance, currency, created_at, held_balance, available_balance, overdraft_limit, type FROM accounts
 WHERE ($3::int[] IS NULL OR id = ANY($3::int[]))
   AND ($1::int IS NULL OR balance < $1)
   AND ($2::bigint IS NULL OR $2::bigint = accounts.owner_id)
@@ -123,6 +219,10 @@ func (q *Queries) GetAccounts(ctx context.Context, arg GetAccountsParams) ([]Acc
 			&i.Balance,
 			&i.Currency,
 			&i.CreatedAt,
+			&i.HeldBalance,
+			&i.AvailableBalance,
+			&i.OverdraftLimit,
+			&i.Type,
 		); err != nil {
 			return nil, err
 		}
@@ -134,11 +234,144 @@ func (q *Queries) GetAccounts(ctx context.Context, arg GetAccountsParams) ([]Acc
 	return items, nil
 }
 
+const getAccountsByType = `-- name: GetAccountsByType :many
+SELECT id, owner_id, balance, currency, created_at, held_balance, available_balance, overdraft_limit, type FROM accounts
+WHERE type = $1 AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type GetAccountsByTypeParams struct {
+	Type    string `json:"type"`
+	AfterID int64  `json:"after_id"`
+	Limit   int32  `json:"limit"`
+}
+
+func (q *Queries) GetAccountsByType(ctx context.Context, arg GetAccountsByTypeParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, getAccountsByType, arg.Type, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.HeldBalance,
+			&i.AvailableBalance,
+			&i.OverdraftLimit,
+			&i.Type,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOldestAccountCreatedAt = `-- name: GetOldestAccountCreatedAt :one
+SELECT min(created_at)::timestamptz AS created_at FROM accounts
+`
+
+func (q *Queries) GetOldestAccountCreatedAt(ctx context.Context) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getOldestAccountCreatedAt)
+	var created_at pgtype.Timestamptz
+	err := row.Scan(&created_at)
+	return created_at, err
+}
+
+const getOverdrawnAccounts = `-- name: GetOverdrawnAccounts :many
+SELECT a.id, a.owner_id, a.balance, a.currency, a.created_at, a.held_balance, a.available_balance, a.overdraft_limit, a.type FROM accounts a
+LEFT JOIN entries e ON e.account_id = a.id AND e.created_at >= $1
+WHERE a.id > $2
+  AND a.id NOT IN (SELECT account_id FROM system_accounts)
+GROUP BY a.id
+HAVING a.balance - COALESCE(SUM(e.amount), 0) < 0
+ORDER BY a.id
+LIMIT $3
+`
+
+type GetOverdrawnAccountsParams struct {
+	At      pgtype.Timestamptz `json:"at"`
+	AfterID int64              `json:"after_id"`
+	Limit   int32              `json:"limit"`
+}
+
+// Returns the accounts whose balance was negative at the given time, backing
+// out entries booked since. System accounts such as interest expense run
+// negative by design and are never charged.
+func (q *Queries) GetOverdrawnAccounts(ctx context.Context, arg GetOverdrawnAccountsParams) ([]Account, error) {
+	rows, err := q.db.Query(ctx, getOverdrawnAccounts, arg.At, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Account{}
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerID,
+			&i.Balance,
+			&i.Currency,
+			&i.CreatedAt,
+			&i.HeldBalance,
+			&i.AvailableBalance,
+			&i.OverdraftLimit,
+			&i.Type,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateAccountOverdraftLimit = `-- name: UpdateAccountOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $1
+WHERE id = $2
+RETURNING id, owner_id, balance, currency, created_at, held_balance, available_balance, overdraft_limit, type
+`
+
+type UpdateAccountOverdraftLimitParams struct {
+	OverdraftLimit float64 `json:"overdraft_limit"`
+	ID             int64   `json:"id"`
+}
+
+func (q *Queries) UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountOverdraftLimit, arg.OverdraftLimit, arg.ID)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerID,
+		&i.Balance,
+		&i.Currency,
+		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.OverdraftLimit,
+		&i.Type,
+	)
+	return i, err
+}
+
 const updateBalance = `-- name: UpdateBalance :one
 UPDATE accounts
 SET balance = $1
 WHERE id = $2
-RETURNING id, owner_id, balance, currency, created_at
+RETURNING id, owner_id, balance, currency, created_at, held_balance, available_balance, overdraft_limit, type
 `
 
 type UpdateBalanceParams struct {
@@ -155,6 +388,10 @@ func (q *Queries) UpdateBalance(ctx context.Context, arg UpdateBalanceParams) (A
 		&i.Balance,
 		&i.Currency,
 		&i.CreatedAt,
+		&i.HeldBalance,
+		&i.AvailableBalance,
+		&i.OverdraftLimit,
+		&i.Type,
 	)
 	return i, err
 }
@@ -18,6 +18,7 @@ func createRandomAccount(t *testing.T) Account {
 		OwnerID:  user.ID,
 		Balance:  float64(util.RandomMoney()),
 		Currency: util.RandomCurrency(),
+		Type:     util.CheckingAccount,
 	}
 
 	account, err := testQueries.CreateAccount(context.Background(), arg)
@@ -32,6 +33,7 @@ func createRandomAccount(t *testing.T) Account {
 	require.Equal(t, arg.OwnerID, account.OwnerID)
 	require.Equal(t, arg.Balance, account.Balance)
 	require.Equal(t, arg.Currency, account.Currency)
+	require.Equal(t, arg.Type, account.Type)
 
 	require.NotZero(t, account.ID)
 	require.NotZero(t, account.CreatedAt)
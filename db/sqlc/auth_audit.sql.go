@@ -0,0 +1,93 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: auth_audit.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createAuthAudit = `-- name: CreateAuthAudit :one
+INSERT INTO auth_audit(user_id, identifier, client_ip, user_agent, outcome, success)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, identifier, client_ip, user_agent, outcome, success, created_at
+`
+
+type CreateAuthAuditParams struct {
+	UserID     pgtype.Int8 `json:"user_id"`
+	Identifier string      `json:"identifier"`
+	ClientIp   string      `json:"client_ip"`
+	UserAgent  string      `json:"user_agent"`
+	Outcome    string      `json:"outcome"`
+	Success    bool        `json:"success"`
+}
+
+func (q *Queries) CreateAuthAudit(ctx context.Context, arg CreateAuthAuditParams) (AuthAudit, error) {
+	row := q.db.QueryRow(ctx, createAuthAudit,
+		arg.UserID,
+		arg.Identifier,
+		arg.ClientIp,
+		arg.UserAgent,
+		arg.Outcome,
+		arg.Success,
+	)
+	var i AuthAudit
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Identifier,
+		&i.ClientIp,
+		&i.UserAgent,
+		&i.Outcome,
+		&i.Success,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listUserAuthAudit = `-- name: ListUserAuthAudit :many
+SELECT id, user_id, identifier, client_ip, user_agent, outcome, success, created_at FROM auth_audit
+WHERE user_id = $1
+ORDER BY id DESC
+LIMIT $2
+OFFSET $3
+`
+
+type ListUserAuthAuditParams struct {
+	UserID pgtype.Int8 `json:"user_id"`
+	Limit  int32       `json:"limit"`
+	Offset int32       `json:"offset"`
+}
+
+func (q *Queries) ListUserAuthAudit(ctx context.Context, arg ListUserAuthAuditParams) ([]AuthAudit, error) {
+	rows, err := q.db.Query(ctx, listUserAuthAudit, arg.UserID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AuthAudit{}
+	for rows.Next() {
+		var i AuthAudit
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Identifier,
+			&i.ClientIp,
+			&i.UserAgent,
+			&i.Outcome,
+			&i.Success,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -12,30 +12,39 @@ import (
 )
 
 const createBalanceEntry = `-- name: CreateBalanceEntry :one
-INSERT INTO entries (account_id, amount)
-VALUES($1, $2)
-RETURNING id, account_id, amount, created_at
+INSERT INTO entries (account_id, amount, transfer_id, kind)
+VALUES($1, $2, $3, $4)
+RETURNING id, account_id, amount, created_at, transfer_id, kind
 `
 
 type CreateBalanceEntryParams struct {
-	AccountID pgtype.Int8 `json:"account_id"`
-	Amount    float64     `json:"amount"`
+	AccountID  pgtype.Int8 `json:"account_id"`
+	Amount     float64     `json:"amount"`
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	Kind       string      `json:"kind"`
 }
 
 func (q *Queries) CreateBalanceEntry(ctx context.Context, arg CreateBalanceEntryParams) (Entry, error) {
-	row := q.db.QueryRow(ctx, createBalanceEntry, arg.AccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createBalanceEntry,
+		arg.AccountID,
+		arg.Amount,
+		arg.TransferID,
+		arg.Kind,
+	)
 	var i Entry
 	err := row.Scan(
 		&i.ID,
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.Kind,
 	)
 	return i, err
 }
 
 const getAccountBalanceEntries = `-- name: GetAccountBalanceEntries :many
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, kind FROM entries
 WHERE account_id = $1
 LIMIT 1
 `
@@ -54,6 +63,8 @@ func (q *Queries) GetAccountBalanceEntries(ctx context.Context, accountID pgtype
 			&i.AccountID,
 			&i.Amount,
 			&i.CreatedAt,
+			&i.TransferID,
+			&i.Kind,
 		); err != nil {
 			return nil, err
 		}
@@ -66,7 +77,7 @@ func (q *Queries) GetAccountBalanceEntries(ctx context.Context, accountID pgtype
 }
 
 const getBalanceEntry = `-- name: GetBalanceEntry :one
-SELECT id, account_id, amount, created_at FROM entries
+SELECT id, account_id, amount, created_at, transfer_id, kind FROM entries
 WHERE id = $1
 LIMIT 1
 `
@@ -79,6 +90,75 @@ func (q *Queries) GetBalanceEntry(ctx context.Context, id int64) (Entry, error)
 		&i.AccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.TransferID,
+		&i.Kind,
 	)
 	return i, err
 }
+
+const getStatementEntries = `-- name: GetStatementEntries :many
+SELECT e.id, e.amount, e.kind, e.created_at, e.transfer_id,
+  t.from_account_id, t.to_account_id, t.original_transfer_id
+FROM entries e
+LEFT JOIN transfer t ON t.id = e.transfer_id
+WHERE e.account_id = $1
+  AND e.created_at >= $2
+  AND e.created_at < $3
+  AND e.id > $4
+ORDER BY e.id
+LIMIT $5
+`
+
+type GetStatementEntriesParams struct {
+	AccountID pgtype.Int8        `json:"account_id"`
+	From      pgtype.Timestamptz `json:"from"`
+	To        pgtype.Timestamptz `json:"to"`
+	AfterID   int64              `json:"after_id"`
+	Limit     int32              `json:"limit"`
+}
+
+type GetStatementEntriesRow struct {
+	ID                 int64              `json:"id"`
+	Amount             float64            `json:"amount"`
+	Kind               string             `json:"kind"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
+	TransferID         pgtype.Int8        `json:"transfer_id"`
+	FromAccountID      pgtype.Int8        `json:"from_account_id"`
+	ToAccountID        pgtype.Int8        `json:"to_account_id"`
+	OriginalTransferID pgtype.Int8        `json:"original_transfer_id"`
+}
+
+func (q *Queries) GetStatementEntries(ctx context.Context, arg GetStatementEntriesParams) ([]GetStatementEntriesRow, error) {
+	rows, err := q.db.Query(ctx, getStatementEntries,
+		arg.AccountID,
+		arg.From,
+		arg.To,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetStatementEntriesRow{}
+	for rows.Next() {
+		var i GetStatementEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.Kind,
+			&i.CreatedAt,
+			&i.TransferID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.OriginalTransferID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStatementEntries(t *testing.T) {
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	result, err := testQueries.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        40,
+	})
+	require.NoError(t, err)
+	require.Equal(t, result.Transfer.ID, result.FromEntry.TransferID.Int64)
+	require.Equal(t, EntryKindTransfer, result.FromEntry.Kind)
+
+	now := time.Now()
+	arg := GetStatementEntriesParams{
+		AccountID: pgtype.Int8{Int64: account2.ID, Valid: true},
+		From:      pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+		To:        pgtype.Timestamptz{Time: now.Add(time.Hour), Valid: true},
+		Limit:     10,
+	}
+
+	rows, err := testQueries.GetStatementEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, result.ToEntry.ID, rows[0].ID)
+	require.Equal(t, float64(40), rows[0].Amount)
+	require.Equal(t, account1.ID, rows[0].FromAccountID.Int64)
+	require.Equal(t, account2.ID, rows[0].ToAccountID.Int64)
+
+	// The range end is exclusive.
+	arg.To = pgtype.Timestamptz{Time: now.Add(-time.Minute), Valid: true}
+	rows, err = testQueries.GetStatementEntries(context.Background(), arg)
+	require.NoError(t, err)
+	require.Empty(t, rows)
+
+	// Backing the transfer out gives the balance before it.
+	balance, err := testQueries.GetAccountBalanceAt(context.Background(), GetAccountBalanceAtParams{
+		ID: account2.ID,
+		At: pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, account2.Balance, balance)
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/devphasex/cedar-bank-api/fee"
+	"github.com/jackc/pgx/v5"
+)
+
+type TransferFeeQuote struct {
+	Amount        float64 `json:"amount"`
+	Fee           float64 `json:"fee"`
+	Total         float64 `json:"total"`
+	Currency      string  `json:"currency"`
+	FeePolicyID   *int64  `json:"fee_policy_id,omitempty"`
+	PolicyVersion *int32  `json:"fee_policy_version,omitempty"`
+}
+
+// newFeePolicy maps a fee_policies row onto the fee engine policy.
+func newFeePolicy(row FeePolicy) (*fee.Policy, error) {
+	tiers, err := fee.ParseTiers(row.Tiers)
+
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &fee.Policy{
+		ID:         row.ID,
+		Version:    row.Version,
+		Kind:       fee.Kind(row.Kind),
+		FlatAmount: row.FlatAmount,
+		Percentage: row.Percentage,
+		MinFee:     row.MinFee,
+		Tiers:      tiers,
+	}
+
+	if row.MaxFee.Valid {
+		maxFee := row.MaxFee.Float64
+		policy.MaxFee = &maxFee
+	}
+
+	return policy, nil
+}
+
+// quoteTransferFee computes the fee for amount under the policy active
+// for currency. No active policy means the transfer is free.
+func quoteTransferFee(ctx context.Context, q *Queries, currency string, amount float64) (*TransferFeeQuote, error) {
+	quote := &TransferFeeQuote{
+		Amount:   amount,
+		Total:    amount,
+		Currency: currency,
+	}
+
+	row, err := q.GetActiveFeePolicy(ctx, currency)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return quote, nil
+		}
+
+		return nil, err
+	}
+
+	policy, err := newFeePolicy(row)
+
+	if err != nil {
+		return nil, err
+	}
+
+	quote.Fee, err = policy.Compute(amount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	quote.Total = fee.Round(amount + quote.Fee)
+	quote.FeePolicyID = &row.ID
+	quote.PolicyVersion = &row.Version
+
+	return quote, nil
+}
+
+// QuoteTransferFee previews the fee TransferTx would charge without
+// moving any funds.
+func (s *PgStore) QuoteTransferFee(ctx context.Context, currency string, amount float64) (*TransferFeeQuote, error) {
+	return quoteTransferFee(ctx, s.Queries, currency, amount)
+}
@@ -0,0 +1,163 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: fee_policy.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createFeePolicy = `-- name: CreateFeePolicy :one
+INSERT INTO fee_policies(currency, version, kind, flat_amount, percentage, min_fee, max_fee, tiers, effective_from)
+VALUES (
+  $1,
+  (SELECT COALESCE(MAX(version), 0) + 1 FROM fee_policies WHERE currency IS NOT DISTINCT FROM $1),
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8
+)
+RETURNING id, currency, version, kind, flat_amount, percentage, min_fee, max_fee, tiers, active, effective_from, created_at
+`
+
+type CreateFeePolicyParams struct {
+	Currency      pgtype.Text        `json:"currency"`
+	Kind          string             `json:"kind"`
+	FlatAmount    float64            `json:"flat_amount"`
+	Percentage    float64            `json:"percentage"`
+	MinFee        float64            `json:"min_fee"`
+	MaxFee        pgtype.Float8      `json:"max_fee"`
+	Tiers         []byte             `json:"tiers"`
+	EffectiveFrom pgtype.Timestamptz `json:"effective_from"`
+}
+
+func (q *Queries) CreateFeePolicy(ctx context.Context, arg CreateFeePolicyParams) (FeePolicy, error) {
+	row := q.db.QueryRow(ctx, createFeePolicy,
+		arg.Currency,
+		arg.Kind,
+		arg.FlatAmount,
+		arg.Percentage,
+		arg.MinFee,
+		arg.MaxFee,
+		arg.Tiers,
+		arg.EffectiveFrom,
+	)
+	var i FeePolicy
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Version,
+		&i.Kind,
+		&i.FlatAmount,
+		&i.Percentage,
+		&i.MinFee,
+		&i.MaxFee,
+		&i.Tiers,
+		&i.Active,
+		&i.EffectiveFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deactivateFeePolicy = `-- name: DeactivateFeePolicy :one
+UPDATE fee_policies
+SET active = FALSE
+WHERE id = $1
+RETURNING id, currency, version, kind, flat_amount, percentage, min_fee, max_fee, tiers, active, effective_from, created_at
+`
+
+func (q *Queries) DeactivateFeePolicy(ctx context.Context, id int64) (FeePolicy, error) {
+	row := q.db.QueryRow(ctx, deactivateFeePolicy, id)
+	var i FeePolicy
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Version,
+		&i.Kind,
+		&i.FlatAmount,
+		&i.Percentage,
+		&i.MinFee,
+		&i.MaxFee,
+		&i.Tiers,
+		&i.Active,
+		&i.EffectiveFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getActiveFeePolicy = `-- name: GetActiveFeePolicy :one
+SELECT id, currency, version, kind, flat_amount, percentage, min_fee, max_fee, tiers, active, effective_from, created_at FROM fee_policies
+WHERE active
+  AND effective_from <= now()
+  AND (currency IS NULL OR currency = $1::varchar)
+ORDER BY currency NULLS LAST, version DESC
+LIMIT 1
+`
+
+func (q *Queries) GetActiveFeePolicy(ctx context.Context, currency string) (FeePolicy, error) {
+	row := q.db.QueryRow(ctx, getActiveFeePolicy, currency)
+	var i FeePolicy
+	err := row.Scan(
+		&i.ID,
+		&i.Currency,
+		&i.Version,
+		&i.Kind,
+		&i.FlatAmount,
+		&i.Percentage,
+		&i.MinFee,
+		&i.MaxFee,
+		&i.Tiers,
+		&i.Active,
+		&i.EffectiveFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getFeePolicies = `-- name: GetFeePolicies :many
+SELECT id, currency, version, kind, flat_amount, percentage, min_fee, max_fee, tiers, active, effective_from, created_at FROM fee_policies
+WHERE ($1::varchar IS NULL OR currency = $1::varchar)
+ORDER BY currency NULLS LAST, version DESC
+`
+
+func (q *Queries) GetFeePolicies(ctx context.Context, currency pgtype.Text) ([]FeePolicy, error) {
+	rows, err := q.db.Query(ctx, getFeePolicies, currency)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []FeePolicy{}
+	for rows.Next() {
+		var i FeePolicy
+		if err := rows.Scan(
+			&i.ID,
+			&i.Currency,
+			&i.Version,
+			&i.Kind,
+			&i.FlatAmount,
+			&i.Percentage,
+			&i.MinFee,
+			&i.MaxFee,
+			&i.Tiers,
+			&i.Active,
+			&i.EffectiveFrom,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
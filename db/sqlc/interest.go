@@ -0,0 +1,185 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/fee"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type AccrueInterestTxParams struct {
+	AccountID int64     `json:"account_id"`
+	Date      time.Time `json:"date"`
+}
+
+// DailyInterest returns one day of interest on balance at annualRate
+// percent, using a simple daily rate of annualRate/365. The result is not
+// rounded; daily amounts are summed and rounded once when posted.
+func DailyInterest(balance, annualRate float64) float64 {
+	if balance <= 0 || annualRate <= 0 {
+		return 0
+	}
+
+	return balance * annualRate / 100 / 365
+}
+
+// AccrueInterestTx records one day of interest for an account, computed on
+// its balance at the end of date (UTC) and the rate effective on that date.
+// Each account accrues at most once per date; a repeated call, an account
+// without an effective rate or a non-positive balance all return nil.
+func (s *PgStore) AccrueInterestTx(ctx context.Context, arg AccrueInterestTxParams) (*InterestAccrual, error) {
+	var accrual *InterestAccrual
+
+	date := arg.Date.UTC().Truncate(24 * time.Hour)
+	endOfDay := date.AddDate(0, 0, 1)
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountByID(ctx, arg.AccountID)
+
+		if err != nil {
+			return err
+		}
+
+		if account.CreatedAt.Valid && !account.CreatedAt.Time.Before(endOfDay) {
+			return nil
+		}
+
+		rate, err := q.GetEffectiveInterestRate(ctx, GetEffectiveInterestRateParams{
+			AccountType: account.Type,
+			Currency:    account.Currency,
+			Date:        pgtype.Date{Time: date, Valid: true},
+		})
+
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+
+			return err
+		}
+
+		// Entries booked after the cutoff are backed out of the current
+		// balance to get the end-of-day figure.
+		balance, err := q.GetAccountBalanceAt(ctx, GetAccountBalanceAtParams{
+			ID: account.ID,
+			At: pgtype.Timestamptz{Time: endOfDay, Valid: true},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		amount := DailyInterest(balance, rate.AnnualRate)
+
+		if amount <= 0 {
+			return nil
+		}
+
+		claimed, err := q.CreateInterestAccrual(ctx, CreateInterestAccrualParams{
+			AccountID:   account.ID,
+			AccrualDate: pgtype.Date{Time: date, Valid: true},
+			Balance:     balance,
+			AnnualRate:  rate.AnnualRate,
+			Amount:      amount,
+		})
+
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Already accrued for this date.
+				return nil
+			}
+
+			return err
+		}
+
+		accrual = &claimed
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return accrual, nil
+}
+
+type PostInterestTxParams struct {
+	AccountID int64 `json:"account_id"`
+	// Before is exclusive: accruals dated on or after it stay pending.
+	Before time.Time `json:"before"`
+}
+
+type PostInterestTxResult struct {
+	Accruals []InterestAccrual `json:"accruals"`
+	Amount   float64           `json:"amount"`
+	// Entry credits the account and ExpenseEntry debits the currency's
+	// interest expense account. Both are nil when the total rounds to zero.
+	Entry        *Entry `json:"entry,omitempty"`
+	ExpenseEntry *Entry `json:"expense_entry,omitempty"`
+}
+
+// PostInterestTx pays out every pending accrual of an account dated before
+// arg.Before as a single entry from the interest expense account. Posted
+// accruals are marked so a second call finds nothing to post.
+func (s *PgStore) PostInterestTx(ctx context.Context, arg PostInterestTxParams) (*PostInterestTxResult, error) {
+	var result PostInterestTxResult
+
+	before := pgtype.Date{Time: arg.Before.UTC().Truncate(24 * time.Hour), Valid: true}
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountByIDForUpdate(ctx, arg.AccountID)
+
+		if err != nil {
+			return err
+		}
+
+		result.Accruals, err = q.GetUnpostedInterestAccrualsForUpdate(ctx, GetUnpostedInterestAccrualsForUpdateParams{
+			AccountID: account.ID,
+			Before:    before,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if len(result.Accruals) == 0 {
+			return nil
+		}
+
+		var total float64
+		for _, accrual := range result.Accruals {
+			total += accrual.Amount
+		}
+		result.Amount = fee.Round(total)
+
+		var entryID pgtype.Int8
+
+		if result.Amount > 0 {
+			entry, expenseEntry, err := postSystemEntries(ctx, q, account, SystemPurposeInterestExpense, result.Amount, pgtype.Int8{})
+
+			if err != nil {
+				return err
+			}
+
+			result.Entry = &entry
+			result.ExpenseEntry = &expenseEntry
+			entryID = pgtype.Int8{Int64: entry.ID, Valid: true}
+		}
+
+		_, err = q.MarkInterestAccrualsPosted(ctx, MarkInterestAccrualsPostedParams{
+			EntryID:   entryID,
+			AccountID: account.ID,
+			Before:    before,
+		})
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,269 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: interest.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createInterestAccrual = `-- name: CreateInterestAccrual :one
+INSERT INTO interest_accruals(account_id, accrual_date, balance, annual_rate, amount)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING account_id, accrual_date, balance, annual_rate, amount, entry_id, posted_at, created_at
+`
+
+type CreateInterestAccrualParams struct {
+	AccountID   int64       `json:"account_id"`
+	AccrualDate pgtype.Date `json:"accrual_date"`
+	Balance     float64     `json:"balance"`
+	AnnualRate  float64     `json:"annual_rate"`
+	Amount      float64     `json:"amount"`
+}
+
+func (q *Queries) CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccrual, error) {
+	row := q.db.QueryRow(ctx, createInterestAccrual,
+		arg.AccountID,
+		arg.AccrualDate,
+		arg.Balance,
+		arg.AnnualRate,
+		arg.Amount,
+	)
+	var i InterestAccrual
+	err := row.Scan(
+		&i.AccountID,
+		&i.AccrualDate,
+		&i.Balance,
+		&i.AnnualRate,
+		&i.Amount,
+		&i.EntryID,
+		&i.PostedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createInterestRate = `-- name: CreateInterestRate :one
+INSERT INTO interest_rates(account_type, currency, annual_rate, effective_from)
+VALUES ($1, $2, $3, $4)
+RETURNING id, account_type, currency, annual_rate, effective_from, created_at
+`
+
+type CreateInterestRateParams struct {
+	AccountType   string      `json:"account_type"`
+	Currency      string      `json:"currency"`
+	AnnualRate    float64     `json:"annual_rate"`
+	EffectiveFrom pgtype.Date `json:"effective_from"`
+}
+
+func (q *Queries) CreateInterestRate(ctx context.Context, arg CreateInterestRateParams) (InterestRate, error) {
+	row := q.db.QueryRow(ctx, createInterestRate,
+		arg.AccountType,
+		arg.Currency,
+		arg.AnnualRate,
+		arg.EffectiveFrom,
+	)
+	var i InterestRate
+	err := row.Scan(
+		&i.ID,
+		&i.AccountType,
+		&i.Currency,
+		&i.AnnualRate,
+		&i.EffectiveFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getEffectiveInterestRate = `-- name: GetEffectiveInterestRate :one
+SELECT id, account_type, currency, annual_rate, effective_from, created_at FROM interest_rates
+WHERE account_type = $1 AND currency = $2 AND effective_from <= $3
+ORDER BY effective_from DESC
+LIMIT 1
+`
+
+type GetEffectiveInterestRateParams struct {
+	AccountType string      `json:"account_type"`
+	Currency    string      `json:"currency"`
+	Date        pgtype.Date `json:"date"`
+}
+
+func (q *Queries) GetEffectiveInterestRate(ctx context.Context, arg GetEffectiveInterestRateParams) (InterestRate, error) {
+	row := q.db.QueryRow(ctx, getEffectiveInterestRate, arg.AccountType, arg.Currency, arg.Date)
+	var i InterestRate
+	err := row.Scan(
+		&i.ID,
+		&i.AccountType,
+		&i.Currency,
+		&i.AnnualRate,
+		&i.EffectiveFrom,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getInterestAccruals = `-- name: GetInterestAccruals :many
+SELECT account_id, accrual_date, balance, annual_rate, amount, entry_id, posted_at, created_at FROM interest_accruals
+WHERE account_id = $1
+ORDER BY accrual_date DESC
+`
+
+func (q *Queries) GetInterestAccruals(ctx context.Context, accountID int64) ([]InterestAccrual, error) {
+	rows, err := q.db.Query(ctx, getInterestAccruals, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []InterestAccrual{}
+	for rows.Next() {
+		var i InterestAccrual
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.AccrualDate,
+			&i.Balance,
+			&i.AnnualRate,
+			&i.Amount,
+			&i.EntryID,
+			&i.PostedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getInterestRates = `-- name: GetInterestRates :many
+SELECT id, account_type, currency, annual_rate, effective_from, created_at FROM interest_rates
+ORDER BY account_type, currency, effective_from DESC
+`
+
+func (q *Queries) GetInterestRates(ctx context.Context) ([]InterestRate, error) {
+	rows, err := q.db.Query(ctx, getInterestRates)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []InterestRate{}
+	for rows.Next() {
+		var i InterestRate
+		if err := rows.Scan(
+			&i.ID,
+			&i.AccountType,
+			&i.Currency,
+			&i.AnnualRate,
+			&i.EffectiveFrom,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnpostedInterestAccountIDs = `-- name: GetUnpostedInterestAccountIDs :many
+SELECT DISTINCT account_id FROM interest_accruals
+WHERE posted_at IS NULL AND accrual_date < $1 AND account_id > $2
+ORDER BY account_id
+LIMIT $3
+`
+
+type GetUnpostedInterestAccountIDsParams struct {
+	Before  pgtype.Date `json:"before"`
+	AfterID int64       `json:"after_id"`
+	Limit   int32       `json:"limit"`
+}
+
+func (q *Queries) GetUnpostedInterestAccountIDs(ctx context.Context, arg GetUnpostedInterestAccountIDsParams) ([]int64, error) {
+	rows, err := q.db.Query(ctx, getUnpostedInterestAccountIDs, arg.Before, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var account_id int64
+		if err := rows.Scan(&account_id); err != nil {
+			return nil, err
+		}
+		items = append(items, account_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUnpostedInterestAccrualsForUpdate = `-- name: GetUnpostedInterestAccrualsForUpdate :many
+SELECT account_id, accrual_date, balance, annual_rate, amount, entry_id, posted_at, created_at FROM interest_accruals
+WHERE account_id = $1 AND posted_at IS NULL AND accrual_date < $2
+ORDER BY accrual_date
+FOR UPDATE
+`
+
+type GetUnpostedInterestAccrualsForUpdateParams struct {
+	AccountID int64       `json:"account_id"`
+	Before    pgtype.Date `json:"before"`
+}
+
+func (q *Queries) GetUnpostedInterestAccrualsForUpdate(ctx context.Context, arg GetUnpostedInterestAccrualsForUpdateParams) ([]InterestAccrual, error) {
+	rows, err := q.db.Query(ctx, getUnpostedInterestAccrualsForUpdate, arg.AccountID, arg.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []InterestAccrual{}
+	for rows.Next() {
+		var i InterestAccrual
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.AccrualDate,
+			&i.Balance,
+			&i.AnnualRate,
+			&i.Amount,
+			&i.EntryID,
+			&i.PostedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markInterestAccrualsPosted = `-- name: MarkInterestAccrualsPosted :execrows
+UPDATE interest_accruals
+SET posted_at = now(), entry_id = $1
+WHERE account_id = $2 AND posted_at IS NULL AND accrual_date < $3
+`
+
+type MarkInterestAccrualsPostedParams struct {
+	EntryID   pgtype.Int8 `json:"entry_id"`
+	AccountID int64       `json:"account_id"`
+	Before    pgtype.Date `json:"before"`
+}
+
+func (q *Queries) MarkInterestAccrualsPosted(ctx context.Context, arg MarkInterestAccrualsPostedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markInterestAccrualsPosted, arg.EntryID, arg.AccountID, arg.Before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/fee"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEffectiveInterestRate(t *testing.T) {
+	currency := util.RandomString(6)
+
+	date := func(year int, month time.Month, day int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+
+	for _, rate := range []CreateInterestRateParams{
+		{AccountType: util.SavingsAccount, Currency: currency, AnnualRate: 1, EffectiveFrom: date(2024, 1, 1)},
+		{AccountType: util.SavingsAccount, Currency: currency, AnnualRate: 2, EffectiveFrom: date(2024, 6, 1)},
+	} {
+		_, err := testQueries.CreateInterestRate(context.Background(), rate)
+		require.NoError(t, err)
+	}
+
+	_, err := testQueries.GetEffectiveInterestRate(context.Background(), GetEffectiveInterestRateParams{
+		AccountType: util.SavingsAccount,
+		Currency:    currency,
+		Date:        date(2023, 12, 31),
+	})
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	rate, err := testQueries.GetEffectiveInterestRate(context.Background(), GetEffectiveInterestRateParams{
+		AccountType: util.SavingsAccount,
+		Currency:    currency,
+		Date:        date(2024, 5, 31),
+	})
+	require.NoError(t, err)
+	require.Equal(t, float64(1), rate.AnnualRate)
+
+	rate, err = testQueries.GetEffectiveInterestRate(context.Background(), GetEffectiveInterestRateParams{
+		AccountType: util.SavingsAccount,
+		Currency:    currency,
+		Date:        date(2024, 6, 1),
+	})
+	require.NoError(t, err)
+	require.Equal(t, float64(2), rate.AnnualRate)
+}
+
+func TestAccrueAndPostInterest(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	// Rates are shared by every account of a type and currency, so an
+	// earlier run may already have scheduled one for this date.
+	_, _ = testQueries.CreateInterestRate(context.Background(), CreateInterestRateParams{
+		AccountType:   util.SavingsAccount,
+		Currency:      string(util.USD),
+		AnnualRate:    float64(util.RandomInt(1, 5)),
+		EffectiveFrom: pgtype.Date{Time: today.AddDate(0, 0, -int(util.RandomInt(0, 1000))), Valid: true},
+	})
+
+	user, _ := createRandomUser(t)
+	account, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		OwnerID:  user.ID,
+		Balance:  100000,
+		Currency: string(util.USD),
+		Type:     util.SavingsAccount,
+	})
+	require.NoError(t, err)
+
+	arg := AccrueInterestTxParams{AccountID: account.ID, Date: today}
+
+	accrual, err := testQueries.AccrueInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotNil(t, accrual)
+	require.Equal(t, account.Balance, accrual.Balance)
+	require.InDelta(t, DailyInterest(account.Balance, accrual.AnnualRate), accrual.Amount, 1e-9)
+
+	// Re-running the same day never accrues twice.
+	again, err := testQueries.AccrueInterestTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Nil(t, again)
+
+	// Today's accrual stays pending until the posting cutoff passes it.
+	result, err := testQueries.PostInterestTx(context.Background(), PostInterestTxParams{AccountID: account.ID, Before: today})
+	require.NoError(t, err)
+	require.Empty(t, result.Accruals)
+	require.Nil(t, result.Entry)
+
+	result, err = testQueries.PostInterestTx(context.Background(), PostInterestTxParams{AccountID: account.ID, Before: today.AddDate(0, 0, 1)})
+	require.NoError(t, err)
+	require.Len(t, result.Accruals, 1)
+	require.Equal(t, fee.Round(accrual.Amount), result.Amount)
+	require.NotNil(t, result.Entry)
+	require.Equal(t, result.Amount, result.Entry.Amount)
+	require.NotNil(t, result.ExpenseEntry)
+	require.Equal(t, -result.Amount, result.ExpenseEntry.Amount)
+
+	updated, err := testQueries.GetAccountByID(context.Background(), account.ID)
+	require.NoError(t, err)
+	require.Equal(t, account.Balance+result.Amount, updated.Balance)
+
+	result, err = testQueries.PostInterestTx(context.Background(), PostInterestTxParams{AccountID: account.ID, Before: today.AddDate(0, 0, 1)})
+	require.NoError(t, err)
+	require.Empty(t, result.Accruals)
+	require.Nil(t, result.Entry)
+}
@@ -9,11 +9,32 @@ import (
 )
 
 type Account struct {
-	ID        int64              `json:"id"`
-	OwnerID   int64              `json:"owner_id"`
+	ID      int64 `json:"id"`
+	OwnerID int64 `json:"owner_id"`
+	// ledger balance, only moved by settled transfers
 	Balance   float64            `json:"balance"`
 	Currency  string             `json:"currency"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	// sum of pending transfer holds
+	HeldBalance      float64 `json:"held_balance"`
+	AvailableBalance float64 `json:"available_balance"`
+	// how far below zero the balance may go, set by admins
+	OverdraftLimit float64 `json:"overdraft_limit"`
+	Type           string  `json:"type"`
+}
+
+// every sign-in attempt, successful or not
+type AuthAudit struct {
+	ID int64 `json:"id"`
+	// null when the identifier matched no user
+	UserID pgtype.Int8 `json:"user_id"`
+	// the username or email the attempt was made with
+	Identifier string             `json:"identifier"`
+	ClientIp   string             `json:"client_ip"`
+	UserAgent  string             `json:"user_agent"`
+	Outcome    string             `json:"outcome"`
+	Success    bool               `json:"success"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
 }
 
 type Entry struct {
@@ -21,6 +42,96 @@ type Entry struct {
 	AccountID pgtype.Int8        `json:"account_id"`
 	Amount    float64            `json:"amount"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	// transfer the entry was booked for, including its fee
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	// transfer, or the system account purpose for fee, interest and overdraft entries
+	Kind string `json:"kind"`
+}
+
+type FeePolicy struct {
+	ID int64 `json:"id"`
+	// NULL applies to every currency without a specific policy
+	Currency   pgtype.Text `json:"currency"`
+	Version    int32       `json:"version"`
+	Kind       string      `json:"kind"`
+	FlatAmount float64     `json:"flat_amount"`
+	// percent of the transfer amount, 1.5 means 1.5%
+	Percentage    float64            `json:"percentage"`
+	MinFee        float64            `json:"min_fee"`
+	MaxFee        pgtype.Float8      `json:"max_fee"`
+	Tiers         []byte             `json:"tiers"`
+	Active        bool               `json:"active"`
+	EffectiveFrom pgtype.Timestamptz `json:"effective_from"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+// one row per account and day, makes the accrual job idempotent
+type InterestAccrual struct {
+	AccountID   int64       `json:"account_id"`
+	AccrualDate pgtype.Date `json:"accrual_date"`
+	// end-of-day balance the interest was computed on
+	Balance    float64 `json:"balance"`
+	AnnualRate float64 `json:"annual_rate"`
+	// unrounded daily interest, rounded when the month is posted
+	Amount    float64            `json:"amount"`
+	EntryID   pgtype.Int8        `json:"entry_id"`
+	PostedAt  pgtype.Timestamptz `json:"posted_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+// how far each daily job got through its dates
+type JobWatermark struct {
+	Job string `json:"job"`
+	// last date the job finished for every account
+	DoneThrough pgtype.Date        `json:"done_through"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type InterestRate struct {
+	ID          int64  `json:"id"`
+	AccountType string `json:"account_type"`
+	Currency    string `json:"currency"`
+	// yearly rate in percent, applies from effective_from until the next row
+	AnnualRate    float64            `json:"annual_rate"`
+	EffectiveFrom pgtype.Date        `json:"effective_from"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+}
+
+// one row per account and day, makes the accrual job idempotent
+type OverdraftAccrual struct {
+	AccountID   int64              `json:"account_id"`
+	AccrualDate pgtype.Date        `json:"accrual_date"`
+	Balance     float64            `json:"balance"`
+	Amount      float64            `json:"amount"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type PasswordReset struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// sha256 of the emailed token, the token itself is never stored
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+// token buckets shared by every instance when rate limits are kept in postgres
+type RateLimitBucket struct {
+	Key    string  `json:"key"`
+	Tokens float64 `json:"tokens"`
+	// whether the last take found a token
+	Allowed   bool               `json:"allowed"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type RecoveryCode struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// sha256 of the normalized code
+	CodeHash  string             `json:"code_hash"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type Session struct {
@@ -34,22 +145,120 @@ type Session struct {
 	CreatedAt    pgtype.Timestamptz `json:"created_at"`
 }
 
+// password sign-ins of 2FA users waiting for their second factor
+type SigninChallenge struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// sha256 of the challenge token, the token itself is never stored
+	TokenHash string             `json:"token_hash"`
+	Attempts  int32              `json:"attempts"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+// failed sign-in counters, per user and per client ip
+type SigninThrottle struct {
+	// user:<id> or ip:<address>
+	Key          string             `json:"key"`
+	Failures     int32              `json:"failures"`
+	LastFailedAt pgtype.Timestamptz `json:"last_failed_at"`
+	LockedUntil  pgtype.Timestamptz `json:"locked_until"`
+	// attempts let through that are not recorded yet
+	Reserved   int32              `json:"reserved"`
+	ReservedAt pgtype.Timestamptz `json:"reserved_at"`
+}
+
+// re-authentication demanded before a sensitive action, such as a large transfer
+type StepUpChallenge struct {
+	ID     pgtype.UUID `json:"id"`
+	UserID int64       `json:"user_id"`
+	Action string      `json:"action"`
+	// sha256 of the action parameters, the step-up token is only good for exactly these
+	ParamsHash string `json:"params_hash"`
+	Attempts   int32  `json:"attempts"`
+	// sha256 of the step-up token issued once the user re-authenticated
+	TokenHash  pgtype.Text        `json:"token_hash"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	VerifiedAt pgtype.Timestamptz `json:"verified_at"`
+	UsedAt     pgtype.Timestamptz `json:"used_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+}
+
+type SystemAccount struct {
+	Purpose   string             `json:"purpose"`
+	Currency  string             `json:"currency"`
+	AccountID int64              `json:"account_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
 type Transfer struct {
 	ID            int64       `json:"id"`
 	FromAccountID pgtype.Int8 `json:"from_account_id"`
 	ToAccountID   pgtype.Int8 `json:"to_account_id"`
 	// amount must be positive
-	Amount    float64            `json:"amount"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	Amount      float64            `json:"amount"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	Fee         float64            `json:"fee"`
+	FeePolicyID pgtype.Int8        `json:"fee_policy_id"`
+	// total refunded through reversals, never above amount
+	ReversedAmount float64 `json:"reversed_amount"`
+	// set on compensating transfers created by a reversal
+	OriginalTransferID pgtype.Int8 `json:"original_transfer_id"`
+}
+
+type TransferHold struct {
+	ID            int64   `json:"id"`
+	FromAccountID int64   `json:"from_account_id"`
+	ToAccountID   int64   `json:"to_account_id"`
+	Amount        float64 `json:"amount"`
+	// fee quoted at authorization, charged on capture
+	Fee         float64            `json:"fee"`
+	FeePolicyID pgtype.Int8        `json:"fee_policy_id"`
+	Status      string             `json:"status"`
+	TransferID  pgtype.Int8        `json:"transfer_id"`
+	ExpiresAt   pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type TransferReversal struct {
+	ID                 int64              `json:"id"`
+	TransferID         int64              `json:"transfer_id"`
+	ReversalTransferID int64              `json:"reversal_transfer_id"`
+	Amount             float64            `json:"amount"`
+	Reason             string             `json:"reason"`
+	ReversedBy         int64              `json:"reversed_by"`
+	CreatedAt          pgtype.Timestamptz `json:"created_at"`
 }
 
 type User struct {
-	ID                int64              `json:"id"`
-	Username          string             `json:"username"`
-	Email             string             `json:"email"`
-	Fullname          string             `json:"fullname"`
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Fullname string `json:"fullname"`
+	// argon2id or scrypt PHC string, or bcrypt modular crypt string; the prefix names the algorithm
 	HashedPassword    string             `json:"hashed_password"`
-	PasswordSalt      string             `json:"password_salt"`
 	PasswordChangedAt pgtype.Timestamptz `json:"password_changed_at"`
 	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	Role              string             `json:"role"`
+	IsEmailVerified   bool               `json:"is_email_verified"`
+	EmailVerifiedAt   pgtype.Timestamptz `json:"email_verified_at"`
+	// base32 secret, set at enrollment and only in force once is_totp_enabled
+	TotpSecret    pgtype.Text `json:"totp_secret"`
+	IsTotpEnabled bool        `json:"is_totp_enabled"`
+	// time step of the last accepted code, older or equal steps are replays
+	TotpLastStep pgtype.Int8 `json:"totp_last_step"`
+}
+
+type VerifyEmail struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+	// address the token was sent to, the token is void once the user changes it
+	Email string `json:"email"`
+	// sha256 of the emailed token, the token itself is never stored
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/fee"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type AccrueOverdraftTxParams struct {
+	AccountID int64     `json:"account_id"`
+	Date      time.Time `json:"date"`
+	// AnnualRate is the yearly interest charged on the negative balance,
+	// in percent. It is applied as a simple daily rate of AnnualRate/365.
+	AnnualRate float64 `json:"annual_rate"`
+	// DailyFee is a flat charge added for each day spent overdrawn.
+	DailyFee float64 `json:"daily_fee"`
+}
+
+// OverdraftCharge returns the charge for one day spent at balance.
+// Nothing is charged on a non-negative balance.
+func OverdraftCharge(balance, annualRate, dailyFee float64) float64 {
+	if balance >= 0 {
+		return 0
+	}
+
+	return fee.Round(-balance*annualRate/100/365 + dailyFee)
+}
+
+// AccrueOverdraftTx charges one day of overdraft interest and fees to an
+// account overdrawn at the end of date (UTC), crediting the overdraft
+// revenue account. Each account is charged at most once per date; a
+// repeated call or an account that was not overdrawn returns nil.
+func (s *PgStore) AccrueOverdraftTx(ctx context.Context, arg AccrueOverdraftTxParams) (*OverdraftAccrual, error) {
+	var accrual *OverdraftAccrual
+
+	date := arg.Date.UTC().Truncate(24 * time.Hour)
+	endOfDay := date.AddDate(0, 0, 1)
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		account, err := q.GetAccountByIDForUpdate(ctx, arg.AccountID)
+
+		if err != nil {
+			return err
+		}
+
+		if account.CreatedAt.Valid && !account.CreatedAt.Time.Before(endOfDay) {
+			return nil
+		}
+
+		// Entries booked after the cutoff, including charges for later
+		// days, are backed out of the current balance.
+		balance, err := q.GetAccountBalanceAt(ctx, GetAccountBalanceAtParams{
+			ID: account.ID,
+			At: pgtype.Timestamptz{Time: endOfDay, Valid: true},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		amount := OverdraftCharge(balance, arg.AnnualRate, arg.DailyFee)
+
+		if amount <= 0 {
+			return nil
+		}
+
+		claimed, err := q.CreateOverdraftAccrual(ctx, CreateOverdraftAccrualParams{
+			AccountID:   account.ID,
+			AccrualDate: pgtype.Date{Time: date, Valid: true},
+			Balance:     balance,
+			Amount:      amount,
+		})
+
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Already accrued for this date.
+				return nil
+			}
+
+			return err
+		}
+
+		if _, _, err = postSystemEntries(ctx, q, account, SystemPurposeOverdraftRevenue, -amount, pgtype.Int8{}); err != nil {
+			return err
+		}
+
+		accrual = &claimed
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return accrual, nil
+}
@@ -0,0 +1,76 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: overdraft_accrual.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createOverdraftAccrual = `-- name: CreateOverdraftAccrual :one
+INSERT INTO overdraft_accruals(account_id, accrual_date, balance, amount)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (account_id, accrual_date) DO NOTHING
+RETURNING account_id, accrual_date, balance, amount, created_at
+`
+
+type CreateOverdraftAccrualParams struct {
+	AccountID   int64       `json:"account_id"`
+	AccrualDate pgtype.Date `json:"accrual_date"`
+	Balance     float64     `json:"balance"`
+	Amount      float64     `json:"amount"`
+}
+
+func (q *Queries) CreateOverdraftAccrual(ctx context.Context, arg CreateOverdraftAccrualParams) (OverdraftAccrual, error) {
+	row := q.db.QueryRow(ctx, createOverdraftAccrual,
+		arg.AccountID,
+		arg.AccrualDate,
+		arg.Balance,
+		arg.Amount,
+	)
+	var i OverdraftAccrual
+	err := row.Scan(
+		&i.AccountID,
+		&i.AccrualDate,
+		&i.Balance,
+		&i.Amount,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getOverdraftAccruals = `-- name: GetOverdraftAccruals :many
+SELECT account_id, accrual_date, balance, amount, created_at FROM overdraft_accruals
+WHERE account_id = $1
+ORDER BY accrual_date DESC
+`
+
+func (q *Queries) GetOverdraftAccruals(ctx context.Context, accountID int64) ([]OverdraftAccrual, error) {
+	rows, err := q.db.Query(ctx, getOverdraftAccruals, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []OverdraftAccrual{}
+	for rows.Next() {
+		var i OverdraftAccrual
+		if err := rows.Scan(
+			&i.AccountID,
+			&i.AccrualDate,
+			&i.Balance,
+			&i.Amount,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
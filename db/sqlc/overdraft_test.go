@@ -0,0 +1,118 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferIntoOverdraft(t *testing.T) {
+	account1, account2 := createRandomAccountPair(t, 10)
+
+	_, err := testQueries.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        50,
+	})
+	require.ErrorIs(t, err, ErrFundNotSufficient)
+
+	account1, err = testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: 100,
+	})
+	require.NoError(t, err)
+	require.Equal(t, float64(110), account1.SpendableBalance())
+
+	result, err := testQueries.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        50,
+	})
+	require.NoError(t, err)
+	require.Equal(t, float64(-40), result.FromAccount.Balance)
+
+	_, err = testQueries.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        70.01,
+	})
+	require.ErrorIs(t, err, ErrFundNotSufficient)
+}
+
+func TestAccrueOverdraft(t *testing.T) {
+	account1, account2 := createRandomAccountPair(t, 0)
+
+	_, err := testQueries.UpdateAccountOverdraftLimit(context.Background(), UpdateAccountOverdraftLimitParams{
+		ID:             account1.ID,
+		OverdraftLimit: 1000,
+	})
+	require.NoError(t, err)
+
+	arg := AccrueOverdraftTxParams{
+		AccountID:  account1.ID,
+		Date:       time.Now().UTC().Truncate(24 * time.Hour),
+		AnnualRate: 36.5,
+		DailyFee:   1,
+	}
+
+	// Not overdrawn yet, nothing to charge.
+	accrual, err := testQueries.AccrueOverdraftTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Nil(t, accrual)
+
+	_, err = testQueries.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        100,
+	})
+	require.NoError(t, err)
+
+	// The account ended yesterday at zero: only the overdraft booked today
+	// is charged, and only once today is over.
+	yesterday := arg
+	yesterday.Date = arg.Date.AddDate(0, 0, -1)
+	accrual, err = testQueries.AccrueOverdraftTx(context.Background(), yesterday)
+	require.NoError(t, err)
+	require.Nil(t, accrual)
+
+	require.NotContains(t, overdrawnAccountIDs(t, arg.Date, account1.ID), account1.ID)
+	require.Contains(t, overdrawnAccountIDs(t, arg.Date.AddDate(0, 0, 1), account1.ID), account1.ID)
+
+	accrual, err = testQueries.AccrueOverdraftTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotNil(t, accrual)
+	require.Equal(t, float64(-100), accrual.Balance)
+	require.Equal(t, 1.1, accrual.Amount)
+
+	// A second run for the same date is a no-op.
+	accrual, err = testQueries.AccrueOverdraftTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Nil(t, accrual)
+
+	account1, err = testQueries.GetAccountByID(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, -101.1, account1.Balance)
+
+	accruals, err := testQueries.GetOverdraftAccruals(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Len(t, accruals, 1)
+}
+
+func overdrawnAccountIDs(t *testing.T, at time.Time, id int64) []int64 {
+	accounts, err := testQueries.GetOverdrawnAccounts(context.Background(), GetOverdrawnAccountsParams{
+		At:      pgtype.Timestamptz{Time: at, Valid: true},
+		AfterID: id - 1,
+		Limit:   1,
+	})
+	require.NoError(t, err)
+
+	var ids []int64
+	for _, account := range accounts {
+		ids = append(ids, account.ID)
+	}
+
+	return ids
+}
@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrResetTokenInvalid = util.NewCustomError(util.KindInvalidArgument, "ErrResetTokenInvalid", "password reset link is invalid or already used")
+var ErrResetTokenExpired = util.NewCustomError(util.KindExpired, "ErrResetTokenExpired", "password reset link has expired")
+
+// ChangePasswordTx stores a new password hash for the user, blocks all of
+// their sessions and lifts any sign-in lockout. Setting password_changed_at also invalidates access
+// tokens issued before the change, see api.AuthMiddleware.
+func (s *PgStore) ChangePasswordTx(ctx context.Context, arg UpdateUserPasswordParams) (*User, error) {
+	var user User
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		user, err = changePassword(ctx, q, arg)
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+type ResetPasswordTxParams struct {
+	TokenHash      string `json:"token_hash"`
+	HashedPassword string `json:"hashed_password"`
+}
+
+// ResetPasswordTx consumes the reset token with the given hash and changes
+// its user's password like ChangePasswordTx. Any other outstanding reset
+// tokens of the user are spent as well.
+func (s *PgStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (*User, error) {
+	var user User
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		reset, err := q.GetPasswordResetForUpdate(ctx, arg.TokenHash)
+
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrResetTokenInvalid
+			}
+
+			return err
+		}
+
+		if err = checkResetToken(reset.UsedAt, reset.ExpiresAt); err != nil {
+			return err
+		}
+
+		if err = q.ExpireUserPasswordResets(ctx, reset.UserID); err != nil {
+			return err
+		}
+
+		user, err = changePassword(ctx, q, UpdateUserPasswordParams{
+			ID:             reset.UserID,
+			HashedPassword: arg.HashedPassword,
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// Check returns why the reset token the row was looked up by can no
+// longer be used, or nil if it can. ResetPasswordTx checks again when the
+// token is spent.
+func (r GetPasswordResetUserRow) Check() error {
+	return checkResetToken(r.UsedAt, r.ExpiresAt)
+}
+
+func checkResetToken(usedAt, expiresAt pgtype.Timestamptz) error {
+	if usedAt.Valid {
+		return ErrResetTokenInvalid
+	}
+
+	if time.Now().After(expiresAt.Time) {
+		return ErrResetTokenExpired
+	}
+
+	return nil
+}
+
+func changePassword(ctx context.Context, q *Queries, arg UpdateUserPasswordParams) (User, error) {
+	user, err := q.UpdateUserPassword(ctx, arg)
+
+	if err != nil {
+		return User{}, err
+	}
+
+	if err = q.BlockUserSessions(ctx, user.ID); err != nil {
+		return User{}, err
+	}
+
+	if err = q.DeleteSigninThrottle(ctx, SigninThrottleUserKey(user.ID)); err != nil {
+		return User{}, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomSession(t *testing.T, user User) Session {
+	session, err := testQueries.CreateSession(context.Background(), CreateSessionParams{
+		ID:           pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		OwnerID:      user.ID,
+		UserAgent:    "test",
+		RefreshToken: util.RandomString(32),
+		IsBlocked:    pgtype.Bool{Bool: false, Valid: true},
+		ExpiredAt:    pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	return session
+}
+
+func requireSessionBlocked(t *testing.T, session Session) {
+	got, err := testQueries.GetSessionByUniqueID(context.Background(), GetSessionByUniqueIDParams{ID: session.ID})
+	require.NoError(t, err)
+	require.True(t, got.IsBlocked.Bool)
+}
+
+func TestChangePasswordTx(t *testing.T) {
+	user, _ := createRandomUser(t)
+	session := createRandomSession(t, user)
+
+	updated, err := testQueries.ChangePasswordTx(context.Background(), UpdateUserPasswordParams{
+		ID:             user.ID,
+		HashedPassword: util.RandomString(32),
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, user.HashedPassword, updated.HashedPassword)
+	require.True(t, updated.PasswordChangedAt.Valid)
+	require.WithinDuration(t, time.Now(), updated.PasswordChangedAt.Time, time.Minute)
+
+	requireSessionBlocked(t, session)
+}
+
+func TestResetPasswordTx(t *testing.T) {
+	user, _ := createRandomUser(t)
+	session := createRandomSession(t, user)
+
+	createReset := func(ttl time.Duration) string {
+		token, tokenHash, err := util.NewSecretToken()
+		require.NoError(t, err)
+
+		_, err = testQueries.CreatePasswordReset(context.Background(), CreatePasswordResetParams{
+			UserID:    user.ID,
+			TokenHash: tokenHash,
+			ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+		})
+		require.NoError(t, err)
+
+		return token
+	}
+
+	expired := createReset(-time.Minute)
+	other := createReset(time.Hour)
+	token := createReset(time.Hour)
+
+	lookup := func(token string) GetPasswordResetUserRow {
+		row, err := testQueries.GetPasswordResetUser(context.Background(), util.HashSecretToken(token))
+		require.NoError(t, err)
+		require.Equal(t, user.ID, row.User.ID)
+
+		return row
+	}
+
+	require.ErrorIs(t, lookup(expired).Check(), ErrResetTokenExpired)
+	require.NoError(t, lookup(token).Check())
+
+	arg := ResetPasswordTxParams{
+		TokenHash:      util.HashSecretToken(expired),
+		HashedPassword: util.RandomString(32),
+	}
+
+	_, err := testQueries.ResetPasswordTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrResetTokenExpired)
+
+	arg.TokenHash = util.HashSecretToken(token)
+	updated, err := testQueries.ResetPasswordTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.HashedPassword, updated.HashedPassword)
+	require.True(t, updated.PasswordChangedAt.Valid)
+
+	requireSessionBlocked(t, session)
+
+	// The token is single use, and the user's other links are spent too.
+	_, err = testQueries.ResetPasswordTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrResetTokenInvalid)
+
+	arg.TokenHash = util.HashSecretToken(other)
+	_, err = testQueries.ResetPasswordTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrResetTokenInvalid)
+
+	require.ErrorIs(t, lookup(token).Check(), ErrResetTokenInvalid)
+	require.ErrorIs(t, lookup(other).Check(), ErrResetTokenInvalid)
+}
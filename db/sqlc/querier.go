@@ -12,23 +12,110 @@ import (
 )
 
 type Querier interface {
+	AddAccountBalance(ctx context.Context, arg AddAccountBalanceParams) (Account, error)
+	AddAccountHeldBalance(ctx context.Context, arg AddAccountHeldBalanceParams) (Account, error)
+	AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfer, error)
+	AdvanceUserTOTPStep(ctx context.Context, arg AdvanceUserTOTPStepParams) (User, error)
+	BlockUserSessions(ctx context.Context, ownerID int64) error
+	// Locks the throttles of keys for the rest of the transaction, creating
+	// those that do not exist yet.
+	ClaimSigninThrottles(ctx context.Context, keys []string) ([]SigninThrottle, error)
+	ConsumeStepUpToken(ctx context.Context, arg ConsumeStepUpTokenParams) (StepUpChallenge, error)
+	CountPasswordResetsSince(ctx context.Context, arg CountPasswordResetsSinceParams) (int64, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID int64) (int64, error)
+	CountVerifyEmailsSince(ctx context.Context, arg CountVerifyEmailsSinceParams) (int64, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error)
+	CreateAuthAudit(ctx context.Context, arg CreateAuthAuditParams) (AuthAudit, error)
 	CreateBalanceEntry(ctx context.Context, arg CreateBalanceEntryParams) (Entry, error)
+	CreateFeePolicy(ctx context.Context, arg CreateFeePolicyParams) (FeePolicy, error)
+	CreateInterestAccrual(ctx context.Context, arg CreateInterestAccrualParams) (InterestAccrual, error)
+	CreateInterestRate(ctx context.Context, arg CreateInterestRateParams) (InterestRate, error)
+	CreateOverdraftAccrual(ctx context.Context, arg CreateOverdraftAccrualParams) (OverdraftAccrual, error)
+	CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) (RecoveryCode, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateSigninChallenge(ctx context.Context, arg CreateSigninChallengeParams) (SigninChallenge, error)
+	CreateStepUpChallenge(ctx context.Context, arg CreateStepUpChallengeParams) (StepUpChallenge, error)
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error)
+	CreateTransferHold(ctx context.Context, arg CreateTransferHoldParams) (TransferHold, error)
+	CreateTransferReversal(ctx context.Context, arg CreateTransferReversalParams) (TransferReversal, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error)
+	DeactivateFeePolicy(ctx context.Context, id int64) (FeePolicy, error)
 	DeleteAccount(ctx context.Context, id int64) error
+	DeleteSigninThrottle(ctx context.Context, key string) error
+	DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt pgtype.Timestamptz) (int64, error)
+	DeleteUserRecoveryCodes(ctx context.Context, userID int64) error
+	DisableUserTOTP(ctx context.Context, id int64) (User, error)
+	EnableUserTOTP(ctx context.Context, id int64) (User, error)
+	ExpireUserPasswordResets(ctx context.Context, userID int64) error
+	GetAccountBalanceAt(ctx context.Context, arg GetAccountBalanceAtParams) (float64, error)
 	GetAccountBalanceEntries(ctx context.Context, accountID pgtype.Int8) ([]Entry, error)
 	GetAccountByID(ctx context.Context, id int64) (Account, error)
 	GetAccountByIDForUpdate(ctx context.Context, id int64) (Account, error)
 	GetAccounts(ctx context.Context, arg GetAccountsParams) ([]Account, error)
+	GetAccountsByType(ctx context.Context, arg GetAccountsByTypeParams) ([]Account, error)
+	GetActiveFeePolicy(ctx context.Context, currency string) (FeePolicy, error)
 	GetBalanceEntry(ctx context.Context, id int64) (Entry, error)
+	GetEffectiveInterestRate(ctx context.Context, arg GetEffectiveInterestRateParams) (InterestRate, error)
+	GetExpiredTransferHolds(ctx context.Context, limit int32) ([]TransferHold, error)
+	GetFeePolicies(ctx context.Context, currency pgtype.Text) ([]FeePolicy, error)
+	GetInterestAccruals(ctx context.Context, accountID int64) ([]InterestAccrual, error)
+	GetInterestRates(ctx context.Context) ([]InterestRate, error)
+	GetJobWatermark(ctx context.Context, job string) (JobWatermark, error)
+	GetOldestAccountCreatedAt(ctx context.Context) (pgtype.Timestamptz, error)
+	GetOverdraftAccruals(ctx context.Context, accountID int64) ([]OverdraftAccrual, error)
+	// Returns the accounts whose balance was negative at the given time, backing
+	// out entries booked since. System accounts such as interest expense run
+	// negative by design and are never charged.
+	GetOverdrawnAccounts(ctx context.Context, arg GetOverdrawnAccountsParams) ([]Account, error)
+	GetPasswordResetForUpdate(ctx context.Context, tokenHash string) (PasswordReset, error)
+	GetPasswordResetUser(ctx context.Context, tokenHash string) (GetPasswordResetUserRow, error)
 	GetSessionByUniqueID(ctx context.Context, arg GetSessionByUniqueIDParams) (Session, error)
 	GetSessionList(ctx context.Context, arg GetSessionListParams) ([]Session, error)
+	GetSigninChallengeForUpdate(ctx context.Context, tokenHash string) (SigninChallenge, error)
+	GetSigninThrottles(ctx context.Context, keys []string) ([]SigninThrottle, error)
+	GetStatementEntries(ctx context.Context, arg GetStatementEntriesParams) ([]GetStatementEntriesRow, error)
+	GetStepUpChallenge(ctx context.Context, id pgtype.UUID) (StepUpChallenge, error)
+	GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (SystemAccount, error)
+	GetTransfer(ctx context.Context, id int64) (Transfer, error)
+	GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error)
+	GetTransferHold(ctx context.Context, id int64) (TransferHold, error)
+	GetTransferHoldForUpdate(ctx context.Context, id int64) (TransferHold, error)
+	GetTransferReversals(ctx context.Context, transferID int64) ([]TransferReversal, error)
+	GetUnpostedInterestAccountIDs(ctx context.Context, arg GetUnpostedInterestAccountIDsParams) ([]int64, error)
+	GetUnpostedInterestAccrualsForUpdate(ctx context.Context, arg GetUnpostedInterestAccrualsForUpdateParams) ([]InterestAccrual, error)
 	GetUserByUniqueID(ctx context.Context, arg GetUserByUniqueIDParams) (User, error)
+	GetUserForUpdate(ctx context.Context, id int64) (User, error)
 	GetUsers(ctx context.Context, arg GetUsersParams) ([]User, error)
+	GetVerifyEmailForUpdate(ctx context.Context, tokenHash string) (VerifyEmail, error)
+	IncrementSigninChallengeAttempts(ctx context.Context, id int64) (SigninChallenge, error)
+	IssueStepUpToken(ctx context.Context, arg IssueStepUpTokenParams) (StepUpChallenge, error)
+	ListUserAuthAudit(ctx context.Context, arg ListUserAuthAuditParams) ([]AuthAudit, error)
+	LockSigninThrottle(ctx context.Context, arg LockSigninThrottleParams) (SigninThrottle, error)
+	MarkInterestAccrualsPosted(ctx context.Context, arg MarkInterestAccrualsPostedParams) (int64, error)
+	MarkPasswordResetUsed(ctx context.Context, id int64) (PasswordReset, error)
+	MarkSigninChallengeUsed(ctx context.Context, id int64) (SigninChallenge, error)
+	MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (User, error)
+	MarkVerifyEmailUsed(ctx context.Context, id int64) (VerifyEmail, error)
+	RecordSigninFailure(ctx context.Context, arg RecordSigninFailureParams) (SigninThrottle, error)
+	RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) (int64, error)
+	ReleaseSigninReservation(ctx context.Context, key string) error
+	// Counts an attempt let through until it is recorded. Reservations left
+	// over from before reset_before were never recorded and are dropped.
+	ReserveSigninAttempt(ctx context.Context, arg ReserveSigninAttemptParams) error
+	SetJobWatermark(ctx context.Context, arg SetJobWatermarkParams) error
+	SetUserTOTPSecret(ctx context.Context, arg SetUserTOTPSecretParams) (User, error)
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (RateLimitBucket, error)
+	// Takes one of the challenge's attempts before the answer is checked, so
+	// parallel answers cannot exceed max_attempts between them.
+	TakeStepUpAttempt(ctx context.Context, arg TakeStepUpAttemptParams) (StepUpChallenge, error)
+	UpdateAccountOverdraftLimit(ctx context.Context, arg UpdateAccountOverdraftLimitParams) (Account, error)
 	UpdateBalance(ctx context.Context, arg UpdateBalanceParams) (Account, error)
 	UpdateTransferAccountBalance(ctx context.Context, arg UpdateTransferAccountBalanceParams) (pgconn.CommandTag, error)
+	UpdateTransferHoldStatus(ctx context.Context, arg UpdateTransferHoldStatusParams) (TransferHold, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (RecoveryCode, error)
 }
 
 var _ Querier = (*Queries)(nil)
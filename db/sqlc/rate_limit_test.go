@@ -0,0 +1,30 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeRateLimitToken(t *testing.T) {
+	arg := TakeRateLimitTokenParams{
+		Key:   "test|ip:" + util.RandomString(12),
+		Burst: 2,
+		// Slow enough that nothing refills while the test runs.
+		Rate: 0.0001,
+	}
+
+	for i := 0; i < 2; i++ {
+		bucket, err := testQueries.TakeRateLimitToken(context.Background(), arg)
+		require.NoError(t, err)
+		require.True(t, bucket.Allowed)
+	}
+
+	bucket, err := testQueries.TakeRateLimitToken(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, bucket.Allowed)
+	require.Less(t, bucket.Tokens, 1.0)
+	require.GreaterOrEqual(t, bucket.Tokens, 0.0)
+}
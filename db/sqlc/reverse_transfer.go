@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/devphasex/cedar-bank-api/fee"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrTransferNotFound = util.NewCustomError(util.KindNotFound, "ErrTransferNotFound", "transfer not found")
+var ErrTransferAlreadyReversed = util.NewCustomError(util.KindConflict, "ErrTransferAlreadyReversed", "transfer has already been fully reversed")
+var ErrReversalExceedsTransfer = util.NewCustomError(util.KindInvalidArgument, "ErrReversalExceedsTransfer", "reversal amount exceeds the amount left to refund")
+var ErrReversalOfReversal = util.NewCustomError(util.KindConflict, "ErrReversalOfReversal", "a reversal transfer cannot itself be reversed")
+var ErrInvalidReversalAmount = util.NewCustomError(util.KindInvalidArgument, "ErrInvalidReversalAmount", "reversal amount must be positive")
+
+type ReverseTransferTxParams struct {
+	TransferID int64 `json:"transfer_id"`
+	// Amount to refund. Zero refunds whatever is left of the original.
+	Amount     float64 `json:"amount"`
+	Reason     string  `json:"reason"`
+	ReversedBy int64   `json:"reversed_by"`
+}
+
+type ReverseTransferTxResult struct {
+	TransferTxResult
+	OriginalTransfer Transfer         `json:"original_transfer"`
+	Reversal         TransferReversal `json:"reversal"`
+}
+
+// ReverseTransferTx refunds all or part of a transfer by moving funds back
+// from the recipient to the sender through a compensating transfer linked
+// to the original. The fee charged on the original transfer is kept.
+func (s *PgStore) ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (*ReverseTransferTxResult, error) {
+	var txResult ReverseTransferTxResult
+
+	if arg.Amount < 0 {
+		return nil, ErrInvalidReversalAmount
+	}
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		original, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return errors.Join(fmt.Errorf("transfer with '%d' not found", arg.TransferID), ErrTransferNotFound)
+			}
+
+			return err
+		}
+
+		if original.OriginalTransferID.Valid {
+			return ErrReversalOfReversal
+		}
+
+		remaining := fee.Round(original.Amount - original.ReversedAmount)
+
+		if remaining <= 0 {
+			return ErrTransferAlreadyReversed
+		}
+
+		amount := arg.Amount
+		if amount == 0 {
+			amount = remaining
+		}
+
+		if amount > remaining {
+			return ErrReversalExceedsTransfer
+		}
+
+		// The recipient of the original transfer pays the refund.
+		payer, _, err := lockTransferAccounts(ctx, q, original.ToAccountID.Int64, original.FromAccountID.Int64)
+
+		if err != nil {
+			return err
+		}
+
+		if payer.SpendableBalance() < amount {
+			return ErrFundNotSufficient
+		}
+
+		err = moveFunds(ctx, q, &txResult.TransferTxResult, CreateTransferParams{
+			FromAccountID:      original.ToAccountID,
+			ToAccountID:        original.FromAccountID,
+			Amount:             amount,
+			OriginalTransferID: pgtype.Int8{Int64: original.ID, Valid: true},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		txResult.OriginalTransfer, err = q.AddTransferReversedAmount(ctx, AddTransferReversedAmountParams{
+			ID:     original.ID,
+			Amount: amount,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		txResult.Reversal, err = q.CreateTransferReversal(ctx, CreateTransferReversalParams{
+			TransferID:         original.ID,
+			ReversalTransferID: txResult.Transfer.ID,
+			Amount:             amount,
+			Reason:             arg.Reason,
+			ReversedBy:         arg.ReversedBy,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return fetchTransferAccounts(ctx, q, &txResult.TransferTxResult)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &txResult, nil
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTransfer(t *testing.T, amount float64) (*TransferTxResult, Account, Account) {
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	result, err := testQueries.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+
+	return result, result.FromAccount, result.ToAccount
+}
+
+func TestReverseTransferPartially(t *testing.T) {
+	transfer, sender, recipient := createRandomTransfer(t, 40)
+	admin, _ := createRandomUser(t)
+
+	result, err := testQueries.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     15,
+		Reason:     "partial refund",
+		ReversedBy: admin.ID,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, transfer.Transfer.ID, result.Transfer.OriginalTransferID.Int64)
+	require.Equal(t, recipient.ID, result.Transfer.FromAccountID.Int64)
+	require.Equal(t, sender.ID, result.Transfer.ToAccountID.Int64)
+	require.Equal(t, 15.0, result.OriginalTransfer.ReversedAmount)
+
+	require.Equal(t, sender.Balance+15, result.ToAccount.Balance)
+	require.Equal(t, recipient.Balance-15, result.FromAccount.Balance)
+
+	require.Equal(t, admin.ID, result.Reversal.ReversedBy)
+	require.Equal(t, "partial refund", result.Reversal.Reason)
+	require.Equal(t, result.Transfer.ID, result.Reversal.ReversalTransferID)
+
+	_, err = testQueries.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Amount:     30,
+		Reason:     "too much",
+		ReversedBy: admin.ID,
+	})
+	require.ErrorIs(t, err, ErrReversalExceedsTransfer)
+
+	// Zero refunds the remainder.
+	result, err = testQueries.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Reason:     "rest of it",
+		ReversedBy: admin.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 25.0, result.Transfer.Amount)
+	require.Equal(t, 40.0, result.OriginalTransfer.ReversedAmount)
+
+	reversals, err := testQueries.GetTransferReversals(context.Background(), transfer.Transfer.ID)
+	require.NoError(t, err)
+	require.Len(t, reversals, 2)
+}
+
+func TestReverseTransferTwice(t *testing.T) {
+	transfer, _, _ := createRandomTransfer(t, 10)
+	admin, _ := createRandomUser(t)
+
+	result, err := testQueries.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Reason:     "mistake",
+		ReversedBy: admin.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: transfer.Transfer.ID,
+		Reason:     "mistake",
+		ReversedBy: admin.ID,
+	})
+	require.ErrorIs(t, err, ErrTransferAlreadyReversed)
+
+	_, err = testQueries.ReverseTransferTx(context.Background(), ReverseTransferTxParams{
+		TransferID: result.Transfer.ID,
+		Reason:     "undo the undo",
+		ReversedBy: admin.ID,
+	})
+	require.ErrorIs(t, err, ErrReversalOfReversal)
+}
@@ -11,6 +11,17 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const blockUserSessions = `-- name: BlockUserSessions :exec
+UPDATE sessions
+SET is_blocked = true
+WHERE owner_id = $1 AND is_blocked IS NOT TRUE
+`
+
+func (q *Queries) BlockUserSessions(ctx context.Context, ownerID int64) error {
+	_, err := q.db.Exec(ctx, blockUserSessions, ownerID)
+	return err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions(
 id,
@@ -0,0 +1,315 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Outcomes of a sign-in attempt as recorded in auth_audit. Only
+// SigninBadCredentials counts towards a lockout. A step-up answered wrong
+// is recorded as bad credentials too, and one answered right as
+// SigninSteppedUp.
+const (
+	SigninSucceeded      = "succeeded"
+	SigninChallenged     = "two_factor_required"
+	SigninBadCredentials = "bad_credentials"
+	SigninUnverified     = "email_not_verified"
+	SigninThrottled      = "throttled"
+	SigninSteppedUp      = "stepped_up"
+)
+
+// SigninPolicy decides how failed sign-ins slow down further attempts.
+// After each failure the next attempt has to wait BaseDelay, doubling per
+// failure up to MaxDelay. Reaching MaxUserFailures for a user, or
+// MaxIPFailures for a client ip, locks it out for LockoutDuration.
+// Failures older than FailureWindow are forgotten.
+type SigninPolicy struct {
+	MaxUserFailures int32
+	MaxIPFailures   int32
+	LockoutDuration time.Duration
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	FailureWindow   time.Duration
+}
+
+func NewSigninPolicy(config *util.Config) SigninPolicy {
+	return SigninPolicy{
+		MaxUserFailures: config.SigninMaxFailures,
+		MaxIPFailures:   config.SigninIPMaxFailures,
+		LockoutDuration: config.SigninLockoutDuration,
+		BaseDelay:       config.SigninDelayBase,
+		MaxDelay:        config.SigninDelayMax,
+		FailureWindow:   config.SigninFailureWindow,
+	}
+}
+
+// SigninThrottleUserKey is the signin_throttles key of a user.
+func SigninThrottleUserKey(userID int64) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+// SigninThrottleIPKey is the signin_throttles key of a client ip. A port,
+// as in a gRPC peer address, is dropped so every connection from the host
+// shares one counter.
+func SigninThrottleIPKey(clientIP string) string {
+	if host, _, err := net.SplitHostPort(clientIP); err == nil {
+		clientIP = host
+	}
+
+	return "ip:" + clientIP
+}
+
+// delay is the wait imposed after the given number of failures.
+func (p SigninPolicy) delay(failures int32) time.Duration {
+	if failures <= 0 || p.BaseDelay <= 0 {
+		return 0
+	}
+
+	d := p.BaseDelay
+	for i := int32(1); i < failures && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+
+	return min(d, p.MaxDelay)
+}
+
+// retryAt is when the throttle next lets an attempt through, zero if it
+// does now. Attempts let through and not recorded yet count as failures
+// made when the last of them was let through.
+func (p SigninPolicy) retryAt(throttle SigninThrottle, now time.Time) time.Time {
+	if throttle.LockedUntil.Valid && throttle.LockedUntil.Time.After(now) {
+		return throttle.LockedUntil.Time
+	}
+
+	var failures int32
+	var since time.Time
+
+	if now.Sub(throttle.LastFailedAt.Time) <= p.FailureWindow {
+		failures, since = throttle.Failures, throttle.LastFailedAt.Time
+	}
+
+	if throttle.Reserved > 0 && now.Sub(throttle.ReservedAt.Time) <= p.FailureWindow {
+		failures += throttle.Reserved
+
+		if throttle.ReservedAt.Time.After(since) {
+			since = throttle.ReservedAt.Time
+		}
+	}
+
+	if failures == 0 {
+		return time.Time{}
+	}
+
+	if at := since.Add(p.delay(failures)); at.After(now) {
+		return at
+	}
+
+	return time.Time{}
+}
+
+type SigninRetryAtParams struct {
+	// UserID is zero when the identifier matched no user.
+	UserID   int64        `json:"user_id"`
+	ClientIP string       `json:"client_ip"`
+	Policy   SigninPolicy `json:"-"`
+}
+
+// keys are the signin_throttles keys of the attempt, in the order they
+// are locked in.
+func (arg SigninRetryAtParams) keys() []string {
+	keys := []string{SigninThrottleIPKey(arg.ClientIP)}
+
+	if arg.UserID != 0 {
+		keys = append(keys, SigninThrottleUserKey(arg.UserID))
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+// latestRetryAt is when the last of throttles lets an attempt through,
+// zero if they all do now.
+func (p SigninPolicy) latestRetryAt(throttles []SigninThrottle, now time.Time) time.Time {
+	var retryAt time.Time
+
+	for _, throttle := range throttles {
+		if at := p.retryAt(throttle, now); at.After(retryAt) {
+			retryAt = at
+		}
+	}
+
+	return retryAt
+}
+
+// SigninRetryAt reports when the user and client ip may attempt to sign in
+// again. The zero time means right now.
+func (s *PgStore) SigninRetryAt(ctx context.Context, arg SigninRetryAtParams) (time.Time, error) {
+	throttles, err := s.GetSigninThrottles(ctx, arg.keys())
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return arg.Policy.latestRetryAt(throttles, time.Now()), nil
+}
+
+// ReserveSigninAttemptTx lets an attempt through if the user and client ip
+// may sign in now, reporting when they may otherwise, as SigninRetryAt
+// does. An attempt let through is reserved at once, under the throttles'
+// row locks, and delays the next one as a failure would until it is
+// recorded, so parallel attempts cannot all pass before the first is.
+// Recording it with Reserved set releases the reservation; it never moves
+// the time of the last failure.
+func (s *PgStore) ReserveSigninAttemptTx(ctx context.Context, arg SigninRetryAtParams) (time.Time, error) {
+	var retryAt time.Time
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		keys := arg.keys()
+		throttles, err := q.ClaimSigninThrottles(ctx, keys)
+
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		if retryAt = arg.Policy.latestRetryAt(throttles, now); !retryAt.IsZero() {
+			return nil
+		}
+
+		for _, key := range keys {
+			err = q.ReserveSigninAttempt(ctx, ReserveSigninAttemptParams{
+				ResetBefore: pgtype.Timestamptz{Time: now.Add(-arg.Policy.FailureWindow), Valid: true},
+				Key:         key,
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return retryAt, nil
+}
+
+type RecordSigninAttemptTxParams struct {
+	// UserID is zero when the identifier matched no user.
+	UserID     int64        `json:"user_id"`
+	Identifier string       `json:"identifier"`
+	ClientIP   string       `json:"client_ip"`
+	UserAgent  string       `json:"user_agent"`
+	Outcome    string       `json:"outcome"`
+	Policy     SigninPolicy `json:"-"`
+	// Reserved is set once ReserveSigninAttemptTx let the attempt
+	// through.
+	Reserved bool `json:"-"`
+}
+
+// RecordSigninAttemptTx writes the attempt to auth_audit and updates the
+// throttles. Bad credentials count against both the user and the client
+// ip; a successful sign-in clears the user's counter but not the ip's, so
+// an attacker cannot reset it by signing in to their own account. A
+// reserved attempt has its reservation released whatever the outcome.
+func (s *PgStore) RecordSigninAttemptTx(ctx context.Context, arg RecordSigninAttemptTxParams) (*AuthAudit, error) {
+	var audit AuthAudit
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		audit, err = q.CreateAuthAudit(ctx, CreateAuthAuditParams{
+			UserID:     pgtype.Int8{Int64: arg.UserID, Valid: arg.UserID != 0},
+			Identifier: arg.Identifier,
+			ClientIp:   arg.ClientIP,
+			UserAgent:  arg.UserAgent,
+			Outcome:    arg.Outcome,
+			Success:    arg.Outcome == SigninSucceeded,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		userKey, ipKey := SigninThrottleUserKey(arg.UserID), SigninThrottleIPKey(arg.ClientIP)
+
+		// The ip's throttle first, in the order ReserveSigninAttemptTx
+		// locks them in.
+		switch {
+		case arg.Outcome == SigninBadCredentials:
+			err = countSigninFailure(ctx, q, arg.Policy, ipKey, arg.Policy.MaxIPFailures, arg.Reserved)
+
+			if err != nil || arg.UserID == 0 {
+				return err
+			}
+
+			return countSigninFailure(ctx, q, arg.Policy, userKey, arg.Policy.MaxUserFailures, arg.Reserved)
+		case arg.Outcome == SigninSucceeded:
+			if arg.Reserved {
+				if err = q.ReleaseSigninReservation(ctx, ipKey); err != nil {
+					return err
+				}
+			}
+
+			return q.DeleteSigninThrottle(ctx, userKey)
+		case arg.Reserved:
+			if err = q.ReleaseSigninReservation(ctx, ipKey); err != nil || arg.UserID == 0 {
+				return err
+			}
+
+			return q.ReleaseSigninReservation(ctx, userKey)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &audit, nil
+}
+
+// countSigninFailure counts a failure against key, releasing the
+// attempt's reservation if it has one, and locks it once maxFailures is
+// reached. Locking starts the count over, so the attempts after a lockout
+// expires are delayed afresh.
+func countSigninFailure(ctx context.Context, q *Queries, policy SigninPolicy, key string, maxFailures int32, reserved bool) error {
+	now := time.Now()
+
+	throttle, err := q.RecordSigninFailure(ctx, RecordSigninFailureParams{
+		Key:         key,
+		ResetBefore: pgtype.Timestamptz{Time: now.Add(-policy.FailureWindow), Valid: true},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if reserved {
+		if err = q.ReleaseSigninReservation(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	if maxFailures <= 0 || throttle.Failures < maxFailures {
+		return nil
+	}
+
+	_, err = q.LockSigninThrottle(ctx, LockSigninThrottleParams{
+		Key:         key,
+		LockedUntil: pgtype.Timestamptz{Time: now.Add(policy.LockoutDuration), Valid: true},
+	})
+
+	return err
+}
@@ -0,0 +1,304 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func randomClientIP() string {
+	return fmt.Sprintf("10.%d.%d.%d", util.RandomInt(0, 255), util.RandomInt(0, 255), util.RandomInt(1, 254))
+}
+
+func TestSigninLockout(t *testing.T) {
+	user, _ := createRandomUser(t)
+	clientIP := randomClientIP()
+
+	policy := SigninPolicy{
+		MaxUserFailures: 3,
+		MaxIPFailures:   100,
+		LockoutDuration: time.Minute,
+		FailureWindow:   time.Hour,
+	}
+
+	attempt := RecordSigninAttemptTxParams{
+		UserID:     user.ID,
+		Identifier: user.Email,
+		ClientIP:   clientIP,
+		UserAgent:  "test",
+		Outcome:    SigninBadCredentials,
+		Policy:     policy,
+	}
+	retryArg := SigninRetryAtParams{UserID: user.ID, ClientIP: clientIP, Policy: policy}
+
+	for i := 0; i < 2; i++ {
+		_, err := testQueries.RecordSigninAttemptTx(context.Background(), attempt)
+		require.NoError(t, err)
+	}
+
+	retryAt, err := testQueries.SigninRetryAt(context.Background(), retryArg)
+	require.NoError(t, err)
+	require.True(t, retryAt.IsZero())
+
+	audit, err := testQueries.RecordSigninAttemptTx(context.Background(), attempt)
+	require.NoError(t, err)
+	require.Equal(t, SigninBadCredentials, audit.Outcome)
+	require.False(t, audit.Success)
+
+	retryAt, err = testQueries.SigninRetryAt(context.Background(), retryArg)
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Minute), retryAt, 5*time.Second)
+
+	// The lockout is the user's, the client ip may still sign in others.
+	retryAt, err = testQueries.SigninRetryAt(context.Background(), SigninRetryAtParams{ClientIP: clientIP, Policy: policy})
+	require.NoError(t, err)
+	require.True(t, retryAt.IsZero())
+
+	audits, err := testQueries.ListUserAuthAudit(context.Background(), ListUserAuthAuditParams{
+		UserID: pgtype.Int8{Int64: user.ID, Valid: true},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, audits, 3)
+
+	// Changing the password lifts the lockout.
+	_, err = testQueries.ChangePasswordTx(context.Background(), UpdateUserPasswordParams{
+		ID:             user.ID,
+		HashedPassword: util.RandomString(32),
+	})
+	require.NoError(t, err)
+
+	retryAt, err = testQueries.SigninRetryAt(context.Background(), retryArg)
+	require.NoError(t, err)
+	require.True(t, retryAt.IsZero())
+}
+
+func TestSigninIPLockout(t *testing.T) {
+	clientIP := randomClientIP()
+
+	policy := SigninPolicy{
+		MaxUserFailures: 100,
+		MaxIPFailures:   2,
+		LockoutDuration: time.Minute,
+		FailureWindow:   time.Hour,
+	}
+
+	for i := 0; i < 2; i++ {
+		audit, err := testQueries.RecordSigninAttemptTx(context.Background(), RecordSigninAttemptTxParams{
+			Identifier: util.RandomEmail(),
+			ClientIP:   clientIP + ":5000",
+			Outcome:    SigninBadCredentials,
+			Policy:     policy,
+		})
+		require.NoError(t, err)
+		require.False(t, audit.UserID.Valid)
+	}
+
+	// Counted per host whatever the port.
+	retryAt, err := testQueries.SigninRetryAt(context.Background(), SigninRetryAtParams{ClientIP: clientIP, Policy: policy})
+	require.NoError(t, err)
+	require.False(t, retryAt.IsZero())
+}
+
+func TestSigninSuccessClearsUserFailures(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	policy := SigninPolicy{
+		MaxUserFailures: 5,
+		MaxIPFailures:   100,
+		LockoutDuration: time.Minute,
+		BaseDelay:       time.Minute,
+		MaxDelay:        time.Hour,
+		FailureWindow:   time.Hour,
+	}
+
+	attempt := RecordSigninAttemptTxParams{
+		UserID:     user.ID,
+		Identifier: user.Username,
+		ClientIP:   randomClientIP(),
+		Outcome:    SigninBadCredentials,
+		Policy:     policy,
+	}
+
+	_, err := testQueries.RecordSigninAttemptTx(context.Background(), attempt)
+	require.NoError(t, err)
+
+	retryAt, err := testQueries.SigninRetryAt(context.Background(), SigninRetryAtParams{UserID: user.ID, ClientIP: randomClientIP(), Policy: policy})
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Minute), retryAt, 5*time.Second)
+
+	attempt.Outcome = SigninSucceeded
+	audit, err := testQueries.RecordSigninAttemptTx(context.Background(), attempt)
+	require.NoError(t, err)
+	require.True(t, audit.Success)
+
+	retryAt, err = testQueries.SigninRetryAt(context.Background(), SigninRetryAtParams{UserID: user.ID, ClientIP: randomClientIP(), Policy: policy})
+	require.NoError(t, err)
+	require.True(t, retryAt.IsZero())
+}
+
+func TestSigninPolicyDelay(t *testing.T) {
+	policy := SigninPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+
+	require.Zero(t, policy.delay(0))
+	require.Equal(t, time.Second, policy.delay(1))
+	require.Equal(t, 2*time.Second, policy.delay(2))
+	require.Equal(t, 8*time.Second, policy.delay(4))
+	require.Equal(t, 10*time.Second, policy.delay(5))
+	require.Equal(t, 10*time.Second, policy.delay(50))
+}
+
+func TestReserveSigninAttempt(t *testing.T) {
+	user, _ := createRandomUser(t)
+	clientIP := randomClientIP()
+
+	policy := SigninPolicy{
+		MaxUserFailures: 5,
+		MaxIPFailures:   100,
+		LockoutDuration: time.Minute,
+		BaseDelay:       time.Minute,
+		MaxDelay:        time.Hour,
+		FailureWindow:   time.Hour,
+	}
+	arg := SigninRetryAtParams{UserID: user.ID, ClientIP: clientIP, Policy: policy}
+
+	// Of parallel guesses only one gets through.
+	n := 5
+	results := make(chan time.Time, n)
+	errs := make(chan error, n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			retryAt, err := testQueries.ReserveSigninAttemptTx(context.Background(), arg)
+			errs <- err
+			results <- retryAt
+		}()
+	}
+
+	reserved := 0
+
+	for i := 0; i < n; i++ {
+		require.NoError(t, <-errs)
+
+		if (<-results).IsZero() {
+			reserved++
+		}
+	}
+
+	require.Equal(t, 1, reserved)
+
+	// Recording the attempt counts its failure once and releases it.
+	_, err := testQueries.RecordSigninAttemptTx(context.Background(), RecordSigninAttemptTxParams{
+		UserID:     user.ID,
+		Identifier: user.Email,
+		ClientIP:   clientIP,
+		Outcome:    SigninBadCredentials,
+		Policy:     policy,
+		Reserved:   true,
+	})
+	require.NoError(t, err)
+
+	throttles, err := testQueries.GetSigninThrottles(context.Background(), []string{SigninThrottleUserKey(user.ID)})
+	require.NoError(t, err)
+	require.Len(t, throttles, 1)
+	require.Equal(t, int32(1), throttles[0].Failures)
+	require.Zero(t, throttles[0].Reserved)
+}
+
+func TestReservedSigninAttemptReleased(t *testing.T) {
+	user, _ := createRandomUser(t)
+	clientIP := randomClientIP()
+
+	policy := SigninPolicy{
+		MaxUserFailures: 5,
+		MaxIPFailures:   100,
+		LockoutDuration: time.Minute,
+		BaseDelay:       time.Minute,
+		MaxDelay:        time.Hour,
+		FailureWindow:   time.Hour,
+	}
+	arg := SigninRetryAtParams{UserID: user.ID, ClientIP: clientIP, Policy: policy}
+
+	retryAt, err := testQueries.ReserveSigninAttemptTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, retryAt.IsZero())
+
+	_, err = testQueries.RecordSigninAttemptTx(context.Background(), RecordSigninAttemptTxParams{
+		UserID:     user.ID,
+		Identifier: user.Email,
+		ClientIP:   clientIP,
+		Outcome:    SigninChallenged,
+		Policy:     policy,
+		Reserved:   true,
+	})
+	require.NoError(t, err)
+
+	retryAt, err = testQueries.SigninRetryAt(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, retryAt.IsZero())
+}
+
+func TestSigninSuccessKeepsIPRetryAt(t *testing.T) {
+	attacker, _ := createRandomUser(t)
+	user, _ := createRandomUser(t)
+	clientIP := randomClientIP()
+
+	policy := SigninPolicy{
+		MaxUserFailures: 5,
+		MaxIPFailures:   100,
+		LockoutDuration: time.Minute,
+		BaseDelay:       500 * time.Millisecond,
+		MaxDelay:        time.Hour,
+		FailureWindow:   time.Hour,
+	}
+	ipArg := SigninRetryAtParams{ClientIP: clientIP, Policy: policy}
+
+	_, err := testQueries.RecordSigninAttemptTx(context.Background(), RecordSigninAttemptTxParams{
+		UserID:     attacker.ID,
+		Identifier: attacker.Username,
+		ClientIP:   clientIP,
+		Outcome:    SigninBadCredentials,
+		Policy:     policy,
+	})
+	require.NoError(t, err)
+
+	retryAt, err := testQueries.SigninRetryAt(context.Background(), ipArg)
+	require.NoError(t, err)
+	require.False(t, retryAt.IsZero())
+	time.Sleep(time.Until(retryAt))
+
+	before, err := testQueries.GetSigninThrottles(context.Background(), []string{SigninThrottleIPKey(clientIP)})
+	require.NoError(t, err)
+	require.Len(t, before, 1)
+
+	retryAt, err = testQueries.ReserveSigninAttemptTx(context.Background(), SigninRetryAtParams{UserID: user.ID, ClientIP: clientIP, Policy: policy})
+	require.NoError(t, err)
+	require.True(t, retryAt.IsZero())
+
+	_, err = testQueries.RecordSigninAttemptTx(context.Background(), RecordSigninAttemptTxParams{
+		UserID:     user.ID,
+		Identifier: user.Username,
+		ClientIP:   clientIP,
+		Outcome:    SigninSucceeded,
+		Policy:     policy,
+		Reserved:   true,
+	})
+	require.NoError(t, err)
+
+	// The ip's earlier failure is still counted from when it was made.
+	after, err := testQueries.GetSigninThrottles(context.Background(), []string{SigninThrottleIPKey(clientIP)})
+	require.NoError(t, err)
+	require.Len(t, after, 1)
+	require.Equal(t, before[0].Failures, after[0].Failures)
+	require.True(t, before[0].LastFailedAt.Time.Equal(after[0].LastFailedAt.Time))
+	require.Zero(t, after[0].Reserved)
+
+	retryAt, err = testQueries.SigninRetryAt(context.Background(), ipArg)
+	require.NoError(t, err)
+	require.True(t, retryAt.IsZero())
+}
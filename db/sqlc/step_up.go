@@ -0,0 +1,25 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/devphasex/cedar-bank-api/util"
+)
+
+// Actions a step-up challenge can be raised for.
+const (
+	StepUpActionTransfer     = "transfer"
+	StepUpActionTransferHold = "transfer_hold"
+)
+
+// MaxStepUpAttempts is how many failed re-authentications a step-up
+// challenge takes before it is spent.
+const MaxStepUpAttempts = 5
+
+// TransferStepUpParams hashes the parameters a transfer step-up token is
+// bound to, so the token cannot be spent on a different transfer.
+func TransferStepUpParams(fromAccountID, toAccountID int64, amount float64, currency string) string {
+	return util.HashSecretToken(fmt.Sprintf("%d:%d:%s:%s",
+		fromAccountID, toAccountID, strconv.FormatFloat(amount, 'f', -1, 64), currency))
+}
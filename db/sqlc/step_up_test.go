@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStepUpToken(t *testing.T) {
+	user, _ := createRandomUser(t)
+	paramsHash := TransferStepUpParams(1, 2, 15000, string(util.USD))
+
+	challenge, err := testQueries.CreateStepUpChallenge(context.Background(), CreateStepUpChallengeParams{
+		ID:         pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		UserID:     user.ID,
+		Action:     StepUpActionTransfer,
+		ParamsHash: paramsHash,
+		ExpiresAt:  pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	})
+	require.NoError(t, err)
+
+	token, tokenHash, err := util.NewSecretToken()
+	require.NoError(t, err)
+
+	issueArg := IssueStepUpTokenParams{
+		ID:          challenge.ID,
+		TokenHash:   tokenHash,
+		ExpiresAt:   pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+		MaxAttempts: MaxStepUpAttempts,
+	}
+
+	issued, err := testQueries.IssueStepUpToken(context.Background(), issueArg)
+	require.NoError(t, err)
+	require.True(t, issued.VerifiedAt.Valid)
+
+	// A challenge yields a single token.
+	_, err = testQueries.IssueStepUpToken(context.Background(), issueArg)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	consumeArg := ConsumeStepUpTokenParams{
+		TokenHash:  util.HashSecretToken(token),
+		UserID:     user.ID,
+		Action:     StepUpActionTransfer,
+		ParamsHash: TransferStepUpParams(1, 2, 15001, string(util.USD)),
+	}
+
+	// The token is bound to the transfer it was raised for.
+	_, err = testQueries.ConsumeStepUpToken(context.Background(), consumeArg)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	consumeArg.ParamsHash = paramsHash
+	_, err = testQueries.ConsumeStepUpToken(context.Background(), consumeArg)
+	require.NoError(t, err)
+
+	_, err = testQueries.ConsumeStepUpToken(context.Background(), consumeArg)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+}
+
+func TestTakeStepUpAttempt(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	challenge, err := testQueries.CreateStepUpChallenge(context.Background(), CreateStepUpChallengeParams{
+		ID:         pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		UserID:     user.ID,
+		Action:     StepUpActionTransfer,
+		ParamsHash: TransferStepUpParams(1, 2, 15000, string(util.USD)),
+		ExpiresAt:  pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	})
+	require.NoError(t, err)
+
+	takeArg := TakeStepUpAttemptParams{ID: challenge.ID, MaxAttempts: MaxStepUpAttempts}
+
+	// Parallel answers get no more than MaxStepUpAttempts between them.
+	n := 2 * MaxStepUpAttempts
+	errs := make(chan error, n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := testQueries.TakeStepUpAttempt(context.Background(), takeArg)
+			errs <- err
+		}()
+	}
+
+	taken := 0
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+
+		if err == nil {
+			taken++
+			continue
+		}
+
+		require.ErrorIs(t, err, pgx.ErrNoRows)
+	}
+
+	require.Equal(t, MaxStepUpAttempts, taken)
+
+	// The answer that took the last attempt may still be right.
+	_, tokenHash, err := util.NewSecretToken()
+	require.NoError(t, err)
+
+	_, err = testQueries.IssueStepUpToken(context.Background(), IssueStepUpTokenParams{
+		ID:          challenge.ID,
+		TokenHash:   tokenHash,
+		ExpiresAt:   pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+		MaxAttempts: MaxStepUpAttempts,
+	})
+	require.NoError(t, err)
+}
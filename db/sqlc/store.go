@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/jackc/pgx/v5"
@@ -14,6 +15,26 @@ import (
 type Store interface {
 	Querier
 	TransferTx(ctx context.Context, arg TransferTxParams) (*TransferTxResult, error)
+	QuoteTransferFee(ctx context.Context, currency string, amount float64) (*TransferFeeQuote, error)
+	ReverseTransferTx(ctx context.Context, arg ReverseTransferTxParams) (*ReverseTransferTxResult, error)
+	AuthorizeTransferTx(ctx context.Context, arg AuthorizeTransferTxParams) (*TransferHold, error)
+	CaptureTransferHoldTx(ctx context.Context, holdID int64) (*CaptureTransferHoldTxResult, error)
+	VoidTransferHoldTx(ctx context.Context, holdID int64) (*TransferHold, error)
+	ExpireTransferHoldsTx(ctx context.Context, limit int32) ([]TransferHold, error)
+	AccrueOverdraftTx(ctx context.Context, arg AccrueOverdraftTxParams) (*OverdraftAccrual, error)
+	AccrueInterestTx(ctx context.Context, arg AccrueInterestTxParams) (*InterestAccrual, error)
+	PostInterestTx(ctx context.Context, arg PostInterestTxParams) (*PostInterestTxResult, error)
+	VerifyEmailTx(ctx context.Context, tokenHash string) (*User, error)
+	ResendVerifyEmailTx(ctx context.Context, arg ResendVerifyEmailTxParams) (*VerifyEmail, error)
+	ChangePasswordTx(ctx context.Context, arg UpdateUserPasswordParams) (*User, error)
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (*User, error)
+	EnableTOTPTx(ctx context.Context, arg EnableTOTPTxParams) (*EnableTOTPTxResult, error)
+	DisableTOTPTx(ctx context.Context, arg DisableTOTPTxParams) (*User, error)
+	CompleteSigninChallengeTx(ctx context.Context, arg CompleteSigninChallengeTxParams) (*User, error)
+	VerifySecondFactorTx(ctx context.Context, arg VerifySecondFactorTxParams) error
+	SigninRetryAt(ctx context.Context, arg SigninRetryAtParams) (time.Time, error)
+	ReserveSigninAttemptTx(ctx context.Context, arg SigninRetryAtParams) (time.Time, error)
+	RecordSigninAttemptTx(ctx context.Context, arg RecordSigninAttemptTxParams) (*AuthAudit, error)
 }
 
 type PgStore struct {
@@ -61,109 +82,219 @@ type TransferTxResult struct {
 	ToAccount   Account  `json:"to_account"`
 	FromEntry   Entry    `json:"from_entry"`
 	ToEntry     Entry    `json:"to_entry"`
+	Fee         float64  `json:"fee"`
+	// FeeEntry debits the fee from the sender and RevenueEntry credits
+	// it to the currency's revenue account. Both are nil for free transfers.
+	FeeEntry     *Entry `json:"fee_entry,omitempty"`
+	RevenueEntry *Entry `json:"revenue_entry,omitempty"`
 }
 
-var ErrFundNotSufficient = util.NewCustomError("ErrFundNotSufficient", "insufficient funds for transfer")
-var ErrUnableUpdateAccount = util.NewCustomError("ErrUnableUpdateAccount", "failed to update both accounts")
-var ErrAccountNotFound = util.NewCustomError("ErrAccountNotFound", "account not found")
+var ErrFundNotSufficient = util.NewCustomError(util.KindPermissionDenied, "ErrFundNotSufficient", "insufficient funds for transfer")
+var ErrUnableUpdateAccount = util.NewCustomError(util.KindInternal, "ErrUnableUpdateAccount", "failed to update both accounts")
+var ErrAccountNotFound = util.NewCustomError(util.KindNotFound, "ErrAccountNotFound", "account not found")
 
-func (s *PgStore) TransferTx(ctx context.Context, arg TransferTxParams) (*TransferTxResult, error) {
-	var txResult TransferTxResult
-	err := s.execTx(ctx, func(q *Queries) error {
-		var err error
+func (s *PgStore) TransferTx(ctx context.Context, arg TransferTxParams) (_ *TransferTxResult, err error) {
+	ctx, span := startSpan(ctx, "TransferTx")
+	defer func() { endSpan(span, err) }()
 
-		fromAccount, err := s.GetAccountByIDForUpdate(ctx, arg.FromAccountID)
+	var txResult *TransferTxResult
+	err = s.execTx(ctx, func(q *Queries) error {
+		var err error
+		txResult, err = transferTx(ctx, q, arg, nil)
+		return err
+	})
 
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				return errors.Join(errors.New(
-					fmt.Sprintf("account with '%d' not found", arg.FromAccountID),
-				), ErrAccountNotFound)
-			}
+	if err != nil {
+		return nil, err
+	}
 
-			return err
-		}
+	return txResult, nil
+}
 
-		// Check if the sender has sufficient funds
-		senderBalance := fromAccount.Balance
-		if senderBalance < arg.Amount {
-			return ErrFundNotSufficient
-		}
+// transferTx runs a transfer inside an open transaction. A nil quote
+// charges the fee policy active right now. Each step is traced as a span.
+func transferTx(ctx context.Context, q *Queries, arg TransferTxParams, quote *TransferFeeQuote) (*TransferTxResult, error) {
+	var txResult TransferTxResult
+	var fromAccount Account
 
-		_, err = s.GetAccountByIDForUpdate(ctx, arg.ToAccountID)
+	err := traceStep(ctx, "TransferTx.lockAccounts", func(ctx context.Context) (err error) {
+		fromAccount, _, err = lockTransferAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+		return err
+	})
 
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				return errors.Join(errors.New(
-					fmt.Sprintf("account with '%d' not found", arg.ToAccountID),
-				), ErrAccountNotFound)
-			}
+	if err != nil {
+		return nil, err
+	}
 
+	if quote == nil {
+		err = traceStep(ctx, "TransferTx.quoteFee", func(ctx context.Context) (err error) {
+			quote, err = quoteTransferFee(ctx, q, fromAccount.Currency, arg.Amount)
 			return err
+		})
+
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Create transfer
-		transfer, err := q.CreateTransfer(ctx, CreateTransferParams{
+	// Check if the sender has sufficient funds to cover the fee as well.
+	// Funds reserved by pending holds are not spendable, an approved
+	// overdraft is.
+	if fromAccount.SpendableBalance() < quote.Total {
+		return nil, ErrFundNotSufficient
+	}
+
+	err = traceStep(ctx, "TransferTx.moveFunds", func(ctx context.Context) error {
+		return moveFunds(ctx, q, &txResult, CreateTransferParams{
 			FromAccountID: pgtype.Int8{Int64: arg.FromAccountID, Valid: true},
 			ToAccountID:   pgtype.Int8{Int64: arg.ToAccountID, Valid: true},
 			Amount:        arg.Amount,
+			Fee:           quote.Fee,
+			FeePolicyID:   pgtype.Int8{Int64: derefInt64(quote.FeePolicyID), Valid: quote.FeePolicyID != nil},
 		})
+	})
 
-		if err != nil {
-			return err
-		}
-		txResult.Transfer = transfer
+	if err != nil {
+		return nil, err
+	}
+	txResult.Fee = quote.Fee
 
-		// Create entries
-		txResult.FromEntry, err = q.CreateBalanceEntry(ctx, CreateBalanceEntryParams{
-			AccountID: pgtype.Int8{Int64: arg.FromAccountID, Valid: true},
-			Amount:    -arg.Amount,
+	if quote.Fee > 0 {
+		err = traceStep(ctx, "TransferTx.bookFee", func(ctx context.Context) error {
+			return bookTransferFee(ctx, q, &txResult, fromAccount, quote.Fee)
 		})
-		if err != nil {
-			return err
-		}
 
-		txResult.ToEntry, err = q.CreateBalanceEntry(ctx, CreateBalanceEntryParams{
-			AccountID: pgtype.Int8{Int64: arg.ToAccountID, Valid: true},
-			Amount:    arg.Amount,
-		})
 		if err != nil {
-			return err
+			return nil, err
 		}
+	}
 
-		updateTransferAccountBal := UpdateTransferAccountBalanceParams{
-			FromAccountID: arg.FromAccountID,
-			ToAccountID:   arg.ToAccountID,
-			Amount:        arg.Amount,
-		}
-		// Update both account balances in a single query
+	err = traceStep(ctx, "TransferTx.fetchAccounts", func(ctx context.Context) error {
+		return fetchTransferAccounts(ctx, q, &txResult)
+	})
 
-		result, err := q.UpdateTransferAccountBalance(ctx, updateTransferAccountBal)
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return nil, err
+	}
 
-		if result.RowsAffected() != 2 {
-			return errors.New("failed to update both accounts")
-		}
+	return &txResult, nil
+}
+
+// lockTransferAccounts takes row locks on both accounts of a transfer.
+// Locks are always taken in ascending id order so that concurrent
+// transfers in opposite directions cannot deadlock.
+func lockTransferAccounts(ctx context.Context, q *Queries, fromAccountID, toAccountID int64) (fromAccount, toAccount Account, err error) {
+	lock := func(id int64) (Account, error) {
+		account, err := q.GetAccountByIDForUpdate(ctx, id)
 
-		// Fetch updated accounts
-		txResult.FromAccount, err = q.GetAccountByID(ctx, arg.FromAccountID)
 		if err != nil {
-			return err
+			if errors.Is(err, pgx.ErrNoRows) {
+				return account, errors.Join(errors.New(
+					fmt.Sprintf("account with '%d' not found", id),
+				), ErrAccountNotFound)
+			}
+
+			return account, err
 		}
 
-		txResult.ToAccount, err = q.GetAccountByID(ctx, arg.ToAccountID)
-		if err != nil {
-			return err
+		return account, nil
+	}
+
+	if fromAccountID < toAccountID {
+		if fromAccount, err = lock(fromAccountID); err != nil {
+			return
 		}
+		toAccount, err = lock(toAccountID)
+		return
+	}
 
-		return nil
+	if toAccount, err = lock(toAccountID); err != nil {
+		return
+	}
+	fromAccount, err = lock(fromAccountID)
+	return
+}
+
+// moveFunds records the transfer, books the matching debit and credit
+// entries and updates both balances. Callers must hold the account locks.
+func moveFunds(ctx context.Context, q *Queries, txResult *TransferTxResult, arg CreateTransferParams) error {
+	transfer, err := q.CreateTransfer(ctx, arg)
+
+	if err != nil {
+		return err
+	}
+	txResult.Transfer = transfer
+
+	// Create entries
+	txResult.FromEntry, err = q.CreateBalanceEntry(ctx, CreateBalanceEntryParams{
+		AccountID:  arg.FromAccountID,
+		Amount:     -arg.Amount,
+		TransferID: pgtype.Int8{Int64: transfer.ID, Valid: true},
+		Kind:       EntryKindTransfer,
 	})
+	if err != nil {
+		return err
+	}
 
+	txResult.ToEntry, err = q.CreateBalanceEntry(ctx, CreateBalanceEntryParams{
+		AccountID:  arg.ToAccountID,
+		Amount:     arg.Amount,
+		TransferID: pgtype.Int8{Int64: transfer.ID, Valid: true},
+		Kind:       EntryKindTransfer,
+	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return &txResult, nil
+	// Update both account balances in a single query
+	result, err := q.UpdateTransferAccountBalance(ctx, UpdateTransferAccountBalanceParams{
+		FromAccountID: arg.FromAccountID.Int64,
+		ToAccountID:   arg.ToAccountID.Int64,
+		Amount:        arg.Amount,
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() != 2 {
+		return ErrUnableUpdateAccount
+	}
+
+	return nil
+}
+
+// fetchTransferAccounts loads the post-transfer state of both accounts.
+func fetchTransferAccounts(ctx context.Context, q *Queries, txResult *TransferTxResult) error {
+	var err error
+
+	txResult.FromAccount, err = q.GetAccountByID(ctx, txResult.Transfer.FromAccountID.Int64)
+	if err != nil {
+		return err
+	}
+
+	txResult.ToAccount, err = q.GetAccountByID(ctx, txResult.Transfer.ToAccountID.Int64)
+	return err
+}
+
+// bookTransferFee debits the fee from the sender and credits it to the
+// revenue account of the transfer currency.
+func bookTransferFee(ctx context.Context, q *Queries, txResult *TransferTxResult, fromAccount Account, fee float64) error {
+	transferID := pgtype.Int8{Int64: txResult.Transfer.ID, Valid: true}
+	feeEntry, revenueEntry, err := postSystemEntries(ctx, q, fromAccount, SystemPurposeFeeRevenue, -fee, transferID)
+
+	if err != nil {
+		return err
+	}
+
+	txResult.FeeEntry = &feeEntry
+	txResult.RevenueEntry = &revenueEntry
+	return nil
+}
+
+func derefInt64(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+
+	return *v
 }
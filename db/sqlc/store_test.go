@@ -6,6 +6,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/devphasex/cedar-bank-api/fee"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -171,3 +174,64 @@ func TestFundTransferDeadlock(t *testing.T) {
 	require.Equal(t, account1.Balance, updatedAccount1.Balance)
 	require.Equal(t, account2.Balance, updatedAccount2.Balance)
 }
+
+func TestFundTransferWithFee(t *testing.T) {
+	store := testQueries
+
+	account1 := createRandomAccount(t)
+	user2, _ := createRandomUser(t)
+	account2, err := store.CreateAccount(context.Background(), CreateAccountParams{
+		OwnerID:  user2.ID,
+		Balance:  float64(util.RandomMoney()),
+		Currency: account1.Currency,
+		Type:     util.CheckingAccount,
+	})
+	require.NoError(t, err)
+
+	policy, err := store.CreateFeePolicy(context.Background(), CreateFeePolicyParams{
+		Currency:   pgtype.Text{String: account1.Currency, Valid: true},
+		Kind:       string(fee.Flat),
+		FlatAmount: 1.5,
+		Tiers:      []byte("[]"),
+		EffectiveFrom: pgtype.Timestamptz{
+			Time:  time.Now().Add(-time.Minute),
+			Valid: true,
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, err := store.DeactivateFeePolicy(context.Background(), policy.ID)
+		require.NoError(t, err)
+	})
+
+	account1, err = store.UpdateBalance(context.Background(), UpdateBalanceParams{ID: account1.ID, Balance: 100})
+	require.NoError(t, err)
+
+	var amount float64 = 10
+
+	quote, err := store.QuoteTransferFee(context.Background(), account1.Currency, amount)
+	require.NoError(t, err)
+	require.Equal(t, 1.5, quote.Fee)
+	require.Equal(t, amount+1.5, quote.Total)
+
+	result, err := store.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        amount,
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 1.5, result.Fee)
+	require.Equal(t, 1.5, result.Transfer.Fee)
+	require.Equal(t, policy.ID, result.Transfer.FeePolicyID.Int64)
+
+	require.NotNil(t, result.FeeEntry)
+	require.Equal(t, account1.ID, result.FeeEntry.AccountID.Int64)
+	require.Equal(t, -1.5, result.FeeEntry.Amount)
+
+	require.NotNil(t, result.RevenueEntry)
+	require.Equal(t, 1.5, result.RevenueEntry.Amount)
+
+	require.Equal(t, account1.Balance-amount-1.5, result.FromAccount.Balance)
+	require.Equal(t, account2.Balance+amount, result.ToAccount.Balance)
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// System account purposes. Each purpose owns one account per currency.
+const (
+	SystemPurposeFeeRevenue       = "fee_revenue"
+	SystemPurposeOverdraftRevenue = "overdraft_revenue"
+	SystemPurposeInterestExpense  = "interest_expense"
+)
+
+// EntryKindTransfer marks the two principal legs of a transfer. Entries
+// posted against a system account carry its purpose as their kind.
+const EntryKindTransfer = "transfer"
+
+var ErrSystemAccountNotFound = util.NewCustomError(util.KindInternal, "ErrSystemAccountNotFound", "system account not configured for currency")
+
+// postSystemEntries books amount against account with the opposite leg on
+// the system account for purpose. A negative amount charges the account,
+// a positive one pays into it. transferID links fee entries to the transfer
+// they were charged for.
+func postSystemEntries(ctx context.Context, q *Queries, account Account, purpose string, amount float64, transferID pgtype.Int8) (accountEntry, systemEntry Entry, err error) {
+	system, err := q.GetSystemAccount(ctx, GetSystemAccountParams{
+		Purpose:  purpose,
+		Currency: account.Currency,
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = errors.Join(fmt.Errorf("no %s account for %s", purpose, account.Currency), ErrSystemAccountNotFound)
+		}
+
+		return
+	}
+
+	accountEntry, err = q.CreateBalanceEntry(ctx, CreateBalanceEntryParams{
+		AccountID:  pgtype.Int8{Int64: account.ID, Valid: true},
+		Amount:     amount,
+		TransferID: transferID,
+		Kind:       purpose,
+	})
+	if err != nil {
+		return
+	}
+
+	systemEntry, err = q.CreateBalanceEntry(ctx, CreateBalanceEntryParams{
+		AccountID:  pgtype.Int8{Int64: system.AccountID, Valid: true},
+		Amount:     -amount,
+		TransferID: transferID,
+		Kind:       purpose,
+	})
+	if err != nil {
+		return
+	}
+
+	if _, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{ID: account.ID, Amount: amount}); err != nil {
+		return
+	}
+
+	_, err = q.AddAccountBalance(ctx, AddAccountBalanceParams{ID: system.AccountID, Amount: -amount})
+	return
+}
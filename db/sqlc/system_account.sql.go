@@ -0,0 +1,33 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: system_account.sql
+
+package db
+
+import (
+	"context"
+)
+
+const getSystemAccount = `-- name: GetSystemAccount :one
+SELECT purpose, currency, account_id, created_at FROM system_accounts
+WHERE purpose = $1 AND currency = $2
+LIMIT 1
+`
+
+type GetSystemAccountParams struct {
+	Purpose  string `json:"purpose"`
+	Currency string `json:"currency"`
+}
+
+func (q *Queries) GetSystemAccount(ctx context.Context, arg GetSystemAccountParams) (SystemAccount, error) {
+	row := q.db.QueryRow(ctx, getSystemAccount, arg.Purpose, arg.Currency)
+	var i SystemAccount
+	err := row.Scan(
+		&i.Purpose,
+		&i.Currency,
+		&i.AccountID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/devphasex/cedar-bank-api/db")
+
+// startSpan starts a span for a transaction or one of its steps. Queries
+// run with the returned context appear as its children.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name)
+}
+
+// endSpan records err, if any, on span and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// traceStep runs step in a span of its own.
+func traceStep(ctx context.Context, name string, step func(ctx context.Context) error) error {
+	ctx, span := startSpan(ctx, name)
+	err := step(ctx)
+	endSpan(span, err)
+
+	return err
+}
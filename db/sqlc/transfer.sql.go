@@ -11,20 +11,105 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addTransferReversedAmount = `-- name: AddTransferReversedAmount :one
+UPDATE transfer
+SET reversed_amount = reversed_amount + $1
+WHERE id = $2
+RETURNING id, from_account_id, to_account_id, amount, created_at, fee, fee_policy_id, reversed_amount, original_transfer_id
+`
+
+type AddTransferReversedAmountParams struct {
+	Amount float64 `json:"amount"`
+	ID     int64   `json:"id"`
+}
+
+func (q *Queries) AddTransferReversedAmount(ctx context.Context, arg AddTransferReversedAmountParams) (Transfer, error) {
+	row := q.db.QueryRow(ctx, addTransferReversedAmount, arg.Amount, arg.ID)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Fee,
+		&i.FeePolicyID,
+		&i.ReversedAmount,
+		&i.OriginalTransferID,
+	)
+	return i, err
+}
+
 const createTransfer = `-- name: CreateTransfer :one
-INSERT INTO transfer(from_account_id, to_account_id, amount)
-VALUES ($1, $2, $3)
-RETURNING  id, from_account_id, to_account_id, amount, created_at
+INSERT INTO transfer(from_account_id, to_account_id, amount, fee, fee_policy_id, original_transfer_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING  id, from_account_id, to_account_id, amount, created_at, fee, fee_policy_id, reversed_amount, original_transfer_id
 `
 
 type CreateTransferParams struct {
-	FromAccountID pgtype.Int8 `json:"from_account_id"`
-	ToAccountID   pgtype.Int8 `json:"to_account_id"`
-	Amount        float64     `json:"amount"`
+	FromAccountID      pgtype.Int8 `json:"from_account_id"`
+	ToAccountID        pgtype.Int8 `json:"to_account_id"`
+	Amount             float64     `json:"amount"`
+	Fee                float64     `json:"fee"`
+	FeePolicyID        pgtype.Int8 `json:"fee_policy_id"`
+	OriginalTransferID pgtype.Int8 `json:"original_transfer_id"`
 }
 
 func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transfer, error) {
-	row := q.db.QueryRow(ctx, createTransfer, arg.FromAccountID, arg.ToAccountID, arg.Amount)
+	row := q.db.QueryRow(ctx, createTransfer,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Fee,
+		arg.FeePolicyID,
+		arg.OriginalTransferID,
+	)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Fee,
+		&i.FeePolicyID,
+		&i.ReversedAmount,
+		&i.OriginalTransferID,
+	)
+	return i, err
+}
+
+const getTransfer = `-- name: GetTransfer :one
+SELECT id, from_account_id, to_account_id, amount, created_at, fee, fee_policy_id, reversed_amount, original_transfer_id FROM transfer
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetTransfer(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRow(ctx, getTransfer, id)
+	var i Transfer
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.CreatedAt,
+		&i.Fee,
+		&i.FeePolicyID,
+		&i.ReversedAmount,
+		&i.OriginalTransferID,
+	)
+	return i, err
+}
+
+const getTransferForUpdate = `-- name: GetTransferForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, created_at, fee, fee_policy_id, reversed_amount, original_transfer_id FROM transfer
+WHERE id = $1
+LIMIT 1 FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferForUpdate(ctx context.Context, id int64) (Transfer, error) {
+	row := q.db.QueryRow(ctx, getTransferForUpdate, id)
 	var i Transfer
 	err := row.Scan(
 		&i.ID,
@@ -32,6 +117,10 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ToAccountID,
 		&i.Amount,
 		&i.CreatedAt,
+		&i.Fee,
+		&i.FeePolicyID,
+		&i.ReversedAmount,
+		&i.OriginalTransferID,
 	)
 	return i, err
 }
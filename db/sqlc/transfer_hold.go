@@ -0,0 +1,262 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	HoldStatusPending  = "pending"
+	HoldStatusCaptured = "captured"
+	HoldStatusVoided   = "voided"
+	HoldStatusExpired  = "expired"
+)
+
+var ErrHoldNotFound = util.NewCustomError(util.KindNotFound, "ErrHoldNotFound", "transfer hold not found")
+var ErrHoldNotPending = util.NewCustomError(util.KindConflict, "ErrHoldNotPending", "transfer hold is no longer pending")
+var ErrHoldExpired = util.NewCustomError(util.KindConflict, "ErrHoldExpired", "transfer hold has expired")
+
+type AuthorizeTransferTxParams struct {
+	FromAccountID int64         `json:"from_account_id"`
+	ToAccountID   int64         `json:"to_account_id"`
+	Amount        float64       `json:"amount"`
+	TTL           time.Duration `json:"ttl"`
+}
+
+type CaptureTransferHoldTxResult struct {
+	TransferTxResult
+	Hold TransferHold `json:"hold"`
+}
+
+// AuthorizeTransferTx reserves amount plus the quoted fee on the source
+// account. The reservation lowers the available balance but leaves the
+// ledger balance untouched until the hold is captured.
+func (s *PgStore) AuthorizeTransferTx(ctx context.Context, arg AuthorizeTransferTxParams) (*TransferHold, error) {
+	var hold TransferHold
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		fromAccount, _, err := lockTransferAccounts(ctx, q, arg.FromAccountID, arg.ToAccountID)
+
+		if err != nil {
+			return err
+		}
+
+		quote, err := quoteTransferFee(ctx, q, fromAccount.Currency, arg.Amount)
+
+		if err != nil {
+			return err
+		}
+
+		if fromAccount.SpendableBalance() < quote.Total {
+			return ErrFundNotSufficient
+		}
+
+		hold, err = q.CreateTransferHold(ctx, CreateTransferHoldParams{
+			FromAccountID: arg.FromAccountID,
+			ToAccountID:   arg.ToAccountID,
+			Amount:        arg.Amount,
+			Fee:           quote.Fee,
+			FeePolicyID:   pgtype.Int8{Int64: derefInt64(quote.FeePolicyID), Valid: quote.FeePolicyID != nil},
+			ExpiresAt: pgtype.Timestamptz{
+				Time:  time.Now().Add(arg.TTL),
+				Valid: true,
+			},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		_, err = q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+			ID:     arg.FromAccountID,
+			Amount: quote.Total,
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// CaptureTransferHoldTx settles a pending hold into a real transfer,
+// charging the fee quoted when the hold was authorized.
+func (s *PgStore) CaptureTransferHoldTx(ctx context.Context, holdID int64) (*CaptureTransferHoldTxResult, error) {
+	var txResult CaptureTransferHoldTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		hold, err := lockPendingHold(ctx, q, holdID)
+
+		if err != nil {
+			return err
+		}
+
+		if time.Now().After(hold.ExpiresAt.Time) {
+			return ErrHoldExpired
+		}
+
+		if _, _, err = lockTransferAccounts(ctx, q, hold.FromAccountID, hold.ToAccountID); err != nil {
+			return err
+		}
+
+		if err = releaseHold(ctx, q, hold); err != nil {
+			return err
+		}
+
+		quote := &TransferFeeQuote{
+			Amount: hold.Amount,
+			Fee:    hold.Fee,
+			Total:  hold.Amount + hold.Fee,
+		}
+
+		if hold.FeePolicyID.Valid {
+			quote.FeePolicyID = &hold.FeePolicyID.Int64
+		}
+
+		result, err := transferTx(ctx, q, TransferTxParams{
+			FromAccountID: hold.FromAccountID,
+			ToAccountID:   hold.ToAccountID,
+			Amount:        hold.Amount,
+		}, quote)
+
+		if err != nil {
+			return err
+		}
+		txResult.TransferTxResult = *result
+
+		txResult.Hold, err = q.UpdateTransferHoldStatus(ctx, UpdateTransferHoldStatusParams{
+			ID:         hold.ID,
+			Status:     HoldStatusCaptured,
+			TransferID: pgtype.Int8{Int64: result.Transfer.ID, Valid: true},
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &txResult, nil
+}
+
+// VoidTransferHoldTx cancels a pending hold and frees the reserved funds.
+func (s *PgStore) VoidTransferHoldTx(ctx context.Context, holdID int64) (*TransferHold, error) {
+	var hold TransferHold
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		pending, err := lockPendingHold(ctx, q, holdID)
+
+		if err != nil {
+			return err
+		}
+
+		if err = releaseHold(ctx, q, pending); err != nil {
+			return err
+		}
+
+		hold, err = q.UpdateTransferHoldStatus(ctx, UpdateTransferHoldStatusParams{
+			ID:     pending.ID,
+			Status: HoldStatusVoided,
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// ExpireTransferHoldsTx releases up to limit pending holds whose TTL has
+// passed. Holds locked by a concurrent capture or void are skipped.
+func (s *PgStore) ExpireTransferHoldsTx(ctx context.Context, limit int32) ([]TransferHold, error) {
+	var expired []TransferHold
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		holds, err := q.GetExpiredTransferHolds(ctx, limit)
+
+		if err != nil {
+			return err
+		}
+
+		// Lock the source accounts in ascending id order, as transfers do,
+		// before touching any held balance.
+		accountIDs := make([]int64, 0, len(holds))
+		for _, hold := range holds {
+			accountIDs = append(accountIDs, hold.FromAccountID)
+		}
+		slices.Sort(accountIDs)
+
+		for _, id := range slices.Compact(accountIDs) {
+			if _, err = q.GetAccountByIDForUpdate(ctx, id); err != nil {
+				return err
+			}
+		}
+
+		for _, hold := range holds {
+			if err = releaseHold(ctx, q, hold); err != nil {
+				return err
+			}
+
+			hold, err = q.UpdateTransferHoldStatus(ctx, UpdateTransferHoldStatusParams{
+				ID:     hold.ID,
+				Status: HoldStatusExpired,
+			})
+
+			if err != nil {
+				return err
+			}
+
+			expired = append(expired, hold)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return expired, nil
+}
+
+func lockPendingHold(ctx context.Context, q *Queries, holdID int64) (TransferHold, error) {
+	hold, err := q.GetTransferHoldForUpdate(ctx, holdID)
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return hold, errors.Join(fmt.Errorf("transfer hold with '%d' not found", holdID), ErrHoldNotFound)
+		}
+
+		return hold, err
+	}
+
+	if hold.Status != HoldStatusPending {
+		return hold, ErrHoldNotPending
+	}
+
+	return hold, nil
+}
+
+// releaseHold gives the reserved amount back to the available balance.
+func releaseHold(ctx context.Context, q *Queries, hold TransferHold) error {
+	_, err := q.AddAccountHeldBalance(ctx, AddAccountHeldBalanceParams{
+		ID:     hold.FromAccountID,
+		Amount: -(hold.Amount + hold.Fee),
+	})
+
+	return err
+}
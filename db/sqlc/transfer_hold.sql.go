@@ -0,0 +1,177 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: transfer_hold.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createTransferHold = `-- name: CreateTransferHold :one
+INSERT INTO transfer_holds(from_account_id, to_account_id, amount, fee, fee_policy_id, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, from_account_id, to_account_id, amount, fee, fee_policy_id, status, transfer_id, expires_at, created_at, updated_at
+`
+
+type CreateTransferHoldParams struct {
+	FromAccountID int64              `json:"from_account_id"`
+	ToAccountID   int64              `json:"to_account_id"`
+	Amount        float64            `json:"amount"`
+	Fee           float64            `json:"fee"`
+	FeePolicyID   pgtype.Int8        `json:"fee_policy_id"`
+	ExpiresAt     pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateTransferHold(ctx context.Context, arg CreateTransferHoldParams) (TransferHold, error) {
+	row := q.db.QueryRow(ctx, createTransferHold,
+		arg.FromAccountID,
+		arg.ToAccountID,
+		arg.Amount,
+		arg.Fee,
+		arg.FeePolicyID,
+		arg.ExpiresAt,
+	)
+	var i TransferHold
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Fee,
+		&i.FeePolicyID,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getExpiredTransferHolds = `-- name: GetExpiredTransferHolds :many
+SELECT id, from_account_id, to_account_id, amount, fee, fee_policy_id, status, transfer_id, expires_at, created_at, updated_at FROM transfer_holds
+WHERE status = 'pending' AND expires_at <= now()
+ORDER BY expires_at
+LIMIT $1
+FOR NO KEY UPDATE SKIP LOCKED
+`
+
+func (q *Queries) GetExpiredTransferHolds(ctx context.Context, limit int32) ([]TransferHold, error) {
+	rows, err := q.db.Query(ctx, getExpiredTransferHolds, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TransferHold{}
+	for rows.Next() {
+		var i TransferHold
+		if err := rows.Scan(
+			&i.ID,
+			&i.FromAccountID,
+			&i.ToAccountID,
+			&i.Amount,
+			&i.Fee,
+			&i.FeePolicyID,
+			&i.Status,
+			&i.TransferID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTransferHold = `-- name: GetTransferHold :one
+SELECT id, from_account_id, to_account_id, amount, fee, fee_policy_id, status, transfer_id, expires_at, created_at, updated_at FROM transfer_holds
+WHERE id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetTransferHold(ctx context.Context, id int64) (TransferHold, error) {
+	row := q.db.QueryRow(ctx, getTransferHold, id)
+	var i TransferHold
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Fee,
+		&i.FeePolicyID,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getTransferHoldForUpdate = `-- name: GetTransferHoldForUpdate :one
+SELECT id, from_account_id, to_account_id, amount, fee, fee_policy_id, status, transfer_id, expires_at, created_at, updated_at FROM transfer_holds
+WHERE id = $1
+LIMIT 1 FOR NO KEY UPDATE
+`
+
+func (q *Queries) GetTransferHoldForUpdate(ctx context.Context, id int64) (TransferHold, error) {
+	row := q.db.QueryRow(ctx, getTransferHoldForUpdate, id)
+	var i TransferHold
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Fee,
+		&i.FeePolicyID,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateTransferHoldStatus = `-- name: UpdateTransferHoldStatus :one
+UPDATE transfer_holds
+SET status = $1,
+    transfer_id = $2,
+    updated_at = now()
+WHERE id = $3
+RETURNING id, from_account_id, to_account_id, amount, fee, fee_policy_id, status, transfer_id, expires_at, created_at, updated_at
+`
+
+type UpdateTransferHoldStatusParams struct {
+	Status     string      `json:"status"`
+	TransferID pgtype.Int8 `json:"transfer_id"`
+	ID         int64       `json:"id"`
+}
+
+func (q *Queries) UpdateTransferHoldStatus(ctx context.Context, arg UpdateTransferHoldStatusParams) (TransferHold, error) {
+	row := q.db.QueryRow(ctx, updateTransferHoldStatus, arg.Status, arg.TransferID, arg.ID)
+	var i TransferHold
+	err := row.Scan(
+		&i.ID,
+		&i.FromAccountID,
+		&i.ToAccountID,
+		&i.Amount,
+		&i.Fee,
+		&i.FeePolicyID,
+		&i.Status,
+		&i.TransferID,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
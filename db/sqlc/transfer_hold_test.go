@@ -0,0 +1,167 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomAccountPair(t *testing.T, balance float64) (Account, Account) {
+	account1 := createRandomAccount(t)
+	user2, _ := createRandomUser(t)
+
+	account2, err := testQueries.CreateAccount(context.Background(), CreateAccountParams{
+		OwnerID:  user2.ID,
+		Currency: account1.Currency,
+		Type:     util.CheckingAccount,
+	})
+	require.NoError(t, err)
+
+	account1, err = testQueries.UpdateBalance(context.Background(), UpdateBalanceParams{ID: account1.ID, Balance: balance})
+	require.NoError(t, err)
+
+	return account1, account2
+}
+
+func TestAuthorizeAndCaptureTransfer(t *testing.T) {
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	hold, err := testQueries.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        30,
+		TTL:           time.Minute,
+	})
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusPending, hold.Status)
+
+	held, err := testQueries.GetAccountByID(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, 100.0, held.Balance)
+	require.Equal(t, 70.0, held.AvailableBalance)
+
+	// The held funds cannot be spent by a regular transfer.
+	_, err = testQueries.TransferTx(context.Background(), TransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        80,
+	})
+	require.ErrorIs(t, err, ErrFundNotSufficient)
+
+	result, err := testQueries.CaptureTransferHoldTx(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusCaptured, result.Hold.Status)
+	require.Equal(t, result.Transfer.ID, result.Hold.TransferID.Int64)
+	require.Equal(t, 70.0, result.FromAccount.Balance)
+	require.Equal(t, 70.0, result.FromAccount.AvailableBalance)
+	require.Equal(t, 30.0, result.ToAccount.Balance)
+
+	_, err = testQueries.CaptureTransferHoldTx(context.Background(), hold.ID)
+	require.ErrorIs(t, err, ErrHoldNotPending)
+}
+
+func TestVoidTransferHold(t *testing.T) {
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	hold, err := testQueries.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        30,
+		TTL:           time.Minute,
+	})
+	require.NoError(t, err)
+
+	voided, err := testQueries.VoidTransferHoldTx(context.Background(), hold.ID)
+	require.NoError(t, err)
+	require.Equal(t, HoldStatusVoided, voided.Status)
+
+	account, err := testQueries.GetAccountByID(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, 100.0, account.Balance)
+	require.Equal(t, 100.0, account.AvailableBalance)
+}
+
+func TestExpireTransferHolds(t *testing.T) {
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	hold, err := testQueries.AuthorizeTransferTx(context.Background(), AuthorizeTransferTxParams{
+		FromAccountID: account1.ID,
+		ToAccountID:   account2.ID,
+		Amount:        30,
+		TTL:           -time.Second,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CaptureTransferHoldTx(context.Background(), hold.ID)
+	require.ErrorIs(t, err, ErrHoldExpired)
+
+	expired, err := testQueries.ExpireTransferHoldsTx(context.Background(), 1000)
+	require.NoError(t, err)
+
+	var found bool
+	for _, h := range expired {
+		if h.ID == hold.ID {
+			found = true
+			require.Equal(t, HoldStatusExpired, h.Status)
+		}
+	}
+	require.True(t, found)
+
+	account, err := testQueries.GetAccountByID(context.Background(), account1.ID)
+	require.NoError(t, err)
+	require.Equal(t, 100.0, account.AvailableBalance)
+}
+
+func TestExpireTransferHoldsDeadlock(t *testing.T) {
+	account1, account2 := createRandomAccountPair(t, 100)
+
+	// The hold on the higher account id expires first, so expiring in
+	// expires_at order would lock the accounts against the transfer order.
+	for _, arg := range []AuthorizeTransferTxParams{
+		{FromAccountID: max(account1.ID, account2.ID), ToAccountID: min(account1.ID, account2.ID), Amount: 10, TTL: -2 * time.Second},
+		{FromAccountID: min(account1.ID, account2.ID), ToAccountID: max(account1.ID, account2.ID), Amount: 10, TTL: -time.Second},
+	} {
+		_, err := testQueries.AuthorizeTransferTx(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	n := 10
+	errs := make(chan error, n)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			if i%2 == 0 {
+				_, err := testQueries.ExpireTransferHoldsTx(ctx, 1000)
+				errs <- err
+				return
+			}
+
+			_, err := testQueries.TransferTx(ctx, TransferTxParams{
+				FromAccountID: account1.ID,
+				ToAccountID:   account2.ID,
+				Amount:        1,
+			})
+			errs <- err
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,79 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: transfer_reversal.sql
+
+package db
+
+import (
+	"context"
+)
+
+const createTransferReversal = `-- name: CreateTransferReversal :one
+INSERT INTO transfer_reversals(transfer_id, reversal_transfer_id, amount, reason, reversed_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, transfer_id, reversal_transfer_id, amount, reason, reversed_by, created_at
+`
+
+type CreateTransferReversalParams struct {
+	TransferID         int64   `json:"transfer_id"`
+	ReversalTransferID int64   `json:"reversal_transfer_id"`
+	Amount             float64 `json:"amount"`
+	Reason             string  `json:"reason"`
+	ReversedBy         int64   `json:"reversed_by"`
+}
+
+func (q *Queries) CreateTransferReversal(ctx context.Context, arg CreateTransferReversalParams) (TransferReversal, error) {
+	row := q.db.QueryRow(ctx, createTransferReversal,
+		arg.TransferID,
+		arg.ReversalTransferID,
+		arg.Amount,
+		arg.Reason,
+		arg.ReversedBy,
+	)
+	var i TransferReversal
+	err := row.Scan(
+		&i.ID,
+		&i.TransferID,
+		&i.ReversalTransferID,
+		&i.Amount,
+		&i.Reason,
+		&i.ReversedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getTransferReversals = `-- name: GetTransferReversals :many
+SELECT id, transfer_id, reversal_transfer_id, amount, reason, reversed_by, created_at FROM transfer_reversals
+WHERE transfer_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) GetTransferReversals(ctx context.Context, transferID int64) ([]TransferReversal, error) {
+	rows, err := q.db.Query(ctx, getTransferReversals, transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []TransferReversal{}
+	for rows.Next() {
+		var i TransferReversal
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransferID,
+			&i.ReversalTransferID,
+			&i.Amount,
+			&i.Reason,
+			&i.ReversedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
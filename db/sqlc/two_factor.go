@@ -0,0 +1,328 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/totp"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrTwoFactorCodeInvalid = util.NewCustomError(util.KindInvalidArgument, "ErrTwoFactorCodeInvalid", "two-factor code is invalid")
+var ErrTwoFactorNotEnrolled = util.NewCustomError(util.KindConflict, "ErrTwoFactorNotEnrolled", "two-factor authentication enrollment not started")
+var ErrTwoFactorEnabled = util.NewCustomError(util.KindConflict, "ErrTwoFactorEnabled", "two-factor authentication is already enabled")
+var ErrTwoFactorDisabled = util.NewCustomError(util.KindConflict, "ErrTwoFactorDisabled", "two-factor authentication is not enabled")
+var ErrChallengeInvalid = util.NewCustomError(util.KindUnauthenticated, "ErrChallengeInvalid", "sign-in challenge is invalid or already used")
+var ErrChallengeExpired = util.NewCustomError(util.KindUnauthenticated, "ErrChallengeExpired", "sign-in challenge has expired, sign in again")
+
+// ErrChallengeCodeInvalid is ErrTwoFactorCodeInvalid on the second step of
+// a sign-in, where a wrong code fails authentication rather than a request
+// of a signed in user.
+var ErrChallengeCodeInvalid = util.NewCustomError(util.KindUnauthenticated, "ErrChallengeCodeInvalid", "two-factor code is invalid")
+
+// MaxSigninChallengeAttempts is how many wrong codes a sign-in challenge
+// takes before it is spent and the password has to be entered again.
+const MaxSigninChallengeAttempts = 5
+
+type EnableTOTPTxParams struct {
+	UserID int64  `json:"user_id"`
+	Code   string `json:"code"`
+}
+
+type EnableTOTPTxResult struct {
+	User          User     `json:"user"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// EnableTOTPTx turns two-factor authentication on once the user proves,
+// with a code from their authenticator, that enrollment worked. It
+// replaces any previous recovery codes; the new ones are only ever
+// returned here.
+func (s *PgStore) EnableTOTPTx(ctx context.Context, arg EnableTOTPTxParams) (*EnableTOTPTxResult, error) {
+	var result EnableTOTPTxResult
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		user, err := getUserByID(ctx, q, arg.UserID)
+
+		if err != nil {
+			return err
+		}
+
+		if user.IsTotpEnabled {
+			return ErrTwoFactorEnabled
+		}
+
+		if !user.TotpSecret.Valid {
+			return ErrTwoFactorNotEnrolled
+		}
+
+		step, ok := totp.Validate(user.TotpSecret.String, arg.Code, time.Now())
+
+		if !ok {
+			return ErrTwoFactorCodeInvalid
+		}
+
+		if _, err = q.AdvanceUserTOTPStep(ctx, AdvanceUserTOTPStepParams{ID: user.ID, Step: step}); err != nil {
+			return err
+		}
+
+		if result.User, err = q.EnableUserTOTP(ctx, user.ID); err != nil {
+			return err
+		}
+
+		if err = q.DeleteUserRecoveryCodes(ctx, user.ID); err != nil {
+			return err
+		}
+
+		result.RecoveryCodes, err = totp.NewRecoveryCodes(totp.RecoveryCodeCount)
+
+		if err != nil {
+			return err
+		}
+
+		for _, code := range result.RecoveryCodes {
+			_, err = q.CreateRecoveryCode(ctx, CreateRecoveryCodeParams{
+				UserID:   user.ID,
+				CodeHash: util.HashSecretToken(code),
+			})
+
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+type DisableTOTPTxParams struct {
+	UserID int64  `json:"user_id"`
+	Code   string `json:"code"`
+}
+
+// DisableTOTPTx turns two-factor authentication off. Code may be a current
+// TOTP code or an unused recovery code, so a user who lost their device can
+// still opt out after signing in with a recovery code.
+func (s *PgStore) DisableTOTPTx(ctx context.Context, arg DisableTOTPTxParams) (*User, error) {
+	var user User
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		if user, err = getUserByID(ctx, q, arg.UserID); err != nil {
+			return err
+		}
+
+		if !user.IsTotpEnabled {
+			return ErrTwoFactorDisabled
+		}
+
+		ok, err := verifySecondFactor(ctx, q, user, arg.Code)
+
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return ErrTwoFactorCodeInvalid
+		}
+
+		if user, err = q.DisableUserTOTP(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return q.DeleteUserRecoveryCodes(ctx, user.ID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+type CompleteSigninChallengeTxParams struct {
+	TokenHash string       `json:"token_hash"`
+	Code      string       `json:"code"`
+	Policy    SigninPolicy `json:"-"`
+}
+
+// CompleteSigninChallengeTx checks the second factor of a pending sign-in
+// and spends the challenge on success. Wrong codes are counted on the
+// challenge, which is spent after MaxSigninChallengeAttempts of them, and
+// as failed sign-ins of the user, so that fresh challenges do not make for
+// unlimited guesses. While the user is delayed or locked out no code is
+// checked.
+func (s *PgStore) CompleteSigninChallengeTx(ctx context.Context, arg CompleteSigninChallengeTxParams) (*User, error) {
+	var (
+		user    User
+		invalid bool
+	)
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		challenge, err := q.GetSigninChallengeForUpdate(ctx, arg.TokenHash)
+
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrChallengeInvalid
+			}
+
+			return err
+		}
+
+		if challenge.UsedAt.Valid {
+			return ErrChallengeInvalid
+		}
+
+		if time.Now().After(challenge.ExpiresAt.Time) {
+			return ErrChallengeExpired
+		}
+
+		if user, err = getUserByID(ctx, q, challenge.UserID); err != nil {
+			return err
+		}
+
+		if !user.IsTotpEnabled {
+			return ErrChallengeInvalid
+		}
+
+		userKey := SigninThrottleUserKey(user.ID)
+		throttles, err := q.ClaimSigninThrottles(ctx, []string{userKey})
+
+		if err != nil {
+			return err
+		}
+
+		if !arg.Policy.latestRetryAt(throttles, time.Now()).IsZero() {
+			return util.ErrSigninThrottled
+		}
+
+		ok, err := verifySecondFactor(ctx, q, user, arg.Code)
+
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			if _, err = q.MarkSigninChallengeUsed(ctx, challenge.ID); err != nil {
+				return err
+			}
+
+			return q.DeleteSigninThrottle(ctx, userKey)
+		}
+
+		// The failed attempt has to be committed, so it is reported once
+		// the transaction is done instead of by returning an error here.
+		invalid = true
+
+		err = countSigninFailure(ctx, q, arg.Policy, userKey, arg.Policy.MaxUserFailures, true)
+
+		if err != nil {
+			return err
+		}
+
+		challenge, err = q.IncrementSigninChallengeAttempts(ctx, challenge.ID)
+
+		if err != nil {
+			return err
+		}
+
+		if challenge.Attempts >= MaxSigninChallengeAttempts {
+			_, err = q.MarkSigninChallengeUsed(ctx, challenge.ID)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if invalid {
+		return nil, ErrChallengeCodeInvalid
+	}
+
+	return &user, nil
+}
+
+// verifySecondFactor accepts a TOTP code newer than the last one used, or
+// an unused recovery code, which is spent.
+func verifySecondFactor(ctx context.Context, q *Queries, user User, code string) (bool, error) {
+	if totp.IsCode(code) {
+		step, ok := totp.Validate(user.TotpSecret.String, code, time.Now())
+
+		if !ok {
+			return false, nil
+		}
+
+		_, err := q.AdvanceUserTOTPStep(ctx, AdvanceUserTOTPStepParams{ID: user.ID, Step: step})
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+
+		return err == nil, err
+	}
+
+	_, err := q.UseRecoveryCode(ctx, UseRecoveryCodeParams{
+		UserID:   user.ID,
+		CodeHash: util.HashSecretToken(totp.NormalizeRecoveryCode(code)),
+	})
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func getUserByID(ctx context.Context, q *Queries, id int64) (User, error) {
+	return q.GetUserByUniqueID(ctx, GetUserByUniqueIDParams{
+		ID: pgtype.Int8{
+			Int64: id,
+			Valid: true,
+		},
+	})
+}
+
+type VerifySecondFactorTxParams struct {
+	UserID int64  `json:"user_id"`
+	Code   string `json:"code"`
+}
+
+// VerifySecondFactorTx checks a TOTP or recovery code of a signed in user,
+// for re-authentication. Accepted codes are spent like at sign-in.
+func (s *PgStore) VerifySecondFactorTx(ctx context.Context, arg VerifySecondFactorTxParams) error {
+	return s.execTx(ctx, func(q *Queries) error {
+		user, err := getUserByID(ctx, q, arg.UserID)
+
+		if err != nil {
+			return err
+		}
+
+		if !user.IsTotpEnabled {
+			return ErrTwoFactorDisabled
+		}
+
+		ok, err := verifySecondFactor(ctx, q, user, arg.Code)
+
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return ErrTwoFactorCodeInvalid
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,175 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: two_factor.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countUnusedRecoveryCodes = `-- name: CountUnusedRecoveryCodes :one
+SELECT count(*) FROM recovery_codes
+WHERE user_id = $1 AND used_at IS NULL
+`
+
+func (q *Queries) CountUnusedRecoveryCodes(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countUnusedRecoveryCodes, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :one
+INSERT INTO recovery_codes(user_id, code_hash)
+VALUES ($1, $2)
+RETURNING id, user_id, code_hash, used_at, created_at
+`
+
+type CreateRecoveryCodeParams struct {
+	UserID   int64  `json:"user_id"`
+	CodeHash string `json:"code_hash"`
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) (RecoveryCode, error) {
+	row := q.db.QueryRow(ctx, createRecoveryCode, arg.UserID, arg.CodeHash)
+	var i RecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CodeHash,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createSigninChallenge = `-- name: CreateSigninChallenge :one
+INSERT INTO signin_challenges(user_id, token_hash, expires_at)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, token_hash, attempts, expires_at, used_at, created_at
+`
+
+type CreateSigninChallengeParams struct {
+	UserID    int64              `json:"user_id"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateSigninChallenge(ctx context.Context, arg CreateSigninChallengeParams) (SigninChallenge, error) {
+	row := q.db.QueryRow(ctx, createSigninChallenge, arg.UserID, arg.TokenHash, arg.ExpiresAt)
+	var i SigninChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteUserRecoveryCodes = `-- name: DeleteUserRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteUserRecoveryCodes(ctx context.Context, userID int64) error {
+	_, err := q.db.Exec(ctx, deleteUserRecoveryCodes, userID)
+	return err
+}
+
+const getSigninChallengeForUpdate = `-- name: GetSigninChallengeForUpdate :one
+SELECT id, user_id, token_hash, attempts, expires_at, used_at, created_at FROM signin_challenges
+WHERE token_hash = $1
+LIMIT 1 FOR UPDATE
+`
+
+func (q *Queries) GetSigninChallengeForUpdate(ctx context.Context, tokenHash string) (SigninChallenge, error) {
+	row := q.db.QueryRow(ctx, getSigninChallengeForUpdate, tokenHash)
+	var i SigninChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const incrementSigninChallengeAttempts = `-- name: IncrementSigninChallengeAttempts :one
+UPDATE signin_challenges
+SET attempts = attempts + 1
+WHERE id = $1
+RETURNING id, user_id, token_hash, attempts, expires_at, used_at, created_at
+`
+
+func (q *Queries) IncrementSigninChallengeAttempts(ctx context.Context, id int64) (SigninChallenge, error) {
+	row := q.db.QueryRow(ctx, incrementSigninChallengeAttempts, id)
+	var i SigninChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const markSigninChallengeUsed = `-- name: MarkSigninChallengeUsed :one
+UPDATE signin_challenges
+SET used_at = now()
+WHERE id = $1
+RETURNING id, user_id, token_hash, attempts, expires_at, used_at, created_at
+`
+
+func (q *Queries) MarkSigninChallengeUsed(ctx context.Context, id int64) (SigninChallenge, error) {
+	row := q.db.QueryRow(ctx, markSigninChallengeUsed, id)
+	var i SigninChallenge
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.TokenHash,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :one
+UPDATE recovery_codes
+SET used_at = now()
+WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+RETURNING id, user_id, code_hash, used_at, created_at
+`
+
+type UseRecoveryCodeParams struct {
+	UserID   int64  `json:"user_id"`
+	CodeHash string `json:"code_hash"`
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (RecoveryCode, error) {
+	row := q.db.QueryRow(ctx, useRecoveryCode, arg.UserID, arg.CodeHash)
+	var i RecoveryCode
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CodeHash,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/totp"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomTOTPUser(t *testing.T) (User, string, []string) {
+	user, _ := createRandomUser(t)
+
+	secret, err := totp.NewSecret()
+	require.NoError(t, err)
+
+	_, err = testQueries.SetUserTOTPSecret(context.Background(), SetUserTOTPSecretParams{ID: user.ID, TotpSecret: secret})
+	require.NoError(t, err)
+
+	// Confirming with a code from the previous step leaves the current one
+	// free for the caller.
+	code, err := totp.Code(secret, time.Now().Add(-totp.Period))
+	require.NoError(t, err)
+
+	result, err := testQueries.EnableTOTPTx(context.Background(), EnableTOTPTxParams{UserID: user.ID, Code: code})
+	require.NoError(t, err)
+	require.True(t, result.User.IsTotpEnabled)
+	require.Len(t, result.RecoveryCodes, totp.RecoveryCodeCount)
+
+	return result.User, secret, result.RecoveryCodes
+}
+
+func createRandomSigninChallenge(t *testing.T, user User) string {
+	token, tokenHash, err := util.NewSecretToken()
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateSigninChallenge(context.Background(), CreateSigninChallengeParams{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	})
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestEnableTOTPTx(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	_, err := testQueries.EnableTOTPTx(context.Background(), EnableTOTPTxParams{UserID: user.ID, Code: "123456"})
+	require.ErrorIs(t, err, ErrTwoFactorNotEnrolled)
+
+	user, _, _ = createRandomTOTPUser(t)
+
+	_, err = testQueries.EnableTOTPTx(context.Background(), EnableTOTPTxParams{UserID: user.ID, Code: "123456"})
+	require.ErrorIs(t, err, ErrTwoFactorEnabled)
+
+	count, err := testQueries.CountUnusedRecoveryCodes(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(totp.RecoveryCodeCount), count)
+}
+
+func TestCompleteSigninChallengeTx(t *testing.T) {
+	user, secret, recoveryCodes := createRandomTOTPUser(t)
+
+	code, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+
+	token := createRandomSigninChallenge(t, user)
+	arg := CompleteSigninChallengeTxParams{TokenHash: util.HashSecretToken(token), Code: code}
+
+	signedIn, err := testQueries.CompleteSigninChallengeTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, signedIn.ID)
+
+	// The challenge is single use.
+	_, err = testQueries.CompleteSigninChallengeTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrChallengeInvalid)
+
+	// A code cannot be replayed on a new challenge.
+	token = createRandomSigninChallenge(t, user)
+	arg = CompleteSigninChallengeTxParams{TokenHash: util.HashSecretToken(token), Code: code}
+	_, err = testQueries.CompleteSigninChallengeTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrChallengeCodeInvalid)
+
+	// Recovery codes work once, however they are typed.
+	arg.Code = " " + recoveryCodes[0] + " "
+	_, err = testQueries.CompleteSigninChallengeTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	token = createRandomSigninChallenge(t, user)
+	arg = CompleteSigninChallengeTxParams{TokenHash: util.HashSecretToken(token), Code: recoveryCodes[0]}
+	_, err = testQueries.CompleteSigninChallengeTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrChallengeCodeInvalid)
+}
+
+func TestCompleteSigninChallengeTxAttempts(t *testing.T) {
+	user, secret, _ := createRandomTOTPUser(t)
+
+	token := createRandomSigninChallenge(t, user)
+	arg := CompleteSigninChallengeTxParams{TokenHash: util.HashSecretToken(token), Code: "000000"}
+
+	for i := 0; i < MaxSigninChallengeAttempts; i++ {
+		_, err := testQueries.CompleteSigninChallengeTx(context.Background(), arg)
+		require.ErrorIs(t, err, ErrChallengeCodeInvalid)
+	}
+
+	// Even a good code no longer helps once the challenge is spent.
+	code, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+
+	arg.Code = code
+	_, err = testQueries.CompleteSigninChallengeTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrChallengeInvalid)
+}
+
+func TestCompleteSigninChallengeTxLocksUser(t *testing.T) {
+	user, secret, _ := createRandomTOTPUser(t)
+
+	policy := SigninPolicy{
+		MaxUserFailures: 3,
+		MaxIPFailures:   100,
+		LockoutDuration: time.Minute,
+		FailureWindow:   time.Hour,
+	}
+
+	// Each wrong code counts for the user, whichever challenge it is for.
+	for i := 0; i < 3; i++ {
+		token := createRandomSigninChallenge(t, user)
+		arg := CompleteSigninChallengeTxParams{TokenHash: util.HashSecretToken(token), Code: "000000", Policy: policy}
+
+		_, err := testQueries.CompleteSigninChallengeTx(context.Background(), arg)
+		require.ErrorIs(t, err, ErrChallengeCodeInvalid)
+	}
+
+	code, err := totp.Code(secret, time.Now())
+	require.NoError(t, err)
+
+	token := createRandomSigninChallenge(t, user)
+	arg := CompleteSigninChallengeTxParams{TokenHash: util.HashSecretToken(token), Code: code, Policy: policy}
+
+	_, err = testQueries.CompleteSigninChallengeTx(context.Background(), arg)
+	require.ErrorIs(t, err, util.ErrSigninThrottled)
+
+	retryAt, err := testQueries.SigninRetryAt(context.Background(), SigninRetryAtParams{UserID: user.ID, ClientIP: randomClientIP(), Policy: policy})
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(time.Minute), retryAt, 5*time.Second)
+}
+
+func TestDisableTOTPTx(t *testing.T) {
+	user, _, recoveryCodes := createRandomTOTPUser(t)
+
+	_, err := testQueries.DisableTOTPTx(context.Background(), DisableTOTPTxParams{UserID: user.ID, Code: "000000"})
+	require.ErrorIs(t, err, ErrTwoFactorCodeInvalid)
+
+	disabled, err := testQueries.DisableTOTPTx(context.Background(), DisableTOTPTxParams{UserID: user.ID, Code: recoveryCodes[1]})
+	require.NoError(t, err)
+	require.False(t, disabled.IsTotpEnabled)
+	require.False(t, disabled.TotpSecret.Valid)
+
+	count, err := testQueries.CountUnusedRecoveryCodes(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	_, err = testQueries.DisableTOTPTx(context.Background(), DisableTOTPTxParams{UserID: user.ID, Code: recoveryCodes[2]})
+	require.ErrorIs(t, err, ErrTwoFactorDisabled)
+}
@@ -11,10 +11,43 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const advanceUserTOTPStep = `-- name: AdvanceUserTOTPStep :one
+UPDATE users
+SET totp_last_step = $1::bigint
+WHERE id = $2 AND (totp_last_step IS NULL OR totp_last_step < $1::bigint)
+RETURNING id, username, email, fullname, hashed_password, password_changed_at, created_at, role, is_email_verified, email_verified_at, totp_secret, is_totp_enabled, totp_last_step
+`
+
+type AdvanceUserTOTPStepParams struct {
+	Step int64 `json:"step"`
+	ID   int64 `json:"id"`
+}
+
+func (q *Queries) AdvanceUserTOTPStep(ctx context.Context, arg AdvanceUserTOTPStepParams) (User, error) {
+	row := q.db.QueryRow(ctx, advanceUserTOTPStep, arg.Step, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Fullname,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.IsTotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
-INSERT INTO users(username, email, fullname, hashed_password, password_salt)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, username, email, fullname, hashed_password, password_salt, password_changed_at, created_at
+INSERT INTO users(username, email, fullname, hashed_password)
+VALUES ($1, $2, $3, $4)
+RETURNING id, username, email, fullname, hashed_password, password_changed_at, created_at, role, is_email_verified, email_verified_at, totp_secret, is_totp_enabled, totp_last_step
 `
 
 type CreateUserParams struct {
@@ -22,7 +55,6 @@ type CreateUserParams struct {
 	Email          string `json:"email"`
 	Fullname       string `json:"fullname"`
 	HashedPassword string `json:"hashed_password"`
-	PasswordSalt   string `json:"password_salt"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -31,7 +63,6 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.Email,
 		arg.Fullname,
 		arg.HashedPassword,
-		arg.PasswordSalt,
 	)
 	var i User
 	err := row.Scan(
@@ -40,15 +71,76 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.Fullname,
 		&i.HashedPassword,
-		&i.PasswordSalt,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.IsTotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const disableUserTOTP = `-- name: DisableUserTOTP :one
+UPDATE users
+SET totp_secret = NULL, is_totp_enabled = false, totp_last_step = NULL
+WHERE id = $1
+RETURNING id, username, email, fullname, hashed_password, password_changed_at, created_at, role, is_email_verified, email_verified_at, totp_secret, is_totp_enabled, totp_last_step
+`
+
+func (q *Queries) DisableUserTOTP(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, disableUserTOTP, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Fullname,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.IsTotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const enableUserTOTP = `-- name: EnableUserTOTP :one
+UPDATE users
+SET is_totp_enabled = true
+WHERE id = $1 AND totp_secret IS NOT NULL
+RETURNING id, username, email, fullname, hashed_password, password_changed_at, created_at, role, is_email_verified, email_verified_at, totp_secret, is_totp_enabled, totp_last_step
+`
+
+func (q *Queries) EnableUserTOTP(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, enableUserTOTP, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Fullname,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.IsTotpEnabled,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
 
 const getUserByUniqueID = `-- name: GetUserByUniqueID :one
-SELECT id, username, email, fullname, hashed_password, password_salt, password_changed_at, created_at FROM users
+SELECT id, username, email, fullname, hashed_password, password_changed_at, created_at, role, is_email_verified, email_verified_at, totp_secret, is_totp_enabled, totp_last_step FROM users
 WHERE id = $1
 or email ilike $2
 or username ilike $3
@@ -70,15 +162,47 @@ func (q *Queries) GetUserByUniqueID(ctx context.Context, arg GetUserByUniqueIDPa
 		&i.Email,
 		&i.Fullname,
 		&i.HashedPassword,
-		&i.PasswordSalt,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.IsTotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+SELECT id, username, email, fullname, hashed_password, password_changed_at, created_at, role, is_email_verified, email_verified_at, totp_secret, is_totp_enabled, totp_last_step FROM users
+WHERE id = $1
+LIMIT 1 FOR UPDATE
+`
+
+func (q *Queries) GetUserForUpdate(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, getUserForUpdate, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Fullname,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.IsTotpEnabled,
+		&i.TotpLastStep,
 	)
 	return i, err
 }
 
 const getUsers = `-- name: GetUsers :many
-SELECT id, username, email, fullname, hashed_password, password_salt, password_changed_at, created_at FROM users
+SELECT id, username, email, fullname, hashed_password, password_changed_at, created_at, role, is_email_verified, email_verified_at, totp_secret, is_totp_enabled, totp_last_step FROM users
 WHERE ($3::int[] IS NULL OR id = ANY($3::int[]))
 OFFSET $1
 LIMIT $2
@@ -105,9 +229,14 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 			&i.Email,
 			&i.Fullname,
 			&i.HashedPassword,
-			&i.PasswordSalt,
 			&i.PasswordChangedAt,
 			&i.CreatedAt,
+			&i.Role,
+			&i.IsEmailVerified,
+			&i.EmailVerifiedAt,
+			&i.TotpSecret,
+			&i.IsTotpEnabled,
+			&i.TotpLastStep,
 		); err != nil {
 			return nil, err
 		}
@@ -118,3 +247,123 @@ func (q *Queries) GetUsers(ctx context.Context, arg GetUsersParams) ([]User, err
 	}
 	return items, nil
 }
+
+const markUserEmailVerified = `-- name: MarkUserEmailVerified :one
+UPDATE users
+SET is_email_verified = true, email_verified_at = now()
+WHERE id = $1 AND email = $2
+RETURNING id, username, email, fullname, hashed_password, password_changed_at, created_at, role, is_email_verified, email_verified_at, totp_secret, is_totp_enabled, totp_last_step
+`
+
+type MarkUserEmailVerifiedParams struct {
+	ID    int64  `json:"id"`
+	Email string `json:"email"`
+}
+
+func (q *Queries) MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (User, error) {
+	row := q.db.QueryRow(ctx, markUserEmailVerified, arg.ID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Fullname,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.IsTotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const rehashUserPassword = `-- name: RehashUserPassword :execrows
+UPDATE users
+SET hashed_password = $1
+WHERE id = $2 AND hashed_password = $3
+`
+
+type RehashUserPasswordParams struct {
+	HashedPassword    string `json:"hashed_password"`
+	ID                int64  `json:"id"`
+	OldHashedPassword string `json:"old_hashed_password"`
+}
+
+func (q *Queries) RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rehashUserPassword, arg.HashedPassword, arg.ID, arg.OldHashedPassword)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setUserTOTPSecret = `-- name: SetUserTOTPSecret :one
+UPDATE users
+SET totp_secret = $1::varchar, is_totp_enabled = false, totp_last_step = NULL
+WHERE id = $2 AND is_totp_enabled = false
+RETURNING id, username, email, fullname, hashed_password, password_changed_at, created_at, role, is_email_verified, email_verified_at, totp_secret, is_totp_enabled, totp_last_step
+`
+
+type SetUserTOTPSecretParams struct {
+	TotpSecret string `json:"totp_secret"`
+	ID         int64  `json:"id"`
+}
+
+func (q *Queries) SetUserTOTPSecret(ctx context.Context, arg SetUserTOTPSecretParams) (User, error) {
+	row := q.db.QueryRow(ctx, setUserTOTPSecret, arg.TotpSecret, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Fullname,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.IsTotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users
+SET hashed_password = $1,
+    password_changed_at = now()
+WHERE id = $2
+RETURNING id, username, email, fullname, hashed_password, password_changed_at, created_at, role, is_email_verified, email_verified_at, totp_secret, is_totp_enabled, totp_last_step
+`
+
+type UpdateUserPasswordParams struct {
+	HashedPassword string `json:"hashed_password"`
+	ID             int64  `json:"id"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPassword, arg.HashedPassword, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.Fullname,
+		&i.HashedPassword,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.Role,
+		&i.IsEmailVerified,
+		&i.EmailVerifiedAt,
+		&i.TotpSecret,
+		&i.IsTotpEnabled,
+		&i.TotpLastStep,
+	)
+	return i, err
+}
@@ -14,15 +14,13 @@ import (
 func createRandomUser(t *testing.T) (User, string) {
 	ag := hash.DefaultArgonHash()
 	password := util.RandomString(6)
-	passwordHash, err := ag.GenerateHash([]byte(password), nil)
-	hashedPasswordStr, saltStr := hash.ArgonStringEncode(passwordHash)
+	hashedPassword, err := ag.Hash([]byte(password))
 
 	require.NoError(t, err)
 	arg := CreateUserParams{
 		Username:       util.RandomOwner(),
 		Fullname:       util.RandomOwner(),
-		HashedPassword: hashedPasswordStr,
-		PasswordSalt:   saltStr,
+		HashedPassword: hashedPassword,
 		Email:          util.RandomEmail(),
 	}
 
@@ -68,3 +66,32 @@ func TestGetUser(t *testing.T) {
 	require.Equal(t, user.Email, user2.Email)
 	require.WithinDuration(t, user.CreatedAt.Time, user2.CreatedAt.Time, time.Second)
 }
+
+func TestRehashUserPassword(t *testing.T) {
+	user, password := createRandomUser(t)
+
+	rehashed, err := hash.DefaultArgonHash().Hash([]byte(password))
+	require.NoError(t, err)
+
+	arg := RehashUserPasswordParams{
+		ID:                user.ID,
+		HashedPassword:    rehashed,
+		OldHashedPassword: user.HashedPassword,
+	}
+
+	rows, err := testQueries.RehashUserPassword(context.Background(), arg)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, rows)
+
+	// The old hash is gone, a second rehash from it changes nothing.
+	rows, err = testQueries.RehashUserPassword(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	updated, err := testQueries.GetUserByUniqueID(context.Background(), GetUserByUniqueIDParams{
+		ID: pgtype.Int8{Int64: user.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, rehashed, updated.HashedPassword)
+	require.Equal(t, user.PasswordChangedAt, updated.PasswordChangedAt)
+}
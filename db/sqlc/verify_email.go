@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+var ErrVerifyTokenInvalid = util.NewCustomError(util.KindInvalidArgument, "ErrVerifyTokenInvalid", "verification link is invalid or already used")
+var ErrVerifyTokenExpired = util.NewCustomError(util.KindExpired, "ErrVerifyTokenExpired", "verification link has expired")
+var ErrVerifyEmailsExceeded = util.NewCustomError(util.KindRateLimited, "ErrVerifyEmailsExceeded", "too many verification emails requested, try again later")
+
+// VerifyEmailTx consumes the verification token with the given hash and
+// marks its user's email as verified. A token only verifies the address it
+// was sent to, so it becomes invalid if the user changes email meanwhile.
+func (s *PgStore) VerifyEmailTx(ctx context.Context, tokenHash string) (*User, error) {
+	var user User
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		verifyEmail, err := q.GetVerifyEmailForUpdate(ctx, tokenHash)
+
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrVerifyTokenInvalid
+			}
+
+			return err
+		}
+
+		if verifyEmail.UsedAt.Valid {
+			return ErrVerifyTokenInvalid
+		}
+
+		if time.Now().After(verifyEmail.ExpiresAt.Time) {
+			return ErrVerifyTokenExpired
+		}
+
+		if _, err = q.MarkVerifyEmailUsed(ctx, verifyEmail.ID); err != nil {
+			return err
+		}
+
+		user, err = q.MarkUserEmailVerified(ctx, MarkUserEmailVerifiedParams{
+			ID:    verifyEmail.UserID,
+			Email: verifyEmail.Email,
+		})
+
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrVerifyTokenInvalid
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+type ResendVerifyEmailTxParams struct {
+	UserID    int64     `json:"user_id"`
+	Email     string    `json:"email"`
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Limit is how many tokens the user may be issued since Since.
+	Limit int64     `json:"limit"`
+	Since time.Time `json:"since"`
+}
+
+// ResendVerifyEmailTx records a new verification token for the user,
+// failing with ErrVerifyEmailsExceeded once Limit of them were issued
+// since Since. The user row stays locked from the count to the insert, so
+// parallel requests cannot go over the limit between them.
+func (s *PgStore) ResendVerifyEmailTx(ctx context.Context, arg ResendVerifyEmailTxParams) (*VerifyEmail, error) {
+	var verifyEmail VerifyEmail
+
+	err := s.execTx(ctx, func(q *Queries) error {
+		if _, err := q.GetUserForUpdate(ctx, arg.UserID); err != nil {
+			return err
+		}
+
+		recent, err := q.CountVerifyEmailsSince(ctx, CountVerifyEmailsSinceParams{
+			UserID: arg.UserID,
+			Since:  pgtype.Timestamptz{Time: arg.Since, Valid: true},
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if recent >= arg.Limit {
+			return ErrVerifyEmailsExceeded
+		}
+
+		verifyEmail, err = q.CreateVerifyEmail(ctx, CreateVerifyEmailParams{
+			UserID:    arg.UserID,
+			Email:     arg.Email,
+			TokenHash: arg.TokenHash,
+			ExpiresAt: pgtype.Timestamptz{Time: arg.ExpiresAt, Valid: true},
+		})
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &verifyEmail, nil
+}
@@ -0,0 +1,105 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: verify_email.sql
+
+package db
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countVerifyEmailsSince = `-- name: CountVerifyEmailsSince :one
+SELECT count(*) FROM verify_emails
+WHERE user_id = $1 AND created_at >= $2
+`
+
+type CountVerifyEmailsSinceParams struct {
+	UserID int64              `json:"user_id"`
+	Since  pgtype.Timestamptz `json:"since"`
+}
+
+func (q *Queries) CountVerifyEmailsSince(ctx context.Context, arg CountVerifyEmailsSinceParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countVerifyEmailsSince, arg.UserID, arg.Since)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createVerifyEmail = `-- name: CreateVerifyEmail :one
+INSERT INTO verify_emails(user_id, email, token_hash, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, email, token_hash, expires_at, used_at, created_at
+`
+
+type CreateVerifyEmailParams struct {
+	UserID    int64              `json:"user_id"`
+	Email     string             `json:"email"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+}
+
+func (q *Queries) CreateVerifyEmail(ctx context.Context, arg CreateVerifyEmailParams) (VerifyEmail, error) {
+	row := q.db.QueryRow(ctx, createVerifyEmail,
+		arg.UserID,
+		arg.Email,
+		arg.TokenHash,
+		arg.ExpiresAt,
+	)
+	var i VerifyEmail
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Email,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getVerifyEmailForUpdate = `-- name: GetVerifyEmailForUpdate :one
+SELECT id, user_id, email, token_hash, expires_at, used_at, created_at FROM verify_emails
+WHERE token_hash = $1
+LIMIT 1 FOR UPDATE
+`
+
+func (q *Queries) GetVerifyEmailForUpdate(ctx context.Context, tokenHash string) (VerifyEmail, error) {
+	row := q.db.QueryRow(ctx, getVerifyEmailForUpdate, tokenHash)
+	var i VerifyEmail
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Email,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const markVerifyEmailUsed = `-- name: MarkVerifyEmailUsed :one
+UPDATE verify_emails
+SET used_at = now()
+WHERE id = $1
+RETURNING id, user_id, email, token_hash, expires_at, used_at, created_at
+`
+
+func (q *Queries) MarkVerifyEmailUsed(ctx context.Context, id int64) (VerifyEmail, error) {
+	row := q.db.QueryRow(ctx, markVerifyEmailUsed, id)
+	var i VerifyEmail
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Email,
+		&i.TokenHash,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
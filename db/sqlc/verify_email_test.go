@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomVerifyEmail(t *testing.T, user User, ttl time.Duration) string {
+	token, tokenHash, err := util.NewSecretToken()
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateVerifyEmail(context.Background(), CreateVerifyEmailParams{
+		UserID:    user.ID,
+		Email:     user.Email,
+		TokenHash: tokenHash,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(ttl), Valid: true},
+	})
+	require.NoError(t, err)
+
+	return token
+}
+
+func TestVerifyEmailTx(t *testing.T) {
+	user, _ := createRandomUser(t)
+	require.False(t, user.IsEmailVerified)
+
+	token := createRandomVerifyEmail(t, user, time.Hour)
+
+	verified, err := testQueries.VerifyEmailTx(context.Background(), util.HashSecretToken(token))
+	require.NoError(t, err)
+	require.True(t, verified.IsEmailVerified)
+	require.True(t, verified.EmailVerifiedAt.Valid)
+
+	// A token can only be used once.
+	_, err = testQueries.VerifyEmailTx(context.Background(), util.HashSecretToken(token))
+	require.ErrorIs(t, err, ErrVerifyTokenInvalid)
+}
+
+func TestVerifyEmailTxExpired(t *testing.T) {
+	user, _ := createRandomUser(t)
+	token := createRandomVerifyEmail(t, user, -time.Minute)
+
+	_, err := testQueries.VerifyEmailTx(context.Background(), util.HashSecretToken(token))
+	require.ErrorIs(t, err, ErrVerifyTokenExpired)
+
+	_, err = testQueries.VerifyEmailTx(context.Background(), util.HashSecretToken("unknown"))
+	require.ErrorIs(t, err, ErrVerifyTokenInvalid)
+}
+
+func TestResendVerifyEmailTxLimit(t *testing.T) {
+	user, _ := createRandomUser(t)
+
+	// Parallel resends cannot go over the limit between them.
+	n, limit := 6, int64(3)
+	errs := make(chan error, n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			_, tokenHash, err := util.NewSecretToken()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			_, err = testQueries.ResendVerifyEmailTx(context.Background(), ResendVerifyEmailTxParams{
+				UserID:    user.ID,
+				Email:     user.Email,
+				TokenHash: tokenHash,
+				ExpiresAt: time.Now().Add(time.Hour),
+				Limit:     limit,
+				Since:     time.Now().Add(-time.Hour),
+			})
+			errs <- err
+		}()
+	}
+
+	sent := int64(0)
+
+	for i := 0; i < n; i++ {
+		err := <-errs
+
+		if err == nil {
+			sent++
+			continue
+		}
+
+		require.ErrorIs(t, err, ErrVerifyEmailsExceeded)
+	}
+
+	require.Equal(t, limit, sent)
+}
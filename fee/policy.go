@@ -0,0 +1,100 @@
+package fee
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+type Kind string
+
+const (
+	Flat       Kind = "flat"
+	Percentage Kind = "percentage"
+	Tiered     Kind = "tiered"
+)
+
+var ErrUnknownKind = errors.New("unknown fee policy kind")
+
+// Tier applies to amounts up to and including UpTo. A nil UpTo
+// marks the open-ended last tier.
+type Tier struct {
+	UpTo       *float64 `json:"up_to"`
+	Flat       float64  `json:"flat"`
+	Percentage float64  `json:"percentage"`
+}
+
+// Policy is a single versioned fee rule. Percentages are expressed
+// as percent values, so 1.5 means 1.5% of the transfer amount.
+type Policy struct {
+	ID         int64
+	Version    int32
+	Kind       Kind
+	FlatAmount float64
+	Percentage float64
+	MinFee     float64
+	MaxFee     *float64
+	Tiers      []Tier
+}
+
+// ParseTiers decodes the tiers column of a fee policy row.
+func ParseTiers(raw []byte) ([]Tier, error) {
+	var tiers []Tier
+
+	if len(raw) == 0 {
+		return tiers, nil
+	}
+
+	if err := json.Unmarshal(raw, &tiers); err != nil {
+		return nil, fmt.Errorf("invalid fee tiers: %w", err)
+	}
+
+	return tiers, nil
+}
+
+// Compute returns the fee charged on amount, rounded to cents and
+// clamped to the policy min/max bounds.
+func (p *Policy) Compute(amount float64) (float64, error) {
+	var fee float64
+
+	switch p.Kind {
+	case Flat:
+		fee = p.FlatAmount
+	case Percentage:
+		fee = amount * p.Percentage / 100
+	case Tiered:
+		tier, ok := p.tierFor(amount)
+		if !ok {
+			return 0, fmt.Errorf("no fee tier covers amount %v", amount)
+		}
+		fee = tier.Flat + amount*tier.Percentage/100
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrUnknownKind, p.Kind)
+	}
+
+	if fee < p.MinFee {
+		fee = p.MinFee
+	}
+
+	if p.MaxFee != nil && fee > *p.MaxFee {
+		fee = *p.MaxFee
+	}
+
+	return Round(fee), nil
+}
+
+func (p *Policy) tierFor(amount float64) (Tier, bool) {
+	for _, tier := range p.Tiers {
+		if tier.UpTo == nil || amount <= *tier.UpTo {
+			return tier, true
+		}
+	}
+
+	return Tier{}, false
+}
+
+// Round rounds a monetary amount to two decimal places.
+func Round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
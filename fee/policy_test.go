@@ -0,0 +1,113 @@
+package fee
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func TestComputeFee(t *testing.T) {
+	testCases := []struct {
+		name   string
+		policy Policy
+		amount float64
+		fee    float64
+	}{
+		{
+			name:   "Flat",
+			policy: Policy{Kind: Flat, FlatAmount: 1.5},
+			amount: 200,
+			fee:    1.5,
+		},
+		{
+			name:   "Percentage",
+			policy: Policy{Kind: Percentage, Percentage: 1.25},
+			amount: 200,
+			fee:    2.5,
+		},
+		{
+			name:   "PercentageBelowMin",
+			policy: Policy{Kind: Percentage, Percentage: 1, MinFee: 0.5},
+			amount: 10,
+			fee:    0.5,
+		},
+		{
+			name:   "PercentageAboveMax",
+			policy: Policy{Kind: Percentage, Percentage: 1, MaxFee: ptr(5)},
+			amount: 10_000,
+			fee:    5,
+		},
+		{
+			name: "TieredFirstTier",
+			policy: Policy{Kind: Tiered, Tiers: []Tier{
+				{UpTo: ptr(100), Flat: 0.25},
+				{UpTo: ptr(1000), Flat: 0.5, Percentage: 0.5},
+				{Percentage: 0.25},
+			}},
+			amount: 100,
+			fee:    0.25,
+		},
+		{
+			name: "TieredMiddleTier",
+			policy: Policy{Kind: Tiered, Tiers: []Tier{
+				{UpTo: ptr(100), Flat: 0.25},
+				{UpTo: ptr(1000), Flat: 0.5, Percentage: 0.5},
+				{Percentage: 0.25},
+			}},
+			amount: 500,
+			fee:    3,
+		},
+		{
+			name: "TieredOpenEndedTier",
+			policy: Policy{Kind: Tiered, Tiers: []Tier{
+				{UpTo: ptr(100), Flat: 0.25},
+				{Percentage: 0.25},
+			}},
+			amount: 5000,
+			fee:    12.5,
+		},
+		{
+			name:   "RoundsToCents",
+			policy: Policy{Kind: Percentage, Percentage: 0.333},
+			amount: 10,
+			fee:    0.03,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fee, err := tc.policy.Compute(tc.amount)
+			require.NoError(t, err)
+			require.Equal(t, tc.fee, fee)
+		})
+	}
+}
+
+func TestComputeFeeNoMatchingTier(t *testing.T) {
+	policy := Policy{Kind: Tiered, Tiers: []Tier{{UpTo: ptr(100), Flat: 1}}}
+
+	_, err := policy.Compute(101)
+	require.Error(t, err)
+}
+
+func TestComputeFeeUnknownKind(t *testing.T) {
+	policy := Policy{Kind: "bogus"}
+
+	_, err := policy.Compute(10)
+	require.ErrorIs(t, err, ErrUnknownKind)
+}
+
+func TestParseTiers(t *testing.T) {
+	tiers, err := ParseTiers([]byte(`[{"up_to": 100, "flat": 1}, {"up_to": null, "percentage": 0.5}]`))
+	require.NoError(t, err)
+	require.Len(t, tiers, 2)
+	require.Equal(t, 100.0, *tiers[0].UpTo)
+	require.Nil(t, tiers[1].UpTo)
+
+	_, err = ParseTiers([]byte(`{`))
+	require.Error(t, err)
+}
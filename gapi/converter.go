@@ -12,6 +12,8 @@ func convertDbUser(user db.User) *pb.User {
 		Username:          user.Username,
 		Email:             user.Email,
 		Fullname:          user.Fullname,
+		IsEmailVerified:   user.IsEmailVerified,
+		IsTotpEnabled:     user.IsTotpEnabled,
 		PasswordChangedAt: timestamppb.New(user.PasswordChangedAt.Time),
 		CreatedAt:         timestamppb.New(user.CreatedAt.Time),
 	}
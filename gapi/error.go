@@ -0,0 +1,215 @@
+package gapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/textproto"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	requestIDHeader                 = "x-request-id"
+	acceptLanguageHeader            = "accept-language"
+	grpcGatewayAcceptLanguageHeader = "grpcgateway-accept-language"
+
+	// errorDomain is the errdetails.ErrorInfo domain of catalog errors.
+	errorDomain = "cedar-bank-api"
+)
+
+// ErrorInterceptor renders errors from the catalog as statuses. Each
+// status carries an errdetails.ErrorInfo with the error code, an
+// errdetails.RequestInfo with the id RequestIDInterceptor gave the call
+// and, when fields were invalid, an errdetails.BadRequest. Errors outside
+// the catalog are logged and reported as util.ErrInternal.
+func ErrorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	rsp, err := handler(ctx, req)
+
+	if err == nil {
+		return rsp, nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return nil, err
+	}
+
+	// Nobody is waiting for the answer to a canceled call.
+	if errors.Is(err, context.Canceled) {
+		return nil, status.FromContextError(err).Err()
+	}
+
+	customErr, ok := util.AsCustomError(err)
+
+	if !ok || customErr.Kind() == util.KindInternal {
+		util.Logger(ctx).Error("internal error", "rpc", info.FullMethod, "error", err)
+	}
+
+	acceptLanguage := firstMetadata(ctx, acceptLanguageHeader)
+
+	if acceptLanguage == "" {
+		acceptLanguage = firstMetadata(ctx, grpcGatewayAcceptLanguageHeader)
+	}
+
+	return nil, errorStatus(customErr, util.RequestIDFromContext(ctx), acceptLanguage).Err()
+}
+
+// errorStatus renders e as a status for the call with id requestID.
+func errorStatus(e util.CustomError, requestID, acceptLanguage string) *status.Status {
+	st := status.New(e.Kind().GRPCCode(), e.Localize(acceptLanguage))
+
+	detailed, err := st.WithDetails(
+		&errdetails.ErrorInfo{Reason: e.Code(), Domain: errorDomain},
+		&errdetails.RequestInfo{RequestId: requestID},
+	)
+
+	if err != nil {
+		return st
+	}
+
+	if violations := e.Violations(); len(violations) != 0 {
+		badRequest := &errdetails.BadRequest{}
+
+		for _, violation := range violations {
+			badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       violation.Field,
+				Description: violation.Description,
+			})
+		}
+
+		if withViolations, err := detailed.WithDetails(badRequest); err == nil {
+			detailed = withViolations
+		}
+	}
+
+	return detailed
+}
+
+func firstMetadata(ctx context.Context, key string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(key); len(values) != 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
+// gatewayErrors stands in for the catalog error of statuses without an
+// errdetails.ErrorInfo, which the gateway makes itself for a request body
+// it cannot parse or a path it does not route, or for a call that ran out
+// of time before reaching the gRPC server.
+var gatewayErrors = map[codes.Code]util.CustomError{
+	codes.InvalidArgument:   util.ErrInvalidArgument,
+	codes.NotFound:          util.ErrRouteNotFound,
+	codes.Unimplemented:     util.ErrRouteNotFound,
+	codes.ResourceExhausted: util.ErrRateLimited,
+	codes.DeadlineExceeded:  util.ErrDeadlineExceeded,
+}
+
+// GatewayErrorHandler renders errors for gateway clients in the same body
+// as the REST server, so clients see one error model whichever server
+// they talk to. Use it with runtime.WithErrorHandler.
+func GatewayErrorHandler(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+	var httpErr *runtime.HTTPStatusError
+
+	if errors.As(err, &httpErr) {
+		err = httpErr.Err
+	}
+
+	body, httpStatus := gatewayErrorBody(status.Convert(err), r.Header.Get("Accept-Language"))
+
+	// The gateway's own errors, such as a method a path does not
+	// support, may come with the status to answer with.
+	if httpErr != nil {
+		httpStatus = httpErr.HTTPStatus
+	}
+
+	if md, ok := runtime.ServerMetadataFromContext(ctx); ok {
+		if values := md.HeaderMD.Get("retry-after"); len(values) != 0 {
+			w.Header().Set("Retry-After", values[0])
+		}
+	}
+
+	if body.RequestID != "" {
+		w.Header().Set(util.RequestIDHeader, body.RequestID)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+
+	if err := json.NewEncoder(w).Encode(util.ErrorResponse{Status: false, Error: body}); err != nil {
+		util.Logger(r.Context()).Error("cannot write error response", "error", err)
+	}
+}
+
+func gatewayErrorBody(st *status.Status, acceptLanguage string) (util.ErrorBody, int) {
+	var body util.ErrorBody
+	httpStatus := runtime.HTTPStatusFromCode(st.Code())
+
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			body.Code = detail.GetReason()
+
+			if e, ok := util.LookupError(detail.GetReason()); ok {
+				httpStatus = e.Kind().HTTPStatus()
+			}
+		case *errdetails.RequestInfo:
+			body.RequestID = detail.GetRequestId()
+		case *errdetails.BadRequest:
+			for _, violation := range detail.GetFieldViolations() {
+				body.Violations = append(body.Violations, util.FieldViolation{
+					Field:       violation.GetField(),
+					Description: violation.GetDescription(),
+				})
+			}
+		}
+	}
+
+	if body.Code != "" {
+		body.Message = st.Message()
+		return body, httpStatus
+	}
+
+	e, ok := gatewayErrors[st.Code()]
+
+	if !ok {
+		e = util.ErrInternal
+	}
+
+	if st.Code() == codes.InvalidArgument {
+		e = e.WithViolations(util.FieldViolation{Field: "body", Description: st.Message()})
+	}
+
+	return e.Body(body.RequestID, acceptLanguage), e.Kind().HTTPStatus()
+}
+
+// GatewayIncomingHeader forwards X-Request-Id to the gRPC server, which
+// runtime.DefaultHeaderMatcher would drop, so a client's id follows the
+// call. Use it with runtime.WithIncomingHeaderMatcher.
+func GatewayIncomingHeader(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == util.RequestIDHeader {
+		return requestIDHeader, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// GatewayOutgoingHeader drops the request id the gRPC server sends back,
+// which is the one HTTPLogger already answered with in X-Request-Id. Use
+// it with runtime.WithOutgoingHeaderMatcher.
+func GatewayOutgoingHeader(key string) (string, bool) {
+	if key == requestIDHeader {
+		return "", false
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
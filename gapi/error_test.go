@@ -0,0 +1,120 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testMethod = &grpc.UnaryServerInfo{FullMethod: "/pb.SimpleBank/Test"}
+
+func interceptError(t *testing.T, md metadata.MD, handlerErr error) *status.Status {
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	_, err := RequestIDInterceptor(ctx, nil, testMethod, func(ctx context.Context, req any) (any, error) {
+		return ErrorInterceptor(ctx, req, testMethod, func(ctx context.Context, req any) (any, error) {
+			return nil, handlerErr
+		})
+	})
+
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+
+	return st
+}
+
+func TestErrorInterceptor(t *testing.T) {
+	t.Run("CatalogError", func(t *testing.T) {
+		st := interceptError(t, metadata.Pairs(requestIDHeader, "client-chosen-id"), db.ErrHoldNotPending)
+
+		require.Equal(t, codes.FailedPrecondition, st.Code())
+		require.Equal(t, db.ErrHoldNotPending.Error(), st.Message())
+		require.Len(t, st.Details(), 2)
+
+		info := st.Details()[0].(*errdetails.ErrorInfo)
+		require.Equal(t, db.ErrHoldNotPending.Code(), info.GetReason())
+		require.Equal(t, "client-chosen-id", st.Details()[1].(*errdetails.RequestInfo).GetRequestId())
+	})
+
+	t.Run("Violations", func(t *testing.T) {
+		var v violations
+
+		v.add("username", validateRequired(""))
+		v.add("email", validateEmail("jdoe"))
+
+		st := interceptError(t, nil, v.err())
+
+		require.Equal(t, codes.InvalidArgument, st.Code())
+		require.Len(t, st.Details(), 3)
+
+		badRequest := st.Details()[2].(*errdetails.BadRequest)
+		require.Len(t, badRequest.GetFieldViolations(), 2)
+		require.Equal(t, "username", badRequest.GetFieldViolations()[0].GetField())
+		require.Equal(t, "is not a valid email address", badRequest.GetFieldViolations()[1].GetDescription())
+	})
+
+	t.Run("InternalErrorHidden", func(t *testing.T) {
+		st := interceptError(t, nil, errors.New("connection to 10.0.0.5:5432 refused"))
+
+		require.Equal(t, codes.Internal, st.Code())
+		require.Equal(t, util.ErrInternal.Error(), st.Message())
+	})
+
+	t.Run("DeadlineExceeded", func(t *testing.T) {
+		st := interceptError(t, nil, fmt.Errorf("failed to verify email: %w", context.DeadlineExceeded))
+
+		require.Equal(t, codes.DeadlineExceeded, st.Code())
+		require.Equal(t, util.ErrDeadlineExceeded.Code(), st.Details()[0].(*errdetails.ErrorInfo).GetReason())
+	})
+
+	t.Run("StatusPassesThrough", func(t *testing.T) {
+		st := interceptError(t, nil, status.Error(codes.Unavailable, "draining"))
+
+		require.Equal(t, codes.Unavailable, st.Code())
+		require.Equal(t, "draining", st.Message())
+	})
+}
+
+func TestGatewayErrorBody(t *testing.T) {
+	st := errorStatus(db.ErrTransferAlreadyReversed, "some-id", "")
+
+	body, httpStatus := gatewayErrorBody(st, "")
+	require.Equal(t, http.StatusConflict, httpStatus)
+	require.Equal(t, db.ErrTransferAlreadyReversed.Code(), body.Code)
+	require.Equal(t, "some-id", body.RequestID)
+
+	st = errorStatus(util.ErrInvalidArgument.WithViolations(util.FieldViolation{Field: "email", Description: "is required"}), "some-id", "")
+
+	body, httpStatus = gatewayErrorBody(st, "")
+	require.Equal(t, http.StatusBadRequest, httpStatus)
+	require.Equal(t, []util.FieldViolation{{Field: "email", Description: "is required"}}, body.Violations)
+
+	// The gateway's own errors carry no catalog code.
+	body, httpStatus = gatewayErrorBody(status.New(codes.InvalidArgument, "unexpected EOF"), "")
+	require.Equal(t, http.StatusBadRequest, httpStatus)
+	require.Equal(t, util.ErrInvalidArgument.Code(), body.Code)
+	require.Equal(t, "body", body.Violations[0].Field)
+}
+
+func TestGatewayErrorHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+
+	GatewayErrorHandler(context.Background(), nil, nil, recorder, request, errorStatus(util.ErrRateLimited, "some-id", "").Err())
+
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	require.Equal(t, "some-id", recorder.Header().Get(util.RequestIDHeader))
+	require.JSONEq(t, `{"status":false,"error":{"code":"ErrRateLimited","message":"too many requests, try again later","request_id":"some-id"}}`, recorder.Body.String())
+}
@@ -0,0 +1,171 @@
+package gapi
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// RequestIDInterceptor is the outermost unary interceptor. It gives every
+// call a request id, taken from the x-request-id metadata when the client,
+// or HTTPLogger in front of the gateway, sent a usable one. The id is sent
+// back in the x-request-id header and kept in the context along with a
+// logger that tags records with it.
+func RequestIDInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	requestID := util.RequestID(firstMetadata(ctx, requestIDHeader))
+	logger := slog.Default().With("request_id", requestID)
+
+	ctx = util.WithLogger(util.WithRequestID(ctx, requestID), logger)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDHeader, requestID))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("request.id", requestID))
+
+	return handler(ctx, req)
+}
+
+// AccessLog is a unary interceptor logging every call once it has been
+// answered, at warn for client errors and error for server errors. The
+// request message is logged at debug, with sensitive fields redacted. It
+// must come after RequestIDInterceptor and before ErrorInterceptor, so it
+// sees the status the client gets.
+func (s *GrpcServer) AccessLog(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	rsp, err := handler(ctx, req)
+	code := status.Code(err)
+
+	attrs := []slog.Attr{
+		slog.String("rpc", info.FullMethod),
+		slog.String("status", code.String()),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("client_ip", clientIP(ctx)),
+	}
+
+	if userID := s.bearerUserID(ctx); userID != 0 {
+		attrs = append(attrs, slog.Int64("user_id", userID))
+	}
+
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	logger := util.Logger(ctx)
+
+	if msg, ok := req.(proto.Message); ok && logger.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs, slog.Attr{Key: "request", Value: messageFields(msg.ProtoReflect())})
+	}
+
+	logger.LogAttrs(ctx, rpcLogLevel(code), "grpc request", attrs...)
+
+	return rsp, err
+}
+
+// rpcLogLevel is the level of a call that ended with code, split the way
+// HTTP status classes are.
+func rpcLogLevel(code codes.Code) slog.Level {
+	switch code {
+	case codes.OK:
+		return slog.LevelInfo
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.DeadlineExceeded, codes.Unimplemented:
+		return slog.LevelError
+	default:
+		return slog.LevelWarn
+	}
+}
+
+// messageFields renders the set fields of msg as a log group. Sensitive
+// fields are redacted here as well as by the handler, so a logger made
+// without util.NewLogger cannot leak them either.
+func messageFields(msg protoreflect.Message) slog.Value {
+	var attrs []slog.Attr
+
+	msg.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		name := string(fd.Name())
+
+		switch {
+		case util.IsSensitiveKey(name):
+			attrs = append(attrs, slog.String(name, util.Redacted))
+		case fd.Kind() == protoreflect.MessageKind && !fd.IsList() && !fd.IsMap():
+			attrs = append(attrs, slog.Attr{Key: name, Value: messageFields(v.Message())})
+		default:
+			attrs = append(attrs, slog.String(name, v.String()))
+		}
+
+		return true
+	})
+
+	return slog.GroupValue(attrs...)
+}
+
+// statusRecorder remembers the status an http.Handler answered with.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// HTTPLogger does for the gateway what RequestIDInterceptor and AccessLog
+// do for gRPC. The request id goes on to the gRPC server in X-Request-Id,
+// see GatewayIncomingHeader, so both sides of a call log the same id.
+func HTTPLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		requestID := util.RequestID(r.Header.Get(util.RequestIDHeader))
+		logger := slog.Default().With("request_id", requestID)
+
+		r = r.WithContext(util.WithLogger(util.WithRequestID(r.Context(), requestID), logger))
+		r.Header.Set(util.RequestIDHeader, requestID)
+		w.Header().Set(util.RequestIDHeader, requestID)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("request.id", requestID))
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		clientIP, _, err := net.SplitHostPort(r.RemoteAddr)
+
+		if err != nil {
+			clientIP = r.RemoteAddr
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", recorder.status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", clientIP),
+		}
+
+		if r.URL.RawQuery != "" {
+			attrs = append(attrs, slog.String("query", util.RedactQuery(r.URL.RawQuery)))
+		}
+
+		level := slog.LevelInfo
+
+		switch {
+		case recorder.status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case recorder.status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		logger.LogAttrs(r.Context(), level, "http request", attrs...)
+	})
+}
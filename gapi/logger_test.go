@@ -0,0 +1,98 @@
+package gapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/pb"
+	"github.com/devphasex/cedar-bank-api/token"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+// captureLogs makes the default logger write JSON records to the returned
+// buffer for the rest of the test.
+func captureLogs(t *testing.T, level string) *bytes.Buffer {
+	var buf bytes.Buffer
+
+	logger, err := util.NewLogger(&buf, "json", level)
+	require.NoError(t, err)
+
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	return &buf
+}
+
+func TestAccessLog(t *testing.T) {
+	tokenMaker, err := token.NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	server := &GrpcServer{tokenMaker: tokenMaker}
+	logs := captureLogs(t, "debug")
+
+	accessToken, _, err := tokenMaker.CreateToken(42, "jdoe@mail.com", time.Minute)
+	require.NoError(t, err)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		requestIDHeader, "trace-me",
+		authorizationHeader, "Bearer "+accessToken,
+	))
+	req := &pb.CreateSigninRequest{ID: "jdoe", Password: "hunter2"}
+
+	_, err = RequestIDInterceptor(ctx, req, testMethod, func(ctx context.Context, req any) (any, error) {
+		return server.AccessLog(ctx, req, testMethod, func(ctx context.Context, req any) (any, error) {
+			return ErrorInterceptor(ctx, req, testMethod, func(ctx context.Context, req any) (any, error) {
+				return nil, util.ErrInvalidCredentials
+			})
+		})
+	})
+	require.Error(t, err)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+
+	require.Equal(t, "WARN", record["level"])
+	require.Equal(t, "grpc request", record["msg"])
+	require.Equal(t, "trace-me", record["request_id"])
+	require.Equal(t, testMethod.FullMethod, record["rpc"])
+	require.Equal(t, "Unauthenticated", record["status"])
+	require.EqualValues(t, 42, record["user_id"])
+	require.Equal(t, map[string]any{"ID": "jdoe", "Password": util.Redacted}, record["request"])
+	require.NotContains(t, logs.String(), "hunter2")
+	require.NotContains(t, logs.String(), accessToken)
+}
+
+func TestHTTPLogger(t *testing.T) {
+	logs := captureLogs(t, "info")
+
+	var forwarded string
+
+	handler := HTTPLogger(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get(util.RequestIDHeader)
+		require.Equal(t, forwarded, util.RequestIDFromContext(r.Context()))
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/verify_email?token=leaked", nil))
+
+	require.NotEmpty(t, forwarded)
+	require.Equal(t, forwarded, recorder.Header().Get(util.RequestIDHeader))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &record))
+
+	require.Equal(t, "WARN", record["level"])
+	require.Equal(t, forwarded, record["request_id"])
+	require.EqualValues(t, http.StatusNotFound, record["status"])
+	require.Equal(t, "token=[REDACTED]", record["query"])
+}
@@ -2,6 +2,8 @@ package gapi
 
 import (
 	"context"
+	"net"
+	"strings"
 
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
@@ -30,14 +32,38 @@ func (s *GrpcServer) extraMetadata(ctx context.Context) *Metadata {
 		if userAgents := md.Get(userAgentHeader); len(userAgents) != 0 {
 			mtdata.UserAgent = userAgents[0]
 		}
+	}
+
+	mtdata.ClientIp = clientIP(ctx)
+	return mtdata
+}
+
+// clientIP is the address calls are counted and logged against. Calls
+// relayed by the in-process gateway arrive from loopback and carry the
+// real client as the last x-forwarded-for hop, the one the gateway
+// appended; the hops before it, and the header from anyone else, could be
+// forged, so the peer address is used otherwise.
+func clientIP(ctx context.Context) string {
+	var addr string
+
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
 
-		if clientIps := md.Get(xForwardForHeader); len(clientIps) != 0 {
-			mtdata.ClientIp = clientIps[0]
+		if host, _, err := net.SplitHostPort(addr); err == nil {
+			addr = host
 		}
 	}
 
-	if p, ok := peer.FromContext(ctx); ok {
-		mtdata.ClientIp = p.Addr.String()
+	if ip := net.ParseIP(addr); ip == nil || !ip.IsLoopback() {
+		return addr
 	}
-	return mtdata
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if forwarded := md.Get(xForwardForHeader); len(forwarded) != 0 {
+			hops := forwarded[len(forwarded)-1]
+			return strings.TrimSpace(hops[strings.LastIndex(hops, ",")+1:])
+		}
+	}
+
+	return addr
 }
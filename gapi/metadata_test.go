@@ -0,0 +1,65 @@
+package gapi
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestExtraMetadataClientIP(t *testing.T) {
+	testCases := []struct {
+		name      string
+		peer      string
+		forwarded string
+		clientIP  string
+	}{
+		{
+			name:     "DirectClient",
+			peer:     "203.0.113.7:51234",
+			clientIP: "203.0.113.7",
+		},
+		{
+			name:      "DirectClientForgingForwardedFor",
+			peer:      "203.0.113.7:51234",
+			forwarded: "198.51.100.1",
+			clientIP:  "203.0.113.7",
+		},
+		{
+			name:      "Gateway",
+			peer:      "127.0.0.1:40000",
+			forwarded: "198.51.100.1",
+			clientIP:  "198.51.100.1",
+		},
+		{
+			name:      "GatewayRelayingForgedForwardedFor",
+			peer:      "127.0.0.1:40000",
+			forwarded: "10.0.0.1, 198.51.100.1",
+			clientIP:  "198.51.100.1",
+		},
+		{
+			name:     "GatewayWithoutForwardedFor",
+			peer:     "127.0.0.1:40000",
+			clientIP: "127.0.0.1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			addr, err := net.ResolveTCPAddr("tcp", tc.peer)
+			require.NoError(t, err)
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+
+			if tc.forwarded != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(xForwardForHeader, tc.forwarded))
+			}
+
+			server := &GrpcServer{}
+			require.Equal(t, tc.clientIP, server.extraMetadata(ctx).ClientIp)
+		})
+	}
+}
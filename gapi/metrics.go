@@ -0,0 +1,63 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/metrics"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetricsInterceptor records the rate, errors and duration of calls by
+// method. Like AccessLog it must come before ErrorInterceptor.
+func MetricsInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	rsp, err := handler(ctx, req)
+
+	metrics.ObserveGRPC(info.FullMethod, status.Code(err).String(), time.Since(start))
+
+	return rsp, err
+}
+
+type gatewayRouteKey struct{}
+
+// HTTPMetrics records the rate, errors and duration of gateway requests
+// by the path pattern they matched, which GatewayRoute reports back.
+func HTTPMetrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := new(string)
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(context.WithValue(r.Context(), gatewayRouteKey{}, route)))
+
+		metrics.ObserveHTTP(r.Method, *route, recorder.status, time.Since(start))
+	})
+}
+
+// GatewayRoute tells HTTPMetrics which path pattern the gateway matched,
+// and names the request's span after it. It adds no metadata; the gateway
+// only shows the pattern to annotators. Use it with runtime.WithMetadata.
+func GatewayRoute(ctx context.Context, r *http.Request) metadata.MD {
+	pattern, ok := runtime.HTTPPathPattern(ctx)
+
+	if !ok {
+		return nil
+	}
+
+	if route, ok := r.Context().Value(gatewayRouteKey{}).(*string); ok {
+		*route = pattern
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.SetName(r.Method + " " + pattern)
+	span.SetAttributes(semconv.HTTPRoute(pattern))
+
+	return nil
+}
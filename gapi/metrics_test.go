@@ -0,0 +1,35 @@
+package gapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/devphasex/cedar-bank-api/metrics"
+	"github.com/devphasex/cedar-bank-api/pb"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMetricsRoute(t *testing.T) {
+	mux := runtime.NewServeMux(
+		runtime.WithErrorHandler(GatewayErrorHandler),
+		runtime.WithMetadata(GatewayRoute),
+	)
+	require.NoError(t, pb.RegisterSimpleBankHandlerServer(context.Background(), mux, &pb.UnimplementedSimpleBankServer{}))
+
+	handler := HTTPMetrics(mux)
+
+	for _, path := range []string{"/v1/auth/verify-email?token=abc", "/v1/no/such/path"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body := recorder.Body.String()
+	require.Contains(t, body, `cedar_http_requests_total{method="GET",route="/v1/auth/verify-email",status="404"}`)
+	require.Contains(t, body, `cedar_http_requests_total{method="GET",route="unmatched",status="404"}`)
+	require.NotContains(t, body, "/v1/no/such/path")
+}
@@ -0,0 +1,32 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/devphasex/cedar-bank-api/passwordpolicy"
+)
+
+// checkPasswordPolicy adds a violation of field for every policy rule
+// password breaks for the account of username and email. It only fails
+// when the policy cannot be checked at all.
+func (s *GrpcServer) checkPasswordPolicy(ctx context.Context, v *violations, field, password, username, email string) error {
+	err := s.policy.Check(ctx, password, username, email)
+
+	if err == nil {
+		return nil
+	}
+
+	var policyErr *passwordpolicy.Error
+
+	if !errors.As(err, &policyErr) {
+		return fmt.Errorf("failed to check password: %w", err)
+	}
+
+	for _, violation := range policyErr.Violations {
+		v.add(field, errors.New(violation))
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package gapi
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/devphasex/cedar-bank-api/ratelimit"
+	"github.com/devphasex/cedar-bank-api/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const authorizationHeader = "authorization"
+
+// RateLimit is a unary interceptor applying config.RateLimits to gRPC
+// methods by their full name, such as /pb.SimpleBank/SigninUser. Refused
+// calls fail with util.ErrRateLimited and a retry-after header. Should the
+// limiter fail the call goes through.
+func (s *GrpcServer) RateLimit(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if s.limiter == nil {
+		return handler(ctx, req)
+	}
+
+	rule, ok := s.rateLimits.Match(info.FullMethod)
+
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	var userID int64
+
+	if rule.By == ratelimit.ByUser {
+		userID = s.bearerUserID(ctx)
+	}
+
+	key := rule.Key(clientIP(ctx), userID)
+	result, err := s.limiter.Take(ctx, key, rule)
+
+	if err != nil {
+		util.Logger(ctx).Error("cannot rate limit", "key", key, "error", err)
+		return handler(ctx, req)
+	}
+
+	if !result.Allowed {
+		retryAfter := strconv.FormatFloat(math.Ceil(result.RetryAfter.Seconds()), 'f', 0, 64)
+		_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter))
+
+		return nil, util.ErrRateLimited
+	}
+
+	return handler(ctx, req)
+}
+
+// bearerUserID is the user of the call's bearer token, zero if there is
+// no valid one.
+func (s *GrpcServer) bearerUserID(ctx context.Context) int64 {
+	md, ok := metadata.FromIncomingContext(ctx)
+
+	if !ok {
+		return 0
+	}
+
+	values := md.Get(authorizationHeader)
+
+	if len(values) == 0 {
+		return 0
+	}
+
+	fields := strings.Fields(values[0])
+
+	if len(fields) < 2 || strings.ToLower(fields[0]) != "bearer" {
+		return 0
+	}
+
+	payload, err := s.tokenMaker.VerifyToken(fields[1])
+
+	if err != nil {
+		return 0
+	}
+
+	return payload.UserId
+}
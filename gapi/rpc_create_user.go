@@ -2,32 +2,31 @@ package gapi
 
 import (
 	"context"
+	"fmt"
 
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/mail"
 	"github.com/devphasex/cedar-bank-api/pb"
-	"github.com/devphasex/cedar-bank-api/util/hash"
+	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/jackc/pgx/v5/pgconn"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 func (s *GrpcServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	ag := hash.DefaultArgonHash()
+	if err := s.validateCreateUserRequest(ctx, req); err != nil {
+		return nil, err
+	}
 
-	passwordHash, err := ag.GenerateHash([]byte(req.GetPassword()), nil)
+	passwordHash, err := s.hasher.Hash([]byte(req.GetPassword()))
 
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to hash password: %s", err)
+		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	passwordHashStr, passwordSaltStr := hash.ArgonStringEncode(passwordHash)
-
 	arg := db.CreateUserParams{
 		Username:       req.GetUsername(),
 		Email:          req.GetEmail(),
 		Fullname:       req.GetFullname(),
-		HashedPassword: passwordHashStr,
-		PasswordSalt:   passwordSaltStr,
+		HashedPassword: passwordHash,
 	}
 	user, err := s.store.CreateUser(ctx, arg)
 
@@ -35,15 +34,21 @@ func (s *GrpcServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 		if err, ok := err.(*pgconn.PgError); ok {
 			switch err.ConstraintName {
 			case "users_username_key":
-				return nil, status.Error(codes.AlreadyExists, "username already taken")
+				return nil, util.ErrUsernameTaken
 
 			case "users_email_key":
-				return nil, status.Error(codes.AlreadyExists, "email already taken")
+				return nil, util.ErrEmailTaken
 			}
 
 		}
 
-		return nil, status.Errorf(codes.Internal, "failed to create user: %s", err)
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+
+	// The user can ask for another link, so a failed send does not undo
+	// the sign-up.
+	if err := mail.SendVerifyEmail(ctx, s.store, s.mailer, s.config, user); err != nil {
+		util.Logger(ctx).Error("cannot send verification email", "user_id", user.ID, "error", err)
 	}
 
 	rsp := &pb.CreateUserResponse{
@@ -52,3 +57,19 @@ func (s *GrpcServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest)
 
 	return rsp, nil
 }
+
+func (s *GrpcServer) validateCreateUserRequest(ctx context.Context, req *pb.CreateUserRequest) error {
+	var v violations
+
+	v.add("username", validateLength(req.GetUsername(), 2, 50))
+	v.add("email", validateEmail(req.GetEmail()))
+	v.add("fullname", validateLength(req.GetFullname(), 2, 255))
+
+	if err := validateRequired(req.GetPassword()); err != nil {
+		v.add("password", err)
+	} else if err := s.checkPasswordPolicy(ctx, &v, "password", req.GetPassword(), req.GetUsername(), req.GetEmail()); err != nil {
+		return err
+	}
+
+	return v.err()
+}
@@ -3,23 +3,22 @@ package gapi
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
 	"github.com/devphasex/cedar-bank-api/pb"
+	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/devphasex/cedar-bank-api/util/hash"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-var ErrMismatchCredential = errors.New("Invalid credential email or password mismatch")
-
 func (s *GrpcServer) SigninUser(ctx context.Context, req *pb.CreateSigninRequest) (*pb.CreateSigninResponse, error) {
-
-	ag := hash.DefaultArgonHash()
+	if err := validateSigninRequest(req); err != nil {
+		return nil, err
+	}
 
 	user, err := s.store.GetUserByUniqueID(ctx, db.GetUserByUniqueIDParams{
 		Email: pgtype.Text{
@@ -33,30 +32,109 @@ func (s *GrpcServer) SigninUser(ctx context.Context, req *pb.CreateSigninRequest
 		},
 	})
 
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, status.Error(codes.Unauthenticated, ErrMismatchCredential.Error())
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to sign in: %w", err)
+	}
+
+	found := err == nil
+	mtdata := s.extraMetadata(ctx)
+
+	attempt := db.RecordSigninAttemptTxParams{
+		UserID:     user.ID,
+		Identifier: req.ID,
+		ClientIP:   mtdata.ClientIp,
+		UserAgent:  mtdata.UserAgent,
+		Policy:     db.NewSigninPolicy(s.config),
+	}
+
+	if err = s.checkSigninThrottle(ctx, &attempt); err != nil {
+		return nil, err
+	}
+
+	if found {
+		found = hash.Verify(user.HashedPassword, []byte(req.Password)) == nil
+	}
+
+	if !found {
+		if err = s.recordSigninAttempt(ctx, attempt, db.SigninBadCredentials); err != nil {
+			return nil, err
 		}
 
-		return nil, status.Errorf(codes.Internal, "failed to sign in: %s", err.Error())
+		return nil, util.ErrInvalidCredentials
 	}
 
-	passwordHashByte, passwordSaltByte := hash.ArgonStringDecode(user.HashedPassword, user.PasswordSalt)
+	s.rehashPassword(ctx, user, req.Password)
 
-	if err = ag.Compare(passwordHashByte, passwordSaltByte, []byte(req.Password)); err != nil {
-		return nil, status.Error(codes.Unauthenticated, ErrMismatchCredential.Error())
+	if !user.IsEmailVerified {
+		if err = s.recordSigninAttempt(ctx, attempt, db.SigninUnverified); err != nil {
+			return nil, err
+		}
+
+		return nil, util.ErrEmailNotVerified
 	}
 
+	if user.IsTotpEnabled {
+		if err = s.recordSigninAttempt(ctx, attempt, db.SigninChallenged); err != nil {
+			return nil, err
+		}
+
+		return s.createSigninChallenge(ctx, user)
+	}
+
+	if err = s.recordSigninAttempt(ctx, attempt, db.SigninSucceeded); err != nil {
+		return nil, err
+	}
+
+	return s.createSession(ctx, user)
+}
+
+// validateSigninRequest leaves the password policy out: passwords set
+// before it was tightened must keep working.
+func validateSigninRequest(req *pb.CreateSigninRequest) error {
+	var v violations
+
+	v.add("id", validateRequired(req.GetID()))
+	v.add("password", validateRequired(req.GetPassword()))
+
+	return v.err()
+}
+
+// rehashPassword upgrades the stored hash of a user who just proved their
+// password if it was made by another algorithm than the configured one, or
+// with outdated parameters, like api.Server.rehashPassword.
+func (s *GrpcServer) rehashPassword(ctx context.Context, user db.User, password string) {
+	if !s.hasher.NeedsRehash(user.HashedPassword) {
+		return
+	}
+
+	passwordHash, err := s.hasher.Hash([]byte(password))
+
+	if err == nil {
+		_, err = s.store.RehashUserPassword(ctx, db.RehashUserPasswordParams{
+			ID:                user.ID,
+			HashedPassword:    passwordHash,
+			OldHashedPassword: user.HashedPassword,
+		})
+	}
+
+	if err != nil {
+		util.Logger(ctx).Error("cannot rehash password", "user_id", user.ID, "error", err)
+	}
+}
+
+// createSession issues the access and refresh token pair of a completed
+// sign-in and records the refresh token as a session.
+func (s *GrpcServer) createSession(ctx context.Context, user db.User) (*pb.CreateSigninResponse, error) {
 	accessToken, accessPayload, err := s.tokenMaker.CreateToken(user.ID, user.Email, s.config.AccessTokenTime)
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	refreshToken, refreshPayload, err := s.tokenMaker.CreateToken(user.ID, user.Email, s.config.RefreshTokenTime)
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	mtdata := s.extraMetadata(ctx)
@@ -77,7 +155,7 @@ func (s *GrpcServer) SigninUser(ctx context.Context, req *pb.CreateSigninRequest
 	})
 
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, err
 	}
 
 	rsp := &pb.CreateSigninResponse{
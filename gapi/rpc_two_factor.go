@@ -0,0 +1,66 @@
+package gapi
+
+import (
+	"context"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/pb"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// createSigninChallenge records a password sign-in that still needs its
+// second factor and answers with the challenge instead of tokens.
+func (s *GrpcServer) createSigninChallenge(ctx context.Context, user db.User) (*pb.CreateSigninResponse, error) {
+	token, tokenHash, err := util.NewSecretToken()
+
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.store.CreateSigninChallenge(ctx, db.CreateSigninChallengeParams{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: pgtype.Timestamptz{
+			Time:  time.Now().Add(s.config.SigninChallengeTTL),
+			Valid: true,
+		},
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.CreateSigninResponse{
+		TwoFactorRequired:  true,
+		ChallengeToken:     token,
+		ChallengeExpiredAt: timestamppb.New(challenge.ExpiresAt.Time),
+	}
+
+	return rsp, nil
+}
+
+func (s *GrpcServer) VerifySigninChallenge(ctx context.Context, req *pb.VerifySigninChallengeRequest) (*pb.CreateSigninResponse, error) {
+	var v violations
+
+	v.add("challenge_token", validateRequired(req.GetChallengeToken()))
+	v.add("code", validateRequired(req.GetCode()))
+
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.store.CompleteSigninChallengeTx(ctx, db.CompleteSigninChallengeTxParams{
+		TokenHash: util.HashSecretToken(req.GetChallengeToken()),
+		Code:      req.GetCode(),
+		Policy:    db.NewSigninPolicy(s.config),
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createSession(ctx, *user)
+}
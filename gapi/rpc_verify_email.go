@@ -0,0 +1,77 @@
+package gapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/mail"
+	"github.com/devphasex/cedar-bank-api/pb"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const resendVerifyEmailMessage = "if the address belongs to an unverified account, a new verification email is on its way"
+
+func (s *GrpcServer) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	var v violations
+
+	v.add("token", validateRequired(req.GetToken()))
+
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	user, err := s.store.VerifyEmailTx(ctx, util.HashSecretToken(req.GetToken()))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.VerifyEmailResponse{User: convertDbUser(*user)}, nil
+}
+
+// ResendVerifyEmail answers the same way whether or not the address
+// belongs to an unverified user, and whether or not a new email went out,
+// so it cannot be used to probe for accounts. Reaching the resend limit
+// or failing to send is only logged.
+func (s *GrpcServer) ResendVerifyEmail(ctx context.Context, req *pb.ResendVerifyEmailRequest) (*pb.ResendVerifyEmailResponse, error) {
+	var v violations
+
+	v.add("email", validateEmail(req.GetEmail()))
+
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	rsp := &pb.ResendVerifyEmailResponse{Message: resendVerifyEmailMessage}
+
+	user, err := s.store.GetUserByUniqueID(ctx, db.GetUserByUniqueIDParams{
+		Email: pgtype.Text{
+			String: req.GetEmail(),
+			Valid:  true,
+		},
+	})
+
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return rsp, nil
+		}
+
+		return nil, fmt.Errorf("failed to resend verification email: %w", err)
+	}
+
+	if !user.IsEmailVerified {
+		err = mail.ResendVerifyEmail(ctx, s.store, s.mailer, s.config, user)
+
+		if errors.Is(err, db.ErrVerifyEmailsExceeded) {
+			util.Logger(ctx).Warn("verification email resend limit reached", "user_id", user.ID)
+		} else if err != nil {
+			util.Logger(ctx).Error("cannot send verification email", "user_id", user.ID, "error", err)
+		}
+	}
+
+	return rsp, nil
+}
@@ -2,9 +2,13 @@ package gapi
 
 import (
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/mail"
+	"github.com/devphasex/cedar-bank-api/passwordpolicy"
 	"github.com/devphasex/cedar-bank-api/pb"
+	"github.com/devphasex/cedar-bank-api/ratelimit"
 	"github.com/devphasex/cedar-bank-api/token"
 	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/devphasex/cedar-bank-api/util/hash"
 )
 
 type GrpcServer struct {
@@ -12,6 +16,12 @@ type GrpcServer struct {
 	tokenMaker token.Maker
 	store      db.Store
 	config     *util.Config
+	mailer     mail.Mailer
+	hasher     hash.PasswordHasher
+	policy     *passwordpolicy.Policy
+	limiter    ratelimit.Limiter
+	rateLimits *ratelimit.Rules
+	timeouts   util.RouteTimeouts
 }
 
 func NewGrpcServer(store db.Store, config *util.Config) (*GrpcServer, error) {
@@ -22,10 +32,54 @@ func NewGrpcServer(store db.Store, config *util.Config) (*GrpcServer, error) {
 		return nil, err
 	}
 
+	mailer, err := mail.NewMailer(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hasher, err := hash.NewPasswordHasher(config.PasswordHasher)
+
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := passwordpolicy.NewPolicy(config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rateLimits, err := ratelimit.ParseRules(config.RateLimits)
+
+	if err != nil {
+		return nil, err
+	}
+
+	timeouts, err := util.ParseRouteTimeouts(config.RequestTimeouts)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var limiter ratelimit.Limiter
+
+	if !rateLimits.Empty() {
+		if limiter, err = ratelimit.NewLimiter(config, store); err != nil {
+			return nil, err
+		}
+	}
+
 	server := &GrpcServer{
 		store:      store,
 		config:     config,
 		tokenMaker: tokenMaker,
+		mailer:     mailer,
+		hasher:     hasher,
+		policy:     policy,
+		limiter:    limiter,
+		rateLimits: rateLimits,
+		timeouts:   timeouts,
 	}
 
 	return server, nil
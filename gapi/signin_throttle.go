@@ -0,0 +1,57 @@
+package gapi
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// checkSigninThrottle refuses, with util.ErrSigninThrottled, an attempt from a
+// user or client ip that is still delayed or locked out. The refusal is
+// recorded and the wait is sent back in a retry-after header. An attempt
+// let through is reserved so that parallel guesses are throttled too.
+func (s *GrpcServer) checkSigninThrottle(ctx context.Context, attempt *db.RecordSigninAttemptTxParams) error {
+	retryAt, err := s.store.ReserveSigninAttemptTx(ctx, db.SigninRetryAtParams{
+		UserID:   attempt.UserID,
+		ClientIP: attempt.ClientIP,
+		Policy:   attempt.Policy,
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to sign in: %w", err)
+	}
+
+	if retryAt.IsZero() {
+		attempt.Reserved = true
+		return nil
+	}
+
+	if err = s.recordSigninAttempt(ctx, *attempt, db.SigninThrottled); err != nil {
+		return err
+	}
+
+	retryAfter := strconv.FormatFloat(math.Ceil(time.Until(retryAt).Seconds()), 'f', 0, 64)
+	_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter))
+
+	return util.ErrSigninThrottled
+}
+
+// recordSigninAttempt writes the attempt to the auth audit and counts bad
+// credentials towards a lockout. A sign-in that cannot be recorded fails,
+// otherwise guesses would go uncounted.
+func (s *GrpcServer) recordSigninAttempt(ctx context.Context, attempt db.RecordSigninAttemptTxParams, outcome string) error {
+	attempt.Outcome = outcome
+
+	if _, err := s.store.RecordSigninAttemptTx(ctx, attempt); err != nil {
+		return fmt.Errorf("failed to sign in: %w", err)
+	}
+
+	return nil
+}
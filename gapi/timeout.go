@@ -0,0 +1,25 @@
+package gapi
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// Deadline is a unary interceptor giving every call the deadline
+// config.RequestTimeouts sets for its method, unless the client asked for
+// an earlier one. The store gets the call's context, so queries still
+// running when it passes are cancelled and the call fails with
+// util.ErrDeadlineExceeded.
+func (s *GrpcServer) Deadline(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	timeout := s.timeouts.For(info.FullMethod)
+
+	if timeout <= 0 {
+		return handler(ctx, req)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return handler(ctx, req)
+}
@@ -0,0 +1,42 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestDeadline(t *testing.T) {
+	server := &GrpcServer{timeouts: util.RouteTimeouts{
+		"/pb.SimpleBank/SigninUser": time.Second,
+		util.DefaultRoute:           time.Minute,
+	}}
+
+	deadline := func(ctx context.Context, method string) time.Duration {
+		var remaining time.Duration
+
+		_, err := server.Deadline(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req any) (any, error) {
+			d, ok := ctx.Deadline()
+			require.True(t, ok)
+
+			remaining = time.Until(d)
+			return nil, nil
+		})
+		require.NoError(t, err)
+
+		return remaining
+	}
+
+	require.InDelta(t, time.Second, deadline(context.Background(), "/pb.SimpleBank/SigninUser"), float64(100*time.Millisecond))
+	require.InDelta(t, time.Minute, deadline(context.Background(), "/pb.SimpleBank/CreateUser"), float64(100*time.Millisecond))
+
+	// A client asking for less time keeps its own deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	require.LessOrEqual(t, deadline(ctx, "/pb.SimpleBank/CreateUser"), 100*time.Millisecond)
+}
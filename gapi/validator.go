@@ -0,0 +1,74 @@
+package gapi
+
+import (
+	"fmt"
+	"net/mail"
+	"unicode/utf8"
+
+	"github.com/devphasex/cedar-bank-api/util"
+)
+
+// violations collects what is wrong with a request, field by field, so a
+// client gets every problem at once rather than one per round trip. Fields
+// are named by their JSON names, as gateway clients see them.
+type violations []util.FieldViolation
+
+func (v *violations) add(field string, err error) {
+	if err != nil {
+		*v = append(*v, util.FieldViolation{
+			Field:       field,
+			Description: err.Error(),
+		})
+	}
+}
+
+// err returns nil if nothing was added, and otherwise
+// util.ErrInvalidArgument with the violations, which ErrorInterceptor
+// sends as an errdetails.BadRequest.
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	return util.ErrInvalidArgument.WithViolations(v...)
+}
+
+func validateRequired(value string) error {
+	if value == "" {
+		return fmt.Errorf("is required")
+	}
+
+	return nil
+}
+
+func validateLength(value string, minLength, maxLength int) error {
+	if err := validateRequired(value); err != nil {
+		return err
+	}
+
+	n := utf8.RuneCountInString(value)
+
+	if n < minLength {
+		return fmt.Errorf("must be at least %d characters long", minLength)
+	}
+
+	if n > maxLength {
+		return fmt.Errorf("must be at most %d characters long", maxLength)
+	}
+
+	return nil
+}
+
+func validateEmail(value string) error {
+	if err := validateRequired(value); err != nil {
+		return err
+	}
+
+	addr, err := mail.ParseAddress(value)
+
+	if err != nil || addr.Address != value {
+		return fmt.Errorf("is not a valid email address")
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package gapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/devphasex/cedar-bank-api/passwordpolicy"
+	"github.com/devphasex/cedar-bank-api/pb"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+// requireViolations checks err is InvalidArgument and maps each violated
+// field to how many times it was.
+func requireViolations(t *testing.T, err error, fields map[string]int) {
+	if len(fields) == 0 {
+		require.NoError(t, err)
+		return
+	}
+
+	require.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	customErr, ok := util.AsCustomError(err)
+	require.True(t, ok)
+
+	got := map[string]int{}
+
+	for _, violation := range customErr.Violations() {
+		require.NotEmpty(t, violation.Description)
+		got[violation.Field]++
+	}
+
+	require.Equal(t, fields, got)
+}
+
+func TestValidateCreateUserRequest(t *testing.T) {
+	server := &GrpcServer{policy: &passwordpolicy.Policy{MinLength: 8, MaxLength: 64, MinClasses: 3}}
+
+	testCases := []struct {
+		name   string
+		req    *pb.CreateUserRequest
+		fields map[string]int
+	}{
+		{
+			name: "OK",
+			req:  &pb.CreateUserRequest{Username: "jdoe", Email: "jdoe@mail.com", Fullname: "John Doe", Password: "Blue-Horse-7"},
+		},
+		{
+			name:   "Empty",
+			req:    &pb.CreateUserRequest{},
+			fields: map[string]int{"username": 1, "email": 1, "fullname": 1, "password": 1},
+		},
+		{
+			name:   "InvalidEmail",
+			req:    &pb.CreateUserRequest{Username: "jdoe", Email: "John <jdoe@mail.com>", Fullname: "John Doe", Password: "Blue-Horse-7"},
+			fields: map[string]int{"email": 1},
+		},
+		{
+			name:   "ShortFields",
+			req:    &pb.CreateUserRequest{Username: "j", Email: "jdoe@mail.com", Fullname: "J", Password: "Blue-Horse-7"},
+			fields: map[string]int{"username": 1, "fullname": 1},
+		},
+		{
+			name:   "WeakPassword",
+			req:    &pb.CreateUserRequest{Username: "jdoe", Email: "jdoe@mail.com", Fullname: "John Doe", Password: "x"},
+			fields: map[string]int{"password": 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := server.validateCreateUserRequest(context.Background(), tc.req)
+			requireViolations(t, err, tc.fields)
+		})
+	}
+}
+
+func TestValidateSigninRequest(t *testing.T) {
+	requireViolations(t, validateSigninRequest(&pb.CreateSigninRequest{ID: "jdoe", Password: "x"}), nil)
+	requireViolations(t, validateSigninRequest(&pb.CreateSigninRequest{}), map[string]int{"id": 1, "password": 1})
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Pinger is satisfied by *pgxpool.Pool.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// RowQuerier is satisfied by *pgxpool.Pool.
+type RowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Database checks a connection can be acquired from pool and used.
+func Database(pool Pinger) Check {
+	return func(ctx context.Context) error {
+		if err := pool.Ping(ctx); err != nil {
+			return fmt.Errorf("cannot reach database: %w", err)
+		}
+
+		return nil
+	}
+}
+
+// Migrations checks the schema is at least at version, as recorded by
+// golang-migrate, and that no migration was left half applied. A newer
+// schema passes: during a rolling deploy the new release migrates while
+// instances of the old one are still serving.
+func Migrations(db RowQuerier, version uint) Check {
+	return func(ctx context.Context) error {
+		var current uint
+		var dirty bool
+
+		err := db.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&current, &dirty)
+
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return errors.New("no migrations applied")
+		case err != nil:
+			return fmt.Errorf("cannot read schema version: %w", err)
+		case dirty:
+			return fmt.Errorf("migration %d did not finish", current)
+		case current < version:
+			return fmt.Errorf("schema is at version %d, expected at least %d", current, version)
+		}
+
+		return nil
+	}
+}
+
+// GRPCBackend checks the gRPC server behind conn reports service as
+// serving through the grpc.health.v1 service.
+func GRPCBackend(conn grpc.ClientConnInterface, service string) Check {
+	client := healthpb.NewHealthClient(conn)
+
+	return func(ctx context.Context) error {
+		rsp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+
+		if err != nil {
+			return fmt.Errorf("cannot reach gRPC server: %w", err)
+		}
+
+		if rsp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("gRPC server is %s", rsp.GetStatus())
+		}
+
+		return nil
+	}
+}
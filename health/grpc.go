@@ -0,0 +1,50 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Watch runs checks every interval until ctx is done and reports services
+// on server, a grpc.health.v1 service, as serving while all of them pass.
+// The empty service name stands for the server as a whole. Once ctx is
+// done every service is reported as not serving, so clients stop sending
+// calls to a server that is going away.
+func Watch(ctx context.Context, server *grpchealth.Server, services []string, interval time.Duration, checks map[string]Check) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	serving := healthpb.HealthCheckResponse_UNKNOWN
+
+	for {
+		results, ok := Run(ctx, checks)
+		status := healthpb.HealthCheckResponse_SERVING
+
+		if !ok {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+
+		if status != serving && ctx.Err() == nil {
+			if !ok {
+				util.Logger(ctx).Warn("gRPC server is not serving", "checks", results)
+			}
+
+			for _, service := range services {
+				server.SetServingStatus(service, status)
+			}
+
+			serving = status
+		}
+
+		select {
+		case <-ctx.Done():
+			server.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
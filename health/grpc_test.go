@@ -0,0 +1,73 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestWatch(t *testing.T) {
+	const service = "pb.SimpleBank"
+
+	ln := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+
+	go server.Serve(ln)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	var healthy atomic.Bool
+	healthy.Store(true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		Watch(ctx, healthServer, []string{"", service}, 10*time.Millisecond, map[string]Check{
+			"database": func(context.Context) error {
+				if healthy.Load() {
+					return nil
+				}
+
+				return errors.New("connection refused")
+			},
+		})
+	}()
+
+	backend := GRPCBackend(conn, service)
+
+	require.Eventually(t, func() bool { return backend(context.Background()) == nil }, time.Second, 10*time.Millisecond)
+
+	healthy.Store(false)
+	require.Eventually(t, func() bool { return backend(context.Background()) != nil }, time.Second, 10*time.Millisecond)
+
+	healthy.Store(true)
+	require.Eventually(t, func() bool { return backend(context.Background()) == nil }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+
+	rsp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, rsp.GetStatus())
+}
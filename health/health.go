@@ -0,0 +1,111 @@
+// Package health tells an orchestrator whether the service is alive and
+// whether it is ready to take traffic.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+)
+
+// checkTimeout bounds each readiness check, so a hung dependency makes
+// the service unready instead of hanging the probe.
+const checkTimeout = 2 * time.Second
+
+// Check reports why a dependency cannot be used, or nil if it can.
+type Check func(ctx context.Context) error
+
+// Response is the body of both endpoints. Checks maps each readiness check
+// to "ok" or "failed".
+type Response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// LivenessHandler serves /healthz. It answers as long as the process can
+// serve HTTP at all and checks nothing else, so a database outage makes
+// the service unready rather than getting it restarted.
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, r, http.StatusOK, Response{Status: "ok"})
+	})
+}
+
+// ReadinessHandler serves /readyz. It runs checks concurrently and answers
+// 200 if all passed, 503 otherwise, with the outcome of each. The probe is
+// served to anyone, so why a check failed is logged rather than answered.
+func ReadinessHandler(checks map[string]Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		results, ok := Run(r.Context(), checks)
+		rsp := Response{Status: "ok", Checks: make(map[string]string, len(results))}
+		status := http.StatusOK
+
+		for name, result := range results {
+			if result != "ok" {
+				result = "failed"
+			}
+
+			rsp.Checks[name] = result
+		}
+
+		if !ok {
+			util.Logger(r.Context()).Warn("service is not ready", "checks", results)
+			rsp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		}
+
+		writeResponse(w, r, status, rsp)
+	})
+}
+
+// Run runs checks concurrently and returns the outcome of each, and
+// whether all of them passed.
+func Run(ctx context.Context, checks map[string]Check) (map[string]string, bool) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]string, len(checks))
+		ok      = true
+	)
+
+	for name, check := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+			defer cancel()
+
+			err := check(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				results[name] = err.Error()
+				ok = false
+			} else {
+				results[name] = "ok"
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results, ok
+}
+
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, rsp Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(rsp); err != nil {
+		util.Logger(r.Context()).Error("cannot write health response", "error", err)
+	}
+}
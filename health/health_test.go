@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLivenessHandler(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	LivenessHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{"status":"ok"}`, recorder.Body.String())
+}
+
+func TestReadinessHandler(t *testing.T) {
+	pass := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("connection refused") }
+	hang := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	testCases := []struct {
+		name   string
+		checks map[string]Check
+		status int
+		rsp    Response
+	}{
+		{
+			name:   "Ready",
+			checks: map[string]Check{"database": pass, "grpc": pass},
+			status: http.StatusOK,
+			rsp:    Response{Status: "ok", Checks: map[string]string{"database": "ok", "grpc": "ok"}},
+		},
+		{
+			name:   "CheckFailed",
+			checks: map[string]Check{"database": fail, "grpc": pass},
+			status: http.StatusServiceUnavailable,
+			rsp:    Response{Status: "unavailable", Checks: map[string]string{"database": "failed", "grpc": "ok"}},
+		},
+		{
+			name:   "CheckTimedOut",
+			checks: map[string]Check{"database": hang},
+			status: http.StatusServiceUnavailable,
+			rsp:    Response{Status: "unavailable", Checks: map[string]string{"database": "failed"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			ReadinessHandler(tc.checks).ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			require.Equal(t, tc.status, recorder.Code)
+
+			var rsp Response
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.Equal(t, tc.rsp, rsp)
+
+			// Why a check failed is only logged.
+			require.NotContains(t, recorder.Body.String(), "connection refused")
+			require.NotContains(t, recorder.Body.String(), context.DeadlineExceeded.Error())
+		})
+	}
+}
+
+type fakeRow struct {
+	version uint
+	dirty   bool
+	err     error
+}
+
+func (r fakeRow) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	*dest[0].(*uint) = r.version
+	*dest[1].(*bool) = r.dirty
+
+	return nil
+}
+
+type fakeQuerier struct {
+	row fakeRow
+}
+
+func (q fakeQuerier) QueryRow(context.Context, string, ...any) pgx.Row {
+	return q.row
+}
+
+func TestMigrations(t *testing.T) {
+	testCases := []struct {
+		name string
+		row  fakeRow
+		ok   bool
+	}{
+		{"Current", fakeRow{version: 17}, true},
+		{"Ahead", fakeRow{version: 18}, true},
+		{"Behind", fakeRow{version: 16}, false},
+		{"Dirty", fakeRow{version: 17, dirty: true}, false},
+		{"DirtyAhead", fakeRow{version: 18, dirty: true}, false},
+		{"NoneApplied", fakeRow{err: pgx.ErrNoRows}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Migrations(fakeQuerier{tc.row}, 17)(context.Background())
+
+			if tc.ok {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package mail
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryMailer(t *testing.T) {
+	m := NewMemoryMailer()
+
+	msg := Message{To: util.RandomEmail(), Subject: "hello", Body: "body"}
+	require.NoError(t, m.Send(context.Background(), msg))
+
+	require.Equal(t, []Message{msg}, m.Messages())
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+
+	m, err := NewFileMailer(dir, "Cedar Bank <no-reply@cedar-bank.local>")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, m.Send(context.Background(), Message{To: "jane@mail.com", Subject: "Héllo", Body: "body"}))
+	}
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	b, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+
+	eml := string(b)
+	require.Contains(t, eml, "From: Cedar Bank <no-reply@cedar-bank.local>\r\n")
+	require.Contains(t, eml, "To: jane@mail.com\r\n")
+	require.Contains(t, eml, "Subject: =?utf-8?q?H=C3=A9llo?=\r\n")
+	require.True(t, strings.HasSuffix(eml, "\r\n\r\nbody"))
+}
+
+// serveSMTP answers one session on ln like a server that accepts any
+// mail, and sends the message data it got on the returned channel.
+func serveSMTP(ln net.Listener) <-chan string {
+	data := make(chan string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := textproto.NewConn(conn)
+		_ = r.PrintfLine("220 localhost ready")
+
+		for {
+			line, err := r.ReadLine()
+			if err != nil {
+				return
+			}
+
+			switch verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); verb {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				_ = r.PrintfLine("250 ok")
+			case "DATA":
+				_ = r.PrintfLine("354 go ahead")
+				b, _ := r.ReadDotBytes()
+				data <- string(b)
+				_ = r.PrintfLine("250 queued")
+			case "QUIT":
+				_ = r.PrintfLine("221 bye")
+				return
+			default:
+				_ = r.PrintfLine("502 %s not implemented", verb)
+			}
+		}
+	}()
+
+	return data
+}
+
+func TestSMTPMailer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	data := serveSMTP(ln)
+
+	addr := ln.Addr().(*net.TCPAddr)
+	m := NewSMTPMailer("127.0.0.1", addr.Port, "", "", "Cedar Bank <no-reply@cedar-bank.local>")
+
+	require.NoError(t, m.Send(context.Background(), Message{To: "jane@mail.com", Subject: "hello", Body: "body"}))
+	require.Contains(t, <-data, "To: jane@mail.com\n")
+}
+
+func TestSMTPMailerStalledServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	hungUp := make(chan struct{})
+
+	// Takes the connection but never greets.
+	go func() {
+		defer close(hungUp)
+
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			_, _ = io.Copy(io.Discard, conn)
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	m := NewSMTPMailer("127.0.0.1", addr.Port, "", "", "no-reply@cedar-bank.local")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = m.Send(ctx, Message{To: "jane@mail.com", Subject: "hello", Body: "body"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(start), time.Second)
+
+	// Nothing is left waiting on the server.
+	select {
+	case <-hungUp:
+	case <-time.After(time.Second):
+		t.Fatal("connection still open after Send returned")
+	}
+}
+
+func TestNewMailer(t *testing.T) {
+	m, err := NewMailer(&util.Config{Mailer: "memory"})
+	require.NoError(t, err)
+	require.IsType(t, &MemoryMailer{}, m)
+
+	m, err = NewMailer(&util.Config{Mailer: "smtp", SMTPHost: "localhost", SMTPPort: 25})
+	require.NoError(t, err)
+	require.IsType(t, &SMTPMailer{}, m)
+
+	_, err = NewMailer(&util.Config{Mailer: "pigeon"})
+	require.Error(t, err)
+}
+
+func TestVerifyEmailMessage(t *testing.T) {
+	msg, err := VerifyEmailMessage("jane@mail.com", "Jane Doe", "http://localhost:8080/auth/verify-email?lang=en", "abc_123", 24*time.Hour)
+	require.NoError(t, err)
+
+	require.Equal(t, "jane@mail.com", msg.To)
+	require.Contains(t, msg.Body, "Hello Jane Doe,")
+	require.Contains(t, msg.Body, "expires in 24 hours")
+
+	var link string
+	for _, line := range strings.Split(msg.Body, "\n") {
+		if strings.HasPrefix(line, "http") {
+			link = line
+		}
+	}
+
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	require.Equal(t, "/auth/verify-email", u.Path)
+	require.Equal(t, "abc_123", u.Query().Get("token"))
+	require.Equal(t, "en", u.Query().Get("lang"))
+}
+
+func TestPasswordResetMessage(t *testing.T) {
+	msg, err := PasswordResetMessage("jane@mail.com", "Jane Doe", "http://localhost:8080/auth/password/reset", "abc_123", 30*time.Minute)
+	require.NoError(t, err)
+
+	require.Equal(t, "jane@mail.com", msg.To)
+	require.Equal(t, "Reset your password", msg.Subject)
+	require.Contains(t, msg.Body, "expires in 30 minutes")
+	require.Contains(t, msg.Body, "http://localhost:8080/auth/password/reset?token=abc_123")
+}
@@ -0,0 +1,94 @@
+package mail
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// VerifyEmailMessage builds the mail asking a new user to confirm their
+// address. The token is appended to link as the token query parameter.
+func VerifyEmailMessage(to, fullname, link, token string, ttl time.Duration) (Message, error) {
+	u, err := tokenLink(link, token)
+
+	if err != nil {
+		return Message{}, err
+	}
+
+	body := fmt.Sprintf(`Hello %s,
+
+Thank you for signing up with Cedar Bank. Please confirm your email address
+by opening the link below:
+
+%s
+
+The link expires in %s. If you did not create an account, you can ignore
+this message.
+`, fullname, u.String(), humanDuration(ttl))
+
+	return Message{
+		To:      to,
+		Subject: "Verify your email address",
+		Body:    body,
+	}, nil
+}
+
+// PasswordResetMessage builds the mail carrying a password reset link,
+// with the token appended to link like VerifyEmailMessage.
+func PasswordResetMessage(to, fullname, link, token string, ttl time.Duration) (Message, error) {
+	u, err := tokenLink(link, token)
+
+	if err != nil {
+		return Message{}, err
+	}
+
+	body := fmt.Sprintf(`Hello %s,
+
+We received a request to reset the password of your Cedar Bank account.
+Open the link below to choose a new one:
+
+%s
+
+The link expires in %s and can be used once. If you did not ask for a
+reset, you can ignore this message; your password stays unchanged.
+`, fullname, u.String(), humanDuration(ttl))
+
+	return Message{
+		To:      to,
+		Subject: "Reset your password",
+		Body:    body,
+	}, nil
+}
+
+func tokenLink(link, token string) (*url.URL, error) {
+	u, err := url.Parse(link)
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+
+	return u, nil
+}
+
+func humanDuration(d time.Duration) string {
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return plural(int(d/time.Hour), "hour")
+	case d >= time.Minute:
+		return plural(int(d/time.Minute), "minute")
+	}
+
+	return d.String()
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+
+	return fmt.Sprintf("%d %ss", n, unit)
+}
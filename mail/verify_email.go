@@ -0,0 +1,79 @@
+package mail
+
+import (
+	"context"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// VerifyEmailStore is the part of the store SendVerifyEmail records
+// tokens in.
+type VerifyEmailStore interface {
+	CreateVerifyEmail(ctx context.Context, arg db.CreateVerifyEmailParams) (db.VerifyEmail, error)
+}
+
+// ResendVerifyEmailStore is the part of the store ResendVerifyEmail
+// records tokens in.
+type ResendVerifyEmailStore interface {
+	ResendVerifyEmailTx(ctx context.Context, arg db.ResendVerifyEmailTxParams) (*db.VerifyEmail, error)
+}
+
+// SendVerifyEmail issues a fresh verification token for user and mails
+// the link, valid for config.EmailVerifyTTL. Only the token hash is
+// stored.
+func SendVerifyEmail(ctx context.Context, store VerifyEmailStore, mailer Mailer, config *util.Config, user db.User) error {
+	return sendVerifyEmail(ctx, mailer, config, user, func(tokenHash string, expiresAt time.Time) error {
+		_, err := store.CreateVerifyEmail(ctx, db.CreateVerifyEmailParams{
+			UserID:    user.ID,
+			Email:     user.Email,
+			TokenHash: tokenHash,
+			ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+		})
+
+		return err
+	})
+}
+
+// ResendVerifyEmail is SendVerifyEmail for a user asking again. It fails
+// with db.ErrVerifyEmailsExceeded, mailing nothing, once
+// config.EmailVerifyResendLimit tokens were issued to the user within
+// config.EmailVerifyResendWindow.
+func ResendVerifyEmail(ctx context.Context, store ResendVerifyEmailStore, mailer Mailer, config *util.Config, user db.User) error {
+	return sendVerifyEmail(ctx, mailer, config, user, func(tokenHash string, expiresAt time.Time) error {
+		_, err := store.ResendVerifyEmailTx(ctx, db.ResendVerifyEmailTxParams{
+			UserID:    user.ID,
+			Email:     user.Email,
+			TokenHash: tokenHash,
+			ExpiresAt: expiresAt,
+			Limit:     config.EmailVerifyResendLimit,
+			Since:     time.Now().Add(-config.EmailVerifyResendWindow),
+		})
+
+		return err
+	})
+}
+
+// sendVerifyEmail mails user a fresh verification link once record has
+// stored the hash of its token.
+func sendVerifyEmail(ctx context.Context, mailer Mailer, config *util.Config, user db.User, record func(tokenHash string, expiresAt time.Time) error) error {
+	token, tokenHash, err := util.NewSecretToken()
+
+	if err != nil {
+		return err
+	}
+
+	if err = record(tokenHash, time.Now().Add(config.EmailVerifyTTL)); err != nil {
+		return err
+	}
+
+	msg, err := VerifyEmailMessage(user.Email, user.Fullname, config.EmailVerifyURL, token, config.EmailVerifyTTL)
+
+	if err != nil {
+		return err
+	}
+
+	return mailer.Send(ctx, msg)
+}
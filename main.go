@@ -2,24 +2,24 @@ package main
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"net"
-	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
 	"github.com/devphasex/cedar-bank-api/api"
+	"github.com/devphasex/cedar-bank-api/db/migrations"
 	db "github.com/devphasex/cedar-bank-api/db/sqlc"
 	_ "github.com/devphasex/cedar-bank-api/doc/statik"
-	"github.com/devphasex/cedar-bank-api/gapi"
-	"github.com/devphasex/cedar-bank-api/pb"
+	"github.com/devphasex/cedar-bank-api/health"
+	"github.com/devphasex/cedar-bank-api/metrics"
+	"github.com/devphasex/cedar-bank-api/tracing"
 	"github.com/devphasex/cedar-bank-api/util"
-	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/devphasex/cedar-bank-api/worker"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/rakyll/statik/fs"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/reflection"
-	"google.golang.org/protobuf/encoding/protojson"
 )
 
 func main() {
@@ -28,123 +28,122 @@ func main() {
 	config, err := util.LoadConfig(".")
 
 	if err != nil {
-		log.Fatal(err)
+		fatal("cannot load config", err)
 	}
 
-	pgConfig, err := pgxpool.ParseConfig(config.DbSource)
+	logger, err := util.NewLogger(os.Stdout, config.LogFormat, config.LogLevel)
+
 	if err != nil {
-		log.Fatalf("Unable to parse connection string: %v", err)
+		fatal("cannot create logger", err)
 	}
 
-	// Set some reasonable pool limits
-	pgConfig.MaxConns = 20
-	pgConfig.MinConns = 2
-	pgConfig.MaxConnLifetime = time.Hour
-	pgConfig.MaxConnIdleTime = 30 * time.Minute
+	// Also routes the standard log package, and with it gin's debug
+	// output, through the JSON handler.
+	slog.SetDefault(logger)
 
-	// Set some reasonable timeouts
-	pgConfig.ConnConfig.ConnectTimeout = 5 * time.Second
-	pgConfig.ConnConfig.RuntimeParams["statement_timeout"] = "30000" // 30 seconds
+	shutdownTracing, err := tracing.Setup(context.Background(), config)
 
-	conn, err := pgxpool.NewWithConfig(context.Background(), pgConfig)
 	if err != nil {
-		log.Fatalf("connection to db failed: %v", err)
+		fatal("cannot set up tracing", err)
 	}
-	defer conn.Close()
 
-	store := db.NewStore(conn)
-	go runGrpcServer(store, config)
-	runGrpcGatewayServer(store, config)
-}
-
-func runGinServer(store db.Store, config *util.Config) {
-	server, err := api.NewServer(store, config)
+	defer shutdownTracing(context.Background())
 
+	pgConfig, err := pgxpool.ParseConfig(config.DbSource)
 	if err != nil {
-		log.Fatal(err)
+		fatal("cannot parse connection string", err)
 	}
 
-	if err := server.Start(config.HttpServerAddress); err != nil {
-		log.Fatal(err)
-	}
-}
+	pgConfig.MaxConns = config.DbMaxConns
+	pgConfig.MinConns = config.DbMinConns
+	pgConfig.MaxConnLifetime = config.DbMaxConnLifetime
+	pgConfig.MaxConnIdleTime = config.DbMaxConnIdleTime
 
-func runGrpcServer(store db.Store, config *util.Config) {
-	grpcServer := grpc.NewServer([]grpc.ServerOption{}...)
-	server, err := gapi.NewGrpcServer(store, config)
+	pgConfig.ConnConfig.ConnectTimeout = config.DbConnectTimeout
+	pgConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(config.DbStatementTimeout.Milliseconds(), 10)
 
+	pgConfig.ConnConfig.Tracer = tracing.NewQueryTracer()
+
+	conn, err := pgxpool.NewWithConfig(context.Background(), pgConfig)
 	if err != nil {
-		log.Fatal("cannot create server:", err)
+		fatal("connection to db failed", err)
 	}
+	// Closed once the servers and workers below have stopped using it.
+	defer conn.Close()
+
+	metrics.Registry.MustRegister(metrics.NewPoolCollector(conn))
 
-	pb.RegisterSimpleBankServer(grpcServer, server)
-	reflection.Register(grpcServer)
+	store := metrics.InstrumentStore(db.NewStore(conn))
+
+	checks := map[string]health.Check{
+		"database":   health.Database(conn),
+		"migrations": health.Migrations(conn, migrations.LatestVersion()),
+	}
 
-	ln, err := net.Listen("tcp", config.GrpcServerAddress)
+	// SIGTERM is what orchestrators send; SIGINT is Ctrl-C.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	defer ln.Close()
+	srv, err := newServers(ctx, store, config, checks)
 
 	if err != nil {
-		log.Fatal("cannot create listerner:", err)
+		fatal("cannot create servers", err)
 	}
 
-	log.Printf("start gRPC server at %s", ln.Addr().String())
-	err = grpcServer.Serve(ln)
-
+	grpcLn, err := net.Listen("tcp", config.GrpcServerAddress)
 	if err != nil {
-		log.Fatal("cannot start gRPC server:", err)
+		fatal("cannot create listener", err)
 	}
-}
 
-func runGrpcGatewayServer(store db.Store, config *util.Config) {
-	var err error
-	grpcMux := runtime.NewServeMux(
-		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
-			MarshalOptions: protojson.MarshalOptions{
-				UseProtoNames:   true,
-				EmitUnpopulated: true,
-			},
-			UnmarshalOptions: protojson.UnmarshalOptions{
-				DiscardUnknown: true,
-			},
-		}),
-		runtime.WithIncomingHeaderMatcher(runtime.DefaultHeaderMatcher),
-	)
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	// Assuming your gRPC server is running on a different port, e.g., ":9090"
-	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())} // gRPC connection options
-
-	err = pb.RegisterSimpleBankHandlerFromEndpoint(ctx, grpcMux, config.GrpcServerAddress, opts)
+	httpLn, err := net.Listen("tcp", config.HttpServerAddress)
 	if err != nil {
-		log.Fatal("cannot register handler from endpoint:", err)
+		fatal("cannot create listener", err)
 	}
 
-	mux := http.NewServeMux()
-	mux.Handle("/", grpcMux)
+	var metricsLn net.Listener
 
-	statikFs, err := fs.New()
+	if config.MetricsServerAddress != "" {
+		if metricsLn, err = net.Listen("tcp", config.MetricsServerAddress); err != nil {
+			fatal("cannot create listener", err)
+		}
+	}
 
-	if err != nil {
-		log.Fatal("cannot create statik fs:", err)
+	srv.workers = runWorkers(ctx, store, config)
+
+	if err := srv.serve(ctx, grpcLn, httpLn, metricsLn, config.ShutdownTimeout); err != nil {
+		slog.Error("shutdown incomplete", "error", err)
 	}
 
-	swaggerHandler := http.StripPrefix("/swagger/", http.FileServer(statikFs))
-	mux.Handle("/swagger/", swaggerHandler)
+	slog.Info("shut down")
+}
 
-	// Add CORS middleware if necessary
-	// handler := cors.Default().Handler(mux)
+func runWorkers(ctx context.Context, store db.Store, config *util.Config) *worker.Scheduler {
+	scheduler := worker.NewScheduler()
+	scheduler.Every(config.HoldExpiryPeriod, worker.NewExpireHoldsJob(store))
+	scheduler.Every(config.OverdraftAccrualPeriod, worker.NewOverdraftAccrualJob(store, config.OverdraftAnnualRate, config.OverdraftDailyFee))
+	scheduler.Every(config.InterestAccrualPeriod, worker.NewInterestAccrualJob(store))
 
-	ln, err := net.Listen("tcp", config.HttpServerAddress)
-	if err != nil {
-		log.Fatal("cannot create listener: ", err)
+	if config.RateLimiter == "postgres" {
+		scheduler.Every(time.Hour, worker.NewSweepRateLimitsJob(store))
 	}
-	defer ln.Close()
+	scheduler.Start(ctx)
+
+	return scheduler
+}
+
+func runGinServer(store db.Store, config *util.Config) {
+	server, err := api.NewServer(store, config)
 
-	log.Printf("starting HTTP server at %s", ln.Addr().String())
-	err = http.Serve(ln, mux)
 	if err != nil {
-		log.Fatal("cannot start HTTP server:", err)
+		fatal("cannot create server", err)
 	}
+
+	if err := server.Start(config.HttpServerAddress); err != nil {
+		fatal("cannot start HTTP server", err)
+	}
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
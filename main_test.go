@@ -0,0 +1,521 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/certs"
+	"github.com/devphasex/cedar-bank-api/certs/certstest"
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/health"
+	"github.com/devphasex/cedar-bank-api/pb"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func newTestConfig(grpcAddress string) *util.Config {
+	return &util.Config{
+		GrpcServerAddress:   grpcAddress,
+		SymmetricKey:        util.RandomString(32),
+		Mailer:              "memory",
+		EmailVerifyTTL:      time.Hour,
+		PasswordHasher:      "argon2id",
+		PasswordMinLength:   8,
+		PasswordMaxLength:   64,
+		PasswordMinClasses:  1,
+		HealthCheckInterval: time.Second,
+		GrpcMaxRecvMsgSize:  4 << 20,
+		GrpcMaxSendMsgSize:  4 << 20,
+	}
+}
+
+func TestShutdownCompletesInFlightRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	user := db.User{ID: 1, Username: util.RandomOwner(), Email: util.RandomEmail(), IsEmailVerified: true}
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	store.EXPECT().
+		VerifyEmailTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, _ string) (*db.User, error) {
+			close(entered)
+			<-release
+
+			// Shutting down must not cancel the calls it waits for.
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			return &user, nil
+		})
+
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	httpLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv, err := newServers(ctx, store, newTestConfig(grpcLn.Addr().String()), map[string]health.Check{})
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- srv.serve(ctx, grpcLn, httpLn, nil, 5*time.Second)
+	}()
+
+	baseURL := "http://" + httpLn.Addr().String()
+
+	type result struct {
+		rsp *http.Response
+		err error
+	}
+
+	inFlight := make(chan result, 1)
+
+	go func() {
+		rsp, err := http.Get(baseURL + "/v1/auth/verify-email?token=abc")
+		inFlight <- result{rsp, err}
+	}()
+
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request never reached the store")
+	}
+
+	// What SIGTERM does.
+	cancel()
+
+	// New connections are refused while the request is still running.
+	require.Eventually(t, func() bool {
+		_, err := net.DialTimeout("tcp", httpLn.Addr().String(), 100*time.Millisecond)
+		return err != nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	select {
+	case err := <-served:
+		t.Fatalf("servers stopped before the request finished: %v", err)
+	default:
+	}
+
+	close(release)
+
+	res := <-inFlight
+	require.NoError(t, res.err)
+	defer res.rsp.Body.Close()
+
+	require.Equal(t, http.StatusOK, res.rsp.StatusCode)
+
+	var body struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+	}
+	require.NoError(t, json.NewDecoder(res.rsp.Body).Decode(&body))
+	require.Equal(t, user.Username, body.User.Username)
+
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("servers did not shut down")
+	}
+}
+
+func TestRequestDeadlineReachesStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		VerifyEmailTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, _ string) (*db.User, error) {
+			// What a query does once the context of the call ends.
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	httpLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	config := newTestConfig(grpcLn.Addr().String())
+	config.RequestTimeouts = "/pb.SimpleBank/VerifyEmail=50ms"
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	srv, err := newServers(ctx, store, config, map[string]health.Check{})
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- srv.serve(ctx, grpcLn, httpLn, nil, 5*time.Second)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-served)
+	})
+
+	rsp, err := http.Get("http://" + httpLn.Addr().String() + "/v1/auth/verify-email?token=abc")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+
+	require.Equal(t, http.StatusGatewayTimeout, rsp.StatusCode)
+
+	var body util.ErrorResponse
+	require.NoError(t, json.NewDecoder(rsp.Body).Decode(&body))
+	require.Equal(t, util.ErrDeadlineExceeded.Code(), body.Error.Code)
+}
+
+// startTestServers serves config on grpcLn and a loopback HTTP listener
+// until the test ends, and returns the HTTP address.
+func startTestServers(t *testing.T, store db.Store, grpcLn net.Listener, config *util.Config) string {
+	httpLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	srv, err := newServers(ctx, store, config, map[string]health.Check{})
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- srv.serve(ctx, grpcLn, httpLn, nil, 5*time.Second)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		require.NoError(t, <-served)
+	})
+
+	return httpLn.Addr().String()
+}
+
+// requireServing checks the gRPC health service at address reports the
+// server as serving.
+func requireServing(t *testing.T, address string, creds credentials.TransportCredentials) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+
+	require.Eventually(t, func() bool {
+		rsp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		return err == nil && rsp.Status == healthpb.HealthCheckResponse_SERVING
+	}, 5*time.Second, 20*time.Millisecond)
+}
+
+func TestMetricsServedApart(t *testing.T) {
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	httpLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	metricsLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	srv, err := newServers(ctx, mockdb.NewMockStore(gomock.NewController(t)), newTestConfig(grpcLn.Addr().String()), map[string]health.Check{})
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- srv.serve(ctx, grpcLn, httpLn, metricsLn, 5*time.Second)
+	}()
+
+	defer func() {
+		cancel()
+		require.NoError(t, <-served)
+	}()
+
+	rsp, err := http.Get("http://" + metricsLn.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	rsp.Body.Close()
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	rsp, err = http.Get("http://" + httpLn.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	rsp.Body.Close()
+	require.Equal(t, http.StatusNotFound, rsp.StatusCode)
+}
+
+func TestZeroHealthCheckInterval(t *testing.T) {
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	config := newTestConfig(grpcLn.Addr().String())
+	config.HealthCheckInterval = 0
+
+	startTestServers(t, mockdb.NewMockStore(gomock.NewController(t)), grpcLn, config)
+
+	requireServing(t, grpcLn.Addr().String(), insecure.NewCredentials())
+}
+
+func TestSinglePortPlaintext(t *testing.T) {
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	config := newTestConfig(grpcLn.Addr().String())
+	config.SinglePort = true
+
+	httpAddress := startTestServers(t, mockdb.NewMockStore(gomock.NewController(t)), grpcLn, config)
+
+	// gRPC over h2c on the HTTP port.
+	requireServing(t, httpAddress, insecure.NewCredentials())
+
+	rsp, err := http.Get("http://" + httpAddress + "/healthz")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+}
+
+func TestSinglePortTLS(t *testing.T) {
+	ca := certstest.NewCA(t, "cedar test CA")
+	dir := t.TempDir()
+
+	serverCert, serverKey := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	ca.Issue(t, "127.0.0.1", serverCert, serverKey)
+
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	config := newTestConfig(grpcLn.Addr().String())
+	config.TLSCertFile, config.TLSKeyFile = serverCert, serverKey
+	config.SinglePort = true
+
+	httpAddress := startTestServers(t, mockdb.NewMockStore(gomock.NewController(t)), grpcLn, config)
+
+	anonymous, err := certs.NewReloader("", "", ca.CertFile)
+	require.NoError(t, err)
+	defer anonymous.Close()
+
+	// gRPC over HTTP/2 negotiated with ALPN on the HTTP port.
+	requireServing(t, httpAddress, credentials.NewTLS(anonymous.ClientConfig("127.0.0.1")))
+}
+
+func TestMutualTLSBetweenGatewayAndGrpc(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	user := db.User{ID: 1, Username: util.RandomOwner(), Email: util.RandomEmail(), IsEmailVerified: true}
+
+	store.EXPECT().
+		VerifyEmailTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(&user, nil)
+
+	ca := certstest.NewCA(t, "cedar test CA")
+	dir := t.TempDir()
+
+	serverCert, serverKey := filepath.Join(dir, "server.pem"), filepath.Join(dir, "server-key.pem")
+	gatewayCert, gatewayKey := filepath.Join(dir, "gateway.pem"), filepath.Join(dir, "gateway-key.pem")
+
+	ca.Issue(t, "127.0.0.1", serverCert, serverKey)
+	ca.Issue(t, "gateway", gatewayCert, gatewayKey)
+
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	config := newTestConfig(grpcLn.Addr().String())
+	config.TLSCertFile, config.TLSKeyFile = serverCert, serverKey
+	config.GrpcTLSCertFile, config.GrpcTLSKeyFile = serverCert, serverKey
+	config.GrpcTLSClientCAFile = ca.CertFile
+	config.GatewayTLSCertFile, config.GatewayTLSKeyFile = gatewayCert, gatewayKey
+	config.GatewayTLSCAFile = ca.CertFile
+
+	httpAddress := startTestServers(t, store, grpcLn, config)
+
+	anonymous, err := certs.NewReloader("", "", ca.CertFile)
+	require.NoError(t, err)
+	defer anonymous.Close()
+
+	tlsConfig := anonymous.ClientConfig("127.0.0.1")
+
+	// The gateway reaches the gRPC server with its client certificate.
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	rsp, err := client.Get("https://" + httpAddress + "/v1/auth/verify-email?token=abc")
+	require.NoError(t, err)
+	defer rsp.Body.Close()
+
+	require.Equal(t, http.StatusOK, rsp.StatusCode)
+
+	// The gRPC port turns away clients without a certificate.
+	conn, err := grpc.NewClient(grpcLn.Addr().String(), grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestShutdownCompletesSinglePortCall(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+
+	user := db.User{ID: 1, Username: util.RandomOwner(), Email: util.RandomEmail(), IsEmailVerified: true}
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	store.EXPECT().
+		VerifyEmailTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(ctx context.Context, _ string) (*db.User, error) {
+			close(entered)
+			<-release
+
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			return &user, nil
+		})
+
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	httpLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	config := newTestConfig(grpcLn.Addr().String())
+	config.SinglePort = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv, err := newServers(ctx, store, config, map[string]health.Check{})
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- srv.serve(ctx, grpcLn, httpLn, nil, 5*time.Second)
+	}()
+
+	// gRPC over h2c on the HTTP port, which http.Server.Shutdown does not
+	// wait for.
+	conn, err := grpc.NewClient(httpLn.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	type result struct {
+		rsp *pb.VerifyEmailResponse
+		err error
+	}
+
+	inFlight := make(chan result, 1)
+
+	go func() {
+		rsp, err := pb.NewSimpleBankClient(conn).VerifyEmail(context.Background(), &pb.VerifyEmailRequest{Token: "abc"})
+		inFlight <- result{rsp, err}
+	}()
+
+	select {
+	case <-entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("call never reached the store")
+	}
+
+	cancel()
+
+	// Give shutdown the time to get as far as it can without the call.
+	time.Sleep(100 * time.Millisecond)
+
+	select {
+	case err := <-served:
+		t.Fatalf("servers stopped before the call finished: %v", err)
+	default:
+	}
+
+	close(release)
+
+	res := <-inFlight
+	require.NoError(t, res.err)
+	require.Equal(t, user.Username, res.rsp.GetUser().GetUsername())
+
+	select {
+	case err := <-served:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("servers did not shut down")
+	}
+}
+
+func TestShutdownCutsOffSinglePortStream(t *testing.T) {
+	grpcLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	httpLn, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	config := newTestConfig(grpcLn.Addr().String())
+	config.SinglePort = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv, err := newServers(ctx, mockdb.NewMockStore(gomock.NewController(t)), config, map[string]health.Check{})
+	require.NoError(t, err)
+
+	served := make(chan error, 1)
+
+	go func() {
+		served <- srv.serve(ctx, grpcLn, httpLn, nil, 200*time.Millisecond)
+	}()
+
+	conn, err := grpc.NewClient(httpLn.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// A stream that never ends by itself.
+	stream, err := healthpb.NewHealthClient(conn).Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	cancel()
+
+	select {
+	case err := <-served:
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(5 * time.Second):
+		t.Fatal("servers did not shut down")
+	}
+
+	for {
+		if _, err := stream.Recv(); err != nil {
+			break
+		}
+	}
+}
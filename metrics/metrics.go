@@ -0,0 +1,136 @@
+// Package metrics exposes Prometheus metrics on /metrics: RED metrics for
+// HTTP routes and gRPC methods, database pool stats and business counters.
+// Everything is collected by middleware, interceptors and wrappers around
+// the store and token maker, so handlers stay unaware of it.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "cedar"
+
+// Registry holds every metric of the process. NewPoolCollector is
+// registered on it once the pool exists.
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time to answer HTTP requests by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	grpcRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_requests_total",
+		Help:      "gRPC calls by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Time to answer gRPC calls by method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+
+	transfers = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transfers_total",
+		Help:      "Transfers by outcome: ok, or the code of the error they failed with.",
+	}, []string{"outcome"})
+
+	signins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "signins_total",
+		Help:      "Sign-in attempts by outcome, such as succeeded or bad_credentials.",
+	}, []string{"outcome"})
+
+	tokenFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "token_verification_failures_total",
+		Help:      "Access and refresh tokens that failed verification, by error code.",
+	}, []string{"reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		grpcRequests,
+		grpcDuration,
+		transfers,
+		signins,
+		tokenFailures,
+	)
+}
+
+// Handler serves Registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// standardMethods are the HTTP methods counted under their own name.
+var standardMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodConnect: true,
+	http.MethodOptions: true,
+	http.MethodTrace:   true,
+}
+
+// ObserveHTTP records a request to route, the pattern it matched rather
+// than its path so ids do not each get a series. Any method a client
+// makes up is counted as OTHER for the same reason.
+func ObserveHTTP(method, route string, status int, elapsed time.Duration) {
+	if route == "" {
+		route = "unmatched"
+	}
+
+	if !standardMethods[method] {
+		method = "OTHER"
+	}
+
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+// ObserveGRPC records a call to method, a full name such as
+// /pb.SimpleBank/SigninUser, that ended with code.
+func ObserveGRPC(method, code string, elapsed time.Duration) {
+	grpcRequests.WithLabelValues(method, code).Inc()
+	grpcDuration.WithLabelValues(method).Observe(elapsed.Seconds())
+}
+
+// errorCode labels err by its catalog code. Errors outside the catalog
+// are all counted as ErrInternal, which keeps the label bounded.
+func errorCode(err error) string {
+	var customErr util.CustomError
+
+	if errors.As(err, &customErr) {
+		return customErr.Code()
+	}
+
+	return util.ErrInternal.Code()
+}
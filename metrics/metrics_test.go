@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/token"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstrumentStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := mockdb.NewMockStore(ctrl)
+	store := InstrumentStore(mock)
+	ctx := context.Background()
+
+	okBefore := testutil.ToFloat64(transfers.WithLabelValues("ok"))
+	fundsBefore := testutil.ToFloat64(transfers.WithLabelValues(db.ErrFundNotSufficient.Code()))
+	internalBefore := testutil.ToFloat64(transfers.WithLabelValues(util.ErrInternal.Code()))
+
+	gomock.InOrder(
+		mock.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Return(&db.TransferTxResult{}, nil),
+		mock.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Return(nil, db.ErrFundNotSufficient),
+		mock.EXPECT().TransferTx(gomock.Any(), gomock.Any()).Return(nil, errors.New("conn closed")),
+	)
+
+	for i := 0; i < 3; i++ {
+		_, _ = store.TransferTx(ctx, db.TransferTxParams{})
+	}
+
+	require.Equal(t, okBefore+1, testutil.ToFloat64(transfers.WithLabelValues("ok")))
+	require.Equal(t, fundsBefore+1, testutil.ToFloat64(transfers.WithLabelValues(db.ErrFundNotSufficient.Code())))
+	require.Equal(t, internalBefore+1, testutil.ToFloat64(transfers.WithLabelValues(util.ErrInternal.Code())))
+
+	badBefore := testutil.ToFloat64(signins.WithLabelValues(db.SigninBadCredentials))
+	succeededBefore := testutil.ToFloat64(signins.WithLabelValues(db.SigninSucceeded))
+	twoFactorBefore := testutil.ToFloat64(signins.WithLabelValues(signinTwoFactorFailed))
+
+	mock.EXPECT().RecordSigninAttemptTx(gomock.Any(), gomock.Any()).Return(&db.AuthAudit{}, nil)
+	_, _ = store.RecordSigninAttemptTx(ctx, db.RecordSigninAttemptTxParams{Outcome: db.SigninBadCredentials})
+
+	gomock.InOrder(
+		mock.EXPECT().CompleteSigninChallengeTx(gomock.Any(), gomock.Any()).Return(&db.User{}, nil),
+		mock.EXPECT().CompleteSigninChallengeTx(gomock.Any(), gomock.Any()).Return(nil, db.ErrChallengeCodeInvalid),
+		mock.EXPECT().CompleteSigninChallengeTx(gomock.Any(), gomock.Any()).Return(nil, errors.New("conn closed")),
+	)
+
+	for i := 0; i < 3; i++ {
+		_, _ = store.CompleteSigninChallengeTx(ctx, db.CompleteSigninChallengeTxParams{})
+	}
+
+	require.Equal(t, badBefore+1, testutil.ToFloat64(signins.WithLabelValues(db.SigninBadCredentials)))
+	require.Equal(t, succeededBefore+1, testutil.ToFloat64(signins.WithLabelValues(db.SigninSucceeded)))
+	require.Equal(t, twoFactorBefore+1, testutil.ToFloat64(signins.WithLabelValues(signinTwoFactorFailed)))
+}
+
+func TestInstrumentMaker(t *testing.T) {
+	pasetoMaker, err := token.NewPasetoMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	tokenMaker := InstrumentMaker(pasetoMaker)
+	invalid := tokenFailures.WithLabelValues(token.ErrInvalidToken.Code())
+	before := testutil.ToFloat64(invalid)
+
+	accessToken, _, err := tokenMaker.CreateToken(1, "jdoe@mail.com", time.Minute)
+	require.NoError(t, err)
+
+	_, err = tokenMaker.VerifyToken(accessToken)
+	require.NoError(t, err)
+
+	_, err = tokenMaker.VerifyToken("garbage")
+	require.Error(t, err)
+
+	require.Equal(t, before+1, testutil.ToFloat64(invalid))
+}
+
+func TestPoolCollector(t *testing.T) {
+	pool, err := pgxpool.New(context.Background(), "postgres://nobody@127.0.0.1:1/none")
+	require.NoError(t, err)
+	defer pool.Close()
+
+	require.Equal(t, 8, testutil.CollectAndCount(NewPoolCollector(pool)))
+}
+
+func TestHandler(t *testing.T) {
+	ObserveHTTP(http.MethodGet, "/accounts/:id", http.StatusOK, time.Millisecond)
+	ObserveGRPC("/pb.SimpleBank/SigninUser", "OK", time.Millisecond)
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body := recorder.Body.String()
+	require.Contains(t, body, `cedar_http_requests_total{method="GET",route="/accounts/:id",status="200"}`)
+	require.Contains(t, body, `cedar_grpc_request_duration_seconds_bucket{method="/pb.SimpleBank/SigninUser"`)
+	require.Contains(t, body, "go_goroutines")
+}
+
+func TestObserveHTTPOtherMethod(t *testing.T) {
+	before := testutil.ToFloat64(httpRequests.WithLabelValues("OTHER", "unmatched", "405"))
+
+	ObserveHTTP("MADEUP", "", http.StatusMethodNotAllowed, time.Millisecond)
+	ObserveHTTP("ANOTHER-ONE", "", http.StatusMethodNotAllowed, time.Millisecond)
+
+	require.Equal(t, before+2, testutil.ToFloat64(httpRequests.WithLabelValues("OTHER", "unmatched", "405")))
+	require.Zero(t, testutil.ToFloat64(httpRequests.WithLabelValues("MADEUP", "unmatched", "405")))
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"context"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+)
+
+// store counts the outcome of transfers and sign-ins as they reach the
+// database, for both servers at once.
+type store struct {
+	db.Store
+}
+
+// InstrumentStore wraps s to count transfers and sign-ins.
+func InstrumentStore(s db.Store) db.Store {
+	return &store{Store: s}
+}
+
+func (s *store) TransferTx(ctx context.Context, arg db.TransferTxParams) (*db.TransferTxResult, error) {
+	result, err := s.Store.TransferTx(ctx, arg)
+
+	if err != nil {
+		transfers.WithLabelValues(errorCode(err)).Inc()
+	} else {
+		transfers.WithLabelValues("ok").Inc()
+	}
+
+	return result, err
+}
+
+// RecordSigninAttemptTx counts the password step of every sign-in, which
+// both servers record for the auth audit.
+func (s *store) RecordSigninAttemptTx(ctx context.Context, arg db.RecordSigninAttemptTxParams) (*db.AuthAudit, error) {
+	audit, err := s.Store.RecordSigninAttemptTx(ctx, arg)
+
+	if err == nil {
+		signins.WithLabelValues(arg.Outcome).Inc()
+	}
+
+	return audit, err
+}
+
+// CompleteSigninChallengeTx counts the second step of a sign-in that
+// asked for one. Failures other than a wrong or spent challenge, such as
+// a lost connection, are not sign-in outcomes and are left out.
+func (s *store) CompleteSigninChallengeTx(ctx context.Context, arg db.CompleteSigninChallengeTxParams) (*db.User, error) {
+	user, err := s.Store.CompleteSigninChallengeTx(ctx, arg)
+
+	if err == nil {
+		signins.WithLabelValues(db.SigninSucceeded).Inc()
+	} else if _, ok := util.AsCustomError(err); ok {
+		signins.WithLabelValues(signinTwoFactorFailed).Inc()
+	}
+
+	return user, err
+}
+
+// signinTwoFactorFailed is the outcome of a wrong or expired second
+// factor, which the auth audit does not record.
+const signinTwoFactorFailed = "two_factor_failed"
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/devphasex/cedar-bank-api/token"
+)
+
+type maker struct {
+	token.Maker
+}
+
+// InstrumentMaker wraps m to count tokens that fail verification.
+func InstrumentMaker(m token.Maker) token.Maker {
+	return &maker{Maker: m}
+}
+
+func (m *maker) VerifyToken(tok string) (*token.Payload, error) {
+	payload, err := m.Maker.VerifyToken(tok)
+
+	if err != nil {
+		tokenFailures.WithLabelValues(errorCode(err)).Inc()
+	}
+
+	return payload, err
+}
@@ -0,0 +1,99 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// rangePrefixLen is how many hex characters of the SHA-1 a range query
+// reveals, as in the Pwned Passwords API.
+const rangePrefixLen = 5
+
+// BreachedPasswords answers k-anonymity range queries: given the first
+// five hex characters of a password's SHA-1 it returns the remaining 35
+// of every breached hash that shares them. Only the prefix ever leaves
+// the caller, so a remote source never learns the password.
+type BreachedPasswords interface {
+	Range(ctx context.Context, prefix string) ([]string, error)
+}
+
+// IsBreached reports whether password is in list.
+func IsBreached(ctx context.Context, list BreachedPasswords, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	suffixes, err := list.Range(ctx, digest[:rangePrefixLen])
+
+	if err != nil {
+		return false, err
+	}
+
+	for _, suffix := range suffixes {
+		if suffix == digest[rangePrefixLen:] {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// FileBreachList is a breached password list held in memory.
+type FileBreachList struct {
+	ranges map[string][]string
+}
+
+// LoadBreachList reads a list in the Pwned Passwords download format: one
+// upper or lower case SHA-1 hex digest per line, optionally followed by
+// ":<count>". Blank lines and lines starting with # are skipped. The whole
+// list is kept in memory, so use a trimmed one such as the most common
+// few million.
+func LoadBreachList(path string) (*FileBreachList, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	list := &FileBreachList{ranges: map[string][]string{}}
+	scanner := bufio.NewScanner(file)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		digest, _, _ := strings.Cut(line, ":")
+		digest = strings.ToUpper(digest)
+
+		if _, err := hex.DecodeString(digest); err != nil || len(digest) != 2*sha1.Size {
+			return nil, fmt.Errorf("%s:%d: not a SHA-1 hex digest", path, n)
+		}
+
+		prefix := digest[:rangePrefixLen]
+		list.ranges[prefix] = append(list.ranges[prefix], digest[rangePrefixLen:])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, suffixes := range list.ranges {
+		sort.Strings(suffixes)
+	}
+
+	return list, nil
+}
+
+func (l *FileBreachList) Range(_ context.Context, prefix string) ([]string, error) {
+	return l.ranges[strings.ToUpper(prefix)], nil
+}
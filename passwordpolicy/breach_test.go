@@ -0,0 +1,42 @@
+package passwordpolicy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBreachList(t *testing.T) {
+	list, err := LoadBreachList("testdata/breached.txt")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	for _, password := range []string{"password", "123456", "Tr0ub4dor&3"} {
+		breached, err := IsBreached(ctx, list, password)
+		require.NoError(t, err)
+		require.True(t, breached, password)
+	}
+
+	breached, err := IsBreached(ctx, list, "correct horse battery staple")
+	require.NoError(t, err)
+	require.False(t, breached)
+
+	suffixes, err := list.Range(ctx, "5baa6")
+	require.NoError(t, err)
+	require.Equal(t, []string{"1E4C9B93F3F0682250B6CF8331B7EE68FD8"}, suffixes)
+}
+
+func TestLoadBreachListInvalid(t *testing.T) {
+	_, err := LoadBreachList(filepath.Join(t.TempDir(), "missing.txt"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte("5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8\nnot-a-hash:3\n"), 0o600))
+
+	_, err = LoadBreachList(path)
+	require.ErrorContains(t, err, "breached.txt:2")
+}
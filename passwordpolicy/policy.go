@@ -0,0 +1,167 @@
+package passwordpolicy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/devphasex/cedar-bank-api/util"
+)
+
+// bcryptMaxBytes is the longest password bcrypt hashes; it refuses
+// anything longer.
+const bcryptMaxBytes = 72
+
+// minIdentifierLen keeps very short usernames from ruling out half the
+// passwords there are.
+const minIdentifierLen = 3
+
+// Policy is what a new password has to satisfy.
+type Policy struct {
+	// MinLength and MaxLength count characters, not bytes.
+	MinLength int
+	MaxLength int
+	// MaxBytes, when set, bounds the encoded length for hashers that
+	// have a limit of their own.
+	MaxBytes int
+	// MinClasses is how many of lower case letters, upper case letters,
+	// digits and other characters the password has to mix.
+	MinClasses int
+	// Breached, when set, rejects passwords known from data breaches.
+	Breached BreachedPasswords
+}
+
+// NewPolicy builds the policy configured in config, loading the breached
+// password list if one is set.
+func NewPolicy(config *util.Config) (*Policy, error) {
+	policy := &Policy{
+		MinLength:  config.PasswordMinLength,
+		MaxLength:  config.PasswordMaxLength,
+		MinClasses: config.PasswordMinClasses,
+	}
+
+	if policy.MaxLength < policy.MinLength {
+		return nil, fmt.Errorf("password max length %d is below the min length %d", policy.MaxLength, policy.MinLength)
+	}
+
+	if config.PasswordHasher == "bcrypt" {
+		policy.MaxBytes = bcryptMaxBytes
+	}
+
+	if config.PasswordBreachList != "" {
+		list, err := LoadBreachList(config.PasswordBreachList)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot load breached password list: %w", err)
+		}
+
+		policy.Breached = list
+	}
+
+	return policy, nil
+}
+
+// Error lists every rule a password broke.
+type Error struct {
+	Violations []string
+}
+
+func (e *Error) Error() string {
+	return strings.Join(e.Violations, "; ")
+}
+
+// Check returns an *Error naming every rule password breaks, or nil.
+// username and email are those of the account the password is for.
+func (p *Policy) Check(ctx context.Context, password, username, email string) error {
+	var violations []string
+
+	length := utf8.RuneCountInString(password)
+
+	if length < p.MinLength {
+		violations = append(violations, fmt.Sprintf("password must be at least %d characters long", p.MinLength))
+	}
+
+	if (p.MaxLength > 0 && length > p.MaxLength) || (p.MaxBytes > 0 && len(password) > p.MaxBytes) {
+		violations = append(violations, fmt.Sprintf("password must be at most %d characters long", p.maxLength()))
+	}
+
+	if characterClasses(password) < p.MinClasses {
+		violations = append(violations, fmt.Sprintf(
+			"password must mix at least %d of lower case letters, upper case letters, digits and symbols", p.MinClasses))
+	}
+
+	if containsIdentifier(password, username, email) {
+		violations = append(violations, "password must not contain your username or email")
+	}
+
+	if p.Breached != nil {
+		breached, err := IsBreached(ctx, p.Breached, password)
+
+		if err != nil {
+			return err
+		}
+
+		if breached {
+			violations = append(violations, "password appears in a known data breach, choose another one")
+		}
+	}
+
+	if len(violations) != 0 {
+		return &Error{Violations: violations}
+	}
+
+	return nil
+}
+
+// maxLength is the limit to report, the tighter of MaxLength and MaxBytes.
+func (p *Policy) maxLength() int {
+	if p.MaxBytes > 0 && (p.MaxLength == 0 || p.MaxBytes < p.MaxLength) {
+		return p.MaxBytes
+	}
+
+	return p.MaxLength
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, other bool
+
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	classes := 0
+
+	for _, has := range []bool{lower, upper, digit, other} {
+		if has {
+			classes++
+		}
+	}
+
+	return classes
+}
+
+func containsIdentifier(password, username, email string) bool {
+	password = strings.ToLower(password)
+	local, _, _ := strings.Cut(email, "@")
+
+	for _, identifier := range []string{username, local} {
+		identifier = strings.ToLower(identifier)
+
+		if len(identifier) >= minIdentifierLen && strings.Contains(password, identifier) {
+			return true
+		}
+	}
+
+	return false
+}
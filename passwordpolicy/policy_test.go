@@ -0,0 +1,85 @@
+package passwordpolicy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	list, err := LoadBreachList("testdata/breached.txt")
+	require.NoError(t, err)
+
+	policy := &Policy{MinLength: 8, MaxLength: 20, MinClasses: 3, Breached: list}
+
+	testCases := []struct {
+		name       string
+		password   string
+		violations int
+	}{
+		{name: "OK", password: "Blue-Horse-7"},
+		{name: "NonASCII", password: "Grüße-aus-Köln"},
+		{name: "TooShort", password: "Ab1-", violations: 1},
+		{name: "TooLong", password: "Blue-Horse-7-Blue-Horse", violations: 1},
+		{name: "MultiByteCountsOnce", password: "Äö1-Äö1-", violations: 0},
+		{name: "TooFewClasses", password: "bluehorse7", violations: 1},
+		{name: "ContainsUsername", password: "Jdoe-1234", violations: 1},
+		{name: "ContainsEmail", password: "1234-J.Smith", violations: 1},
+		{name: "Breached", password: "Tr0ub4dor&3", violations: 1},
+		{name: "Everything", password: "jdoe", violations: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := policy.Check(context.Background(), tc.password, "jdoe", "j.smith@mail.com")
+
+			if tc.violations == 0 {
+				require.NoError(t, err)
+				return
+			}
+
+			var policyErr *Error
+			require.True(t, errors.As(err, &policyErr))
+			require.Len(t, policyErr.Violations, tc.violations)
+		})
+	}
+}
+
+// Short identifiers would rule out too many passwords to be worth it.
+func TestPolicyCheckShortIdentifier(t *testing.T) {
+	policy := &Policy{MinLength: 8, MaxLength: 64, MinClasses: 1}
+
+	require.NoError(t, policy.Check(context.Background(), "bobsleigh", "bo", "bo@mail.com"))
+}
+
+func TestNewPolicy(t *testing.T) {
+	config := &util.Config{
+		PasswordHasher:     "bcrypt",
+		PasswordMinLength:  8,
+		PasswordMaxLength:  128,
+		PasswordMinClasses: 3,
+		PasswordBreachList: "testdata/breached.txt",
+	}
+
+	policy, err := NewPolicy(config)
+	require.NoError(t, err)
+	require.Equal(t, bcryptMaxBytes, policy.MaxBytes)
+	require.NotNil(t, policy.Breached)
+
+	// bcrypt cannot tell apart passwords that differ past 72 bytes.
+	err = policy.Check(context.Background(), "Aa1-"+string(make([]byte, 70)), "", "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "at most 72 characters")
+
+	config.PasswordMaxLength = 4
+	_, err = NewPolicy(config)
+	require.Error(t, err)
+
+	config.PasswordMaxLength = 128
+	config.PasswordBreachList = "testdata/missing.txt"
+	_, err = NewPolicy(config)
+	require.Error(t, err)
+}
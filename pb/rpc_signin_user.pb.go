@@ -87,6 +87,11 @@ type CreateSigninResponse struct {
 	RefreshToken          string               `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
 	RefreshTokenExpiredAt *timestamp.Timestamp `protobuf:"bytes,5,opt,name=refresh_token_expired_at,json=refreshTokenExpiredAt,proto3" json:"refresh_token_expired_at,omitempty"`
 	User                  *User                `protobuf:"bytes,6,opt,name=user,proto3" json:"user,omitempty"`
+	// Set instead of the tokens when the user has two-factor authentication
+	// on; exchange the challenge token and a code with VerifySigninChallenge.
+	TwoFactorRequired  bool                 `protobuf:"varint,7,opt,name=two_factor_required,json=twoFactorRequired,proto3" json:"two_factor_required,omitempty"`
+	ChallengeToken     string               `protobuf:"bytes,8,opt,name=challenge_token,json=challengeToken,proto3" json:"challenge_token,omitempty"`
+	ChallengeExpiredAt *timestamp.Timestamp `protobuf:"bytes,9,opt,name=challenge_expired_at,json=challengeExpiredAt,proto3" json:"challenge_expired_at,omitempty"`
 }
 
 func (x *CreateSigninResponse) Reset() {
@@ -163,6 +168,82 @@ func (x *CreateSigninResponse) GetUser() *User {
 	return nil
 }
 
+func (x *CreateSigninResponse) GetTwoFactorRequired() bool {
+	if x != nil {
+		return x.TwoFactorRequired
+	}
+	return false
+}
+
+func (x *CreateSigninResponse) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *CreateSigninResponse) GetChallengeExpiredAt() *timestamp.Timestamp {
+	if x != nil {
+		return x.ChallengeExpiredAt
+	}
+	return nil
+}
+
+type VerifySigninChallengeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ChallengeToken string `protobuf:"bytes,1,opt,name=ChallengeToken,json=challenge_token,proto3" json:"ChallengeToken,omitempty"`
+	Code           string `protobuf:"bytes,2,opt,name=Code,json=code,proto3" json:"Code,omitempty"`
+}
+
+func (x *VerifySigninChallengeRequest) Reset() {
+	*x = VerifySigninChallengeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_rpc_signin_user_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifySigninChallengeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifySigninChallengeRequest) ProtoMessage() {}
+
+func (x *VerifySigninChallengeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_signin_user_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifySigninChallengeRequest.ProtoReflect.Descriptor instead.
+func (*VerifySigninChallengeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_signin_user_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VerifySigninChallengeRequest) GetChallengeToken() string {
+	if x != nil {
+		return x.ChallengeToken
+	}
+	return ""
+}
+
+func (x *VerifySigninChallengeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
 var File_rpc_signin_user_proto protoreflect.FileDescriptor
 
 var file_rpc_signin_user_proto_rawDesc = []byte{
@@ -174,7 +255,7 @@ var file_rpc_signin_user_proto_rawDesc = []byte{
 	0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
 	0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
 	0x1a, 0x0a, 0x08, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0xc3, 0x02, 0x0a, 0x14,
+	0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x22, 0xea, 0x03, 0x0a, 0x14,
 	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70,
 	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f,
 	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
@@ -195,10 +276,26 @@ var file_rpc_signin_user_proto_rawDesc = []byte{
 	0x66, 0x72, 0x65, 0x73, 0x68, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65,
 	0x64, 0x41, 0x74, 0x12, 0x1c, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x08, 0x2e, 0x70, 0x62, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65,
-	0x72, 0x42, 0x28, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x64, 0x65, 0x76, 0x70, 0x68, 0x61, 0x73, 0x65, 0x78, 0x2f, 0x63, 0x65, 0x64, 0x61, 0x72, 0x2d,
-	0x62, 0x61, 0x6e, 0x6b, 0x2d, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x72, 0x12, 0x2e, 0x0a, 0x13, 0x74, 0x77, 0x6f, 0x5f, 0x66, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x5f,
+	0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11,
+	0x74, 0x77, 0x6f, 0x46, 0x61, 0x63, 0x74, 0x6f, 0x72, 0x52, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65,
+	0x64, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x5f, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x63, 0x68, 0x61, 0x6c,
+	0x6c, 0x65, 0x6e, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x4c, 0x0a, 0x14, 0x63, 0x68,
+	0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x5f, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x5f,
+	0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x52, 0x12, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x45,
+	0x78, 0x70, 0x69, 0x72, 0x65, 0x64, 0x41, 0x74, 0x22, 0x5b, 0x0a, 0x1c, 0x56, 0x65, 0x72, 0x69,
+	0x66, 0x79, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x0e, 0x43, 0x68, 0x61, 0x6c,
+	0x6c, 0x65, 0x6e, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0f, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x5f, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x12, 0x12, 0x0a, 0x04, 0x43, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x63, 0x6f, 0x64, 0x65, 0x42, 0x28, 0x5a, 0x26, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x64, 0x65, 0x76, 0x70, 0x68, 0x61, 0x73, 0x65, 0x78, 0x2f, 0x63, 0x65,
+	0x64, 0x61, 0x72, 0x2d, 0x62, 0x61, 0x6e, 0x6b, 0x2d, 0x61, 0x70, 0x69, 0x2f, 0x70, 0x62, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -213,22 +310,24 @@ func file_rpc_signin_user_proto_rawDescGZIP() []byte {
 	return file_rpc_signin_user_proto_rawDescData
 }
 
-var file_rpc_signin_user_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_rpc_signin_user_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_rpc_signin_user_proto_goTypes = []any{
-	(*CreateSigninRequest)(nil),  // 0: pb.CreateSigninRequest
-	(*CreateSigninResponse)(nil), // 1: pb.CreateSigninResponse
-	(*timestamp.Timestamp)(nil),  // 2: google.protobuf.Timestamp
-	(*User)(nil),                 // 3: pb.User
+	(*CreateSigninRequest)(nil),          // 0: pb.CreateSigninRequest
+	(*CreateSigninResponse)(nil),         // 1: pb.CreateSigninResponse
+	(*VerifySigninChallengeRequest)(nil), // 2: pb.VerifySigninChallengeRequest
+	(*timestamp.Timestamp)(nil),          // 3: google.protobuf.Timestamp
+	(*User)(nil),                         // 4: pb.User
 }
 var file_rpc_signin_user_proto_depIdxs = []int32{
-	2, // 0: pb.CreateSigninResponse.access_token_expired_at:type_name -> google.protobuf.Timestamp
-	2, // 1: pb.CreateSigninResponse.refresh_token_expired_at:type_name -> google.protobuf.Timestamp
-	3, // 2: pb.CreateSigninResponse.user:type_name -> pb.User
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	3, // 0: pb.CreateSigninResponse.access_token_expired_at:type_name -> google.protobuf.Timestamp
+	3, // 1: pb.CreateSigninResponse.refresh_token_expired_at:type_name -> google.protobuf.Timestamp
+	4, // 2: pb.CreateSigninResponse.user:type_name -> pb.User
+	3, // 3: pb.CreateSigninResponse.challenge_expired_at:type_name -> google.protobuf.Timestamp
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_rpc_signin_user_proto_init() }
@@ -262,6 +361,18 @@ func file_rpc_signin_user_proto_init() {
 				return nil
 			}
 		}
+		file_rpc_signin_user_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*VerifySigninChallengeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -269,7 +380,7 @@ func file_rpc_signin_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_rpc_signin_user_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
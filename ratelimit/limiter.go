@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+)
+
+type Result struct {
+	Allowed bool
+	// RetryAfter is how long until the bucket holds a token again, set
+	// when the request was refused.
+	RetryAfter time.Duration
+}
+
+// Limiter takes tokens from named buckets. Implementations must be safe
+// for concurrent use.
+type Limiter interface {
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+// NewLimiter builds the limiter selected by config.RateLimiter. The memory
+// limiter only sees its own process; run the postgres one when several
+// instances serve the same clients.
+func NewLimiter(config *util.Config, store db.Store) (Limiter, error) {
+	switch config.RateLimiter {
+	case "memory":
+		return NewMemoryLimiter(), nil
+	case "postgres":
+		return NewPgLimiter(store), nil
+	}
+
+	return nil, fmt.Errorf("unknown rate limiter %q", config.RateLimiter)
+}
+
+// retryAfter is the time rate needs to refill tokens up to one.
+func retryAfter(tokens, rate float64) time.Duration {
+	return time.Duration((1 - tokens) / rate * float64(time.Second))
+}
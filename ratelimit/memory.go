@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepSize is the bucket count above which full buckets are dropped.
+const sweepSize = 10_000
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	// fullAt is when the bucket refills completely; from then on it is
+	// no different from a fresh one and can be dropped.
+	fullAt time.Time
+}
+
+// MemoryLimiter keeps buckets in process memory.
+type MemoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+func (l *MemoryLimiter) Take(_ context.Context, key string, rule Rule) (Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b, ok := l.buckets[key]
+
+	if !ok {
+		if len(l.buckets) >= sweepSize {
+			l.sweep(now)
+		}
+
+		b = &bucket{tokens: float64(rule.Burst), updated: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = min(float64(rule.Burst), b.tokens+now.Sub(b.updated).Seconds()*rule.Rate)
+	b.updated = now
+
+	result := Result{Allowed: b.tokens >= 1}
+
+	if result.Allowed {
+		b.tokens--
+	} else {
+		result.RetryAfter = retryAfter(b.tokens, rule.Rate)
+	}
+
+	b.fullAt = now.Add(time.Duration((float64(rule.Burst) - b.tokens) / rule.Rate * float64(time.Second)))
+
+	return result, nil
+}
+
+func (l *MemoryLimiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if !b.fullAt.After(now) {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+
+	rule := Rule{Route: "POST /auth/sign-in", Rate: 1, Burst: 3, By: ByIP}
+
+	for i := 0; i < 3; i++ {
+		result, err := limiter.Take(context.Background(), "a", rule)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+
+	result, err := limiter.Take(context.Background(), "a", rule)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, time.Second, result.RetryAfter)
+
+	// Other keys have buckets of their own.
+	result, err = limiter.Take(context.Background(), "b", rule)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	now = now.Add(500 * time.Millisecond)
+	result, err = limiter.Take(context.Background(), "a", rule)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+	now = now.Add(500 * time.Millisecond)
+	result, err = limiter.Take(context.Background(), "a", rule)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// Refills stop at the burst.
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		result, err = limiter.Take(context.Background(), "a", rule)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+	}
+
+	result, err = limiter.Take(context.Background(), "a", rule)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+}
+
+func TestMemoryLimiterSweep(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+
+	rule := Rule{Route: "*", Rate: 1, Burst: 1, By: ByIP}
+
+	_, err := limiter.Take(context.Background(), "spent", rule)
+	require.NoError(t, err)
+
+	now = now.Add(time.Second)
+	limiter.sweep(now)
+	require.Empty(t, limiter.buckets)
+}
@@ -0,0 +1,36 @@
+package ratelimit
+
+import (
+	"context"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+)
+
+// PgLimiter keeps buckets in the rate_limit_buckets table so that every
+// instance draws from the same ones. Refill and take happen in a single
+// statement, concurrent requests cannot both spend the last token.
+type PgLimiter struct {
+	store db.Store
+}
+
+func NewPgLimiter(store db.Store) *PgLimiter {
+	return &PgLimiter{store: store}
+}
+
+func (l *PgLimiter) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	bucket, err := l.store.TakeRateLimitToken(ctx, db.TakeRateLimitTokenParams{
+		Key:   key,
+		Burst: float64(rule.Burst),
+		Rate:  rule.Rate,
+	})
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	if bucket.Allowed {
+		return Result{Allowed: true}, nil
+	}
+
+	return Result{RetryAfter: retryAfter(bucket.Tokens, rule.Rate)}, nil
+}
@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPgLimiter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	limiter := NewPgLimiter(store)
+	rule := Rule{Route: "POST /auth/sign-in", Rate: 2, Burst: 5, By: ByIP}
+
+	arg := db.TakeRateLimitTokenParams{Key: "k", Burst: 5, Rate: 2}
+
+	store.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(db.RateLimitBucket{Key: "k", Tokens: 4, Allowed: true}, nil)
+
+	result, err := limiter.Take(context.Background(), "k", rule)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	store.EXPECT().
+		TakeRateLimitToken(gomock.Any(), gomock.Eq(arg)).
+		Times(1).
+		Return(db.RateLimitBucket{Key: "k", Tokens: 0.5, Allowed: false}, nil)
+
+	result, err = limiter.Take(context.Background(), "k", rule)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 250*time.Millisecond, result.RetryAfter)
+}
@@ -0,0 +1,168 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KeyBy selects who shares a bucket.
+type KeyBy string
+
+const (
+	// ByIP gives every client ip its own bucket.
+	ByIP KeyBy = "ip"
+	// ByUser gives every authenticated user their own bucket and falls back
+	// to the client ip for anonymous requests.
+	ByUser KeyBy = "user"
+	// ByRoute shares a single bucket between all callers of the route.
+	ByRoute KeyBy = "route"
+)
+
+// DefaultRoute is the rule applied to routes without one of their own.
+const DefaultRoute = "*"
+
+// Rule is a token bucket refilled with Rate tokens per second that holds
+// at most Burst.
+type Rule struct {
+	Route string
+	Rate  float64
+	Burst int
+	By    KeyBy
+}
+
+// Key names the bucket a request to the rule's route draws from. userID is
+// zero for anonymous requests.
+func (r Rule) Key(clientIP string, userID int64) string {
+	switch r.By {
+	case ByRoute:
+		return r.Route
+	case ByUser:
+		if userID != 0 {
+			return fmt.Sprintf("%s|user:%d", r.Route, userID)
+		}
+	}
+
+	return r.Route + "|ip:" + clientIP
+}
+
+// Rules maps routes to their rule. Routes are "METHOD /path" as
+// registered in Gin, or the full gRPC method name.
+type Rules struct {
+	routes map[string]Rule
+}
+
+// ParseRules reads a semicolon separated list of rules of the form
+//
+//	<route>=<count>/<s|m|h>[:<burst>][@<ip|user|route>]
+//
+// such as "POST /auth/sign-in=5/m@ip;*=50/s:100". Burst defaults to
+// count and the key to the client ip. The route "*" applies to every route
+// not listed, each route still getting buckets of its own.
+func ParseRules(spec string) (*Rules, error) {
+	rules := &Rules{routes: map[string]Rule{}}
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+
+		if entry == "" {
+			continue
+		}
+
+		route, limit, ok := strings.Cut(entry, "=")
+		route = strings.TrimSpace(route)
+
+		if !ok || route == "" {
+			return nil, fmt.Errorf("rate limit %q: expected <route>=<limit>", entry)
+		}
+
+		rule, err := parseLimit(strings.TrimSpace(limit))
+
+		if err != nil {
+			return nil, fmt.Errorf("rate limit %q: %w", entry, err)
+		}
+
+		if _, dup := rules.routes[route]; dup {
+			return nil, fmt.Errorf("rate limit %q: route listed twice", entry)
+		}
+
+		rule.Route = route
+		rules.routes[route] = rule
+	}
+
+	return rules, nil
+}
+
+func parseLimit(limit string) (Rule, error) {
+	rule := Rule{By: ByIP}
+
+	if rate, by, ok := strings.Cut(limit, "@"); ok {
+		switch KeyBy(by) {
+		case ByIP, ByUser, ByRoute:
+			rule.By = KeyBy(by)
+		default:
+			return rule, fmt.Errorf("unknown key %q", by)
+		}
+
+		limit = rate
+	}
+
+	return parseRate(limit, rule)
+}
+
+func parseRate(limit string, rule Rule) (Rule, error) {
+	limit, burst, hasBurst := strings.Cut(limit, ":")
+	count, unit, ok := strings.Cut(limit, "/")
+
+	if !ok {
+		return rule, fmt.Errorf("expected <count>/<unit>")
+	}
+
+	n, err := strconv.Atoi(count)
+
+	if err != nil || n <= 0 {
+		return rule, fmt.Errorf("count must be a positive integer")
+	}
+
+	var per time.Duration
+
+	switch unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return rule, fmt.Errorf("unknown unit %q", unit)
+	}
+
+	rule.Rate = float64(n) / per.Seconds()
+	rule.Burst = n
+
+	if hasBurst {
+		if rule.Burst, err = strconv.Atoi(burst); err != nil || rule.Burst <= 0 {
+			return rule, fmt.Errorf("burst must be a positive integer")
+		}
+	}
+
+	return rule, nil
+}
+
+// Match returns the rule for route, falling back to the "*" rule.
+func (r *Rules) Match(route string) (Rule, bool) {
+	if rule, ok := r.routes[route]; ok {
+		return rule, true
+	}
+
+	rule, ok := r.routes[DefaultRoute]
+	rule.Route = route
+
+	return rule, ok
+}
+
+// Empty reports whether no route is limited.
+func (r *Rules) Empty() bool {
+	return len(r.routes) == 0
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("POST /auth/sign-in=5/m@ip; /pb.SimpleBank/SigninUser=2/s:10@user;*=100/h@route")
+	require.NoError(t, err)
+	require.False(t, rules.Empty())
+
+	rule, ok := rules.Match("POST /auth/sign-in")
+	require.True(t, ok)
+	require.Equal(t, Rule{Route: "POST /auth/sign-in", Rate: 5.0 / 60, Burst: 5, By: ByIP}, rule)
+
+	rule, ok = rules.Match("/pb.SimpleBank/SigninUser")
+	require.True(t, ok)
+	require.Equal(t, Rule{Route: "/pb.SimpleBank/SigninUser", Rate: 2, Burst: 10, By: ByUser}, rule)
+
+	rule, ok = rules.Match("GET /accounts")
+	require.True(t, ok)
+	require.Equal(t, Rule{Route: "GET /accounts", Rate: 100.0 / 3600, Burst: 100, By: ByRoute}, rule)
+}
+
+func TestParseRulesEmpty(t *testing.T) {
+	rules, err := ParseRules(" ")
+	require.NoError(t, err)
+	require.True(t, rules.Empty())
+
+	_, ok := rules.Match("GET /accounts")
+	require.False(t, ok)
+}
+
+func TestParseRulesInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"POST /auth/sign-in",
+		"=5/m",
+		"POST /auth/sign-in=5",
+		"POST /auth/sign-in=0/m",
+		"POST /auth/sign-in=5/d",
+		"POST /auth/sign-in=5/m:0",
+		"POST /auth/sign-in=5/m@account",
+		"*=5/m;*=10/m",
+	} {
+		_, err := ParseRules(spec)
+		require.Error(t, err, spec)
+	}
+}
+
+func TestRuleKey(t *testing.T) {
+	rule := Rule{Route: "POST /transfer", By: ByUser}
+	require.Equal(t, "POST /transfer|user:7", rule.Key("10.0.0.1", 7))
+	require.Equal(t, "POST /transfer|ip:10.0.0.1", rule.Key("10.0.0.1", 0))
+
+	rule.By = ByIP
+	require.Equal(t, "POST /transfer|ip:10.0.0.1", rule.Key("10.0.0.1", 7))
+
+	rule.By = ByRoute
+	require.Equal(t, "POST /transfer", rule.Key("10.0.0.1", 7))
+}
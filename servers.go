@@ -0,0 +1,476 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/certs"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/gapi"
+	"github.com/devphasex/cedar-bank-api/health"
+	"github.com/devphasex/cedar-bank-api/metrics"
+	"github.com/devphasex/cedar-bank-api/pb"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/devphasex/cedar-bank-api/worker"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/rakyll/statik/fs"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// defaultHealthCheckInterval stands in for a HEALTH_CHECK_INTERVAL that is
+// not positive, which time.NewTicker would panic on.
+const defaultHealthCheckInterval = 10 * time.Second
+
+// servers is everything main runs, stopped by shutdown in the order the
+// fields are listed.
+type servers struct {
+	// gateway relays HTTP requests to grpc over gatewayConn.
+	gateway *http.Server
+	// singlePort, if set, serves gRPC on the HTTP listener.
+	singlePort  *singlePortGrpc
+	gatewayConn *grpc.ClientConn
+	grpc        *grpc.Server
+	workers     *worker.Scheduler
+	// metrics serves /metrics on a listener of its own, kept off the
+	// public ones, until the rest has stopped.
+	metrics *http.Server
+	// reloaders keep the certificates of the servers current.
+	reloaders []*certs.Reloader
+}
+
+// newServers sets up the gRPC server and the gateway in front of it. The
+// gRPC server reports itself as serving through grpc.health.v1 while
+// checks pass and as not serving once ctx is done.
+func newServers(ctx context.Context, store db.Store, config *util.Config, checks map[string]health.Check) (_ *servers, err error) {
+	s := &servers{}
+
+	defer func() {
+		if err != nil {
+			s.closeReloaders()
+		}
+	}()
+
+	grpcCreds, gatewayCreds := insecure.NewCredentials(), insecure.NewCredentials()
+
+	if config.GrpcTLSCertFile != "" {
+		grpcCerts, err := s.newReloader(config.GrpcTLSCertFile, config.GrpcTLSKeyFile, config.GrpcTLSClientCAFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("gRPC TLS: %w", err)
+		}
+
+		gatewayCerts, err := s.newReloader(config.GatewayTLSCertFile, config.GatewayTLSKeyFile, config.GatewayTLSCAFile)
+
+		if err != nil {
+			return nil, fmt.Errorf("gateway TLS: %w", err)
+		}
+
+		serverName := config.GatewayTLSServerName
+
+		if serverName == "" {
+			serverName, _, _ = net.SplitHostPort(config.GrpcServerAddress)
+		}
+
+		grpcCreds = credentials.NewTLS(grpcCerts.ServerConfig())
+		gatewayCreds = credentials.NewTLS(gatewayCerts.ClientConfig(serverName))
+	}
+
+	grpcServer, err := newGrpcServer(store, config, grpcCreds)
+
+	if err != nil {
+		return nil, err
+	}
+
+	healthServer := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	gateway, gatewayConn, err := newGatewayServer(config, checks, gatewayCreds)
+
+	if err != nil {
+		grpcServer.Stop()
+		return nil, err
+	}
+
+	if config.TLSCertFile != "" {
+		httpCerts, err := s.newReloader(config.TLSCertFile, config.TLSKeyFile, "")
+
+		if err != nil {
+			gatewayConn.Close()
+			grpcServer.Stop()
+			return nil, fmt.Errorf("HTTP TLS: %w", err)
+		}
+
+		gateway.TLSConfig = httpCerts.ServerConfig()
+	}
+
+	if config.SinglePort {
+		s.singlePort = newSinglePortGrpc(grpcServer, gateway.Handler)
+		gateway.Handler = s.singlePort.handler(gateway.TLSConfig == nil)
+	}
+
+	healthInterval := config.HealthCheckInterval
+
+	if healthInterval <= 0 {
+		healthInterval = defaultHealthCheckInterval
+	}
+
+	go health.Watch(ctx, healthServer, []string{"", pb.SimpleBank_ServiceDesc.ServiceName}, healthInterval, checks)
+
+	s.gateway = gateway
+	s.gatewayConn = gatewayConn
+	s.grpc = grpcServer
+	s.metrics = &http.Server{
+		Handler:           metrics.Handler(),
+		ReadHeaderTimeout: config.HTTPReadHeaderTimeout,
+	}
+
+	return s, nil
+}
+
+func (s *servers) newReloader(certFile, keyFile, caFile string) (*certs.Reloader, error) {
+	reloader, err := certs.NewReloader(certFile, keyFile, caFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.reloaders = append(s.reloaders, reloader)
+	return reloader, nil
+}
+
+func (s *servers) closeReloaders() {
+	for _, reloader := range s.reloaders {
+		reloader.Close()
+	}
+}
+
+// singlePortGrpc serves gRPC calls, told apart by their content type, on
+// the HTTP listener as well. gRPC needs HTTP/2, which clients negotiate
+// with ALPN under TLS; plaintext needs h2c, HTTP/2 without TLS. Calls
+// served this way get the TLS of the HTTP listener, not that of the gRPC
+// one.
+//
+// The gRPC server must not drain while such calls are open: the
+// transport grpc.Server.ServeHTTP uses panics when drained. shutdown
+// turns new calls away and waits for open ones, which the HTTP server
+// does not do for h2c connections, before GracefulStop may run.
+type singlePortGrpc struct {
+	grpc *grpc.Server
+	next http.Handler
+	// ctx, once cancelled, cuts the calls off.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	closed bool
+	calls  sync.WaitGroup
+}
+
+func newSinglePortGrpc(grpcServer *grpc.Server, next http.Handler) *singlePortGrpc {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &singlePortGrpc{grpc: grpcServer, next: next, ctx: ctx, cancel: cancel}
+}
+
+// handler returns the handler of the HTTP listener.
+func (g *singlePortGrpc) handler(plaintext bool) http.Handler {
+	if plaintext {
+		return h2c.NewHandler(g, &http2.Server{})
+	}
+
+	return g
+}
+
+func (g *singlePortGrpc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor != 2 || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		g.next.ServeHTTP(w, r)
+		return
+	}
+
+	g.mu.Lock()
+
+	if g.closed {
+		g.mu.Unlock()
+		// gRPC clients take this for codes.Unavailable and retry elsewhere.
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	g.calls.Add(1)
+	g.mu.Unlock()
+
+	defer g.calls.Done()
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	stop := context.AfterFunc(g.ctx, cancel)
+	defer stop()
+
+	g.grpc.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// shutdown turns new calls away and waits for open ones, cutting them off
+// once ctx is done.
+func (g *singlePortGrpc) shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		g.calls.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		g.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func newGrpcServer(store db.Store, config *util.Config, creds credentials.TransportCredentials) (*grpc.Server, error) {
+	server, err := gapi.NewGrpcServer(store, config)
+
+	if err != nil {
+		return nil, err
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.Creds(creds),
+		// Continues the trace the gateway, or any other client, started.
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    config.GrpcKeepaliveTime,
+			Timeout: config.GrpcKeepaliveTimeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             config.GrpcKeepaliveMinTime,
+			PermitWithoutStream: true,
+		}),
+		grpc.MaxRecvMsgSize(config.GrpcMaxRecvMsgSize),
+		grpc.MaxSendMsgSize(config.GrpcMaxSendMsgSize),
+		grpc.ChainUnaryInterceptor(
+			gapi.RequestIDInterceptor,
+			server.AccessLog,
+			gapi.MetricsInterceptor,
+			gapi.ErrorInterceptor,
+			server.Deadline,
+			server.RateLimit,
+		),
+	)
+
+	pb.RegisterSimpleBankServer(grpcServer, server)
+	reflection.Register(grpcServer)
+
+	return grpcServer, nil
+}
+
+// newGatewayServer returns the HTTP server relaying requests to the gRPC
+// server at config.GrpcServerAddress, along with the connection it relays
+// them over with creds.
+func newGatewayServer(config *util.Config, checks map[string]health.Check, creds credentials.TransportCredentials) (*http.Server, *grpc.ClientConn, error) {
+	grpcMux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{
+				UseProtoNames:   true,
+				EmitUnpopulated: true,
+			},
+			UnmarshalOptions: protojson.UnmarshalOptions{
+				DiscardUnknown: true,
+			},
+		}),
+		runtime.WithIncomingHeaderMatcher(gapi.GatewayIncomingHeader),
+		runtime.WithOutgoingHeaderMatcher(gapi.GatewayOutgoingHeader),
+		runtime.WithErrorHandler(gapi.GatewayErrorHandler),
+		runtime.WithMetadata(gapi.GatewayRoute),
+	)
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		// Pings no more often than the server allows.
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                config.GrpcKeepaliveTime,
+			Timeout:             config.GrpcKeepaliveTimeout,
+			PermitWithoutStream: true,
+		}),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallRecvMsgSize(config.GrpcMaxSendMsgSize),
+			grpc.MaxCallSendMsgSize(config.GrpcMaxRecvMsgSize),
+		),
+	} // gRPC connection options
+
+	grpcConn, err := grpc.NewClient(config.GrpcServerAddress, opts...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot create gRPC client: %w", err)
+	}
+
+	err = pb.RegisterSimpleBankHandler(context.Background(), grpcMux, grpcConn)
+	if err != nil {
+		grpcConn.Close()
+		return nil, nil, fmt.Errorf("cannot register handler: %w", err)
+	}
+
+	readinessChecks := map[string]health.Check{
+		"grpc": health.GRPCBackend(grpcConn, pb.SimpleBank_ServiceDesc.ServiceName),
+	}
+
+	for name, check := range checks {
+		readinessChecks[name] = check
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", gapi.HTTPMetrics(grpcMux))
+	mux.Handle("GET /healthz", health.LivenessHandler())
+	mux.Handle("GET /readyz", health.ReadinessHandler(readinessChecks))
+
+	statikFs, err := fs.New()
+
+	if err != nil {
+		grpcConn.Close()
+		return nil, nil, fmt.Errorf("cannot create statik fs: %w", err)
+	}
+
+	swaggerHandler := http.StripPrefix("/swagger/", http.FileServer(statikFs))
+	mux.Handle("/swagger/", swaggerHandler)
+
+	// Add CORS middleware if necessary
+	// handler := cors.Default().Handler(mux)
+
+	server := &http.Server{
+		Handler:           otelhttp.NewHandler(gapi.HTTPLogger(mux), "gateway"),
+		ReadHeaderTimeout: config.HTTPReadHeaderTimeout,
+		ReadTimeout:       config.HTTPReadTimeout,
+		WriteTimeout:      config.HTTPWriteTimeout,
+		IdleTimeout:       config.HTTPIdleTimeout,
+	}
+
+	return server, grpcConn, nil
+}
+
+// serve runs the servers on their listeners until ctx is done or one of
+// them fails, then shuts everything down within shutdownTimeout. Metrics
+// are only served if metricsLn is not nil.
+func (s *servers) serve(ctx context.Context, grpcLn, httpLn, metricsLn net.Listener, shutdownTimeout time.Duration) error {
+	errs := make(chan error, 3)
+
+	if metricsLn != nil {
+		go func() {
+			slog.Info("starting metrics server", "address", metricsLn.Addr().String())
+
+			if err := s.metrics.Serve(metricsLn); !errors.Is(err, http.ErrServerClosed) {
+				errs <- fmt.Errorf("metrics server: %w", err)
+			}
+		}()
+	}
+
+	go func() {
+		slog.Info("starting gRPC server", "address", grpcLn.Addr().String())
+
+		if err := s.grpc.Serve(grpcLn); err != nil {
+			errs <- fmt.Errorf("gRPC server: %w", err)
+		}
+	}()
+
+	go func() {
+		slog.Info("starting HTTP server", "address", httpLn.Addr().String())
+
+		serve := s.gateway.Serve
+
+		if s.gateway.TLSConfig != nil {
+			// The certificate comes from TLSConfig.GetCertificate.
+			serve = func(ln net.Listener) error { return s.gateway.ServeTLS(ln, "", "") }
+		}
+
+		if err := serve(httpLn); !errors.Is(err, http.ErrServerClosed) {
+			errs <- fmt.Errorf("HTTP server: %w", err)
+		}
+	}()
+
+	var err error
+
+	select {
+	case <-ctx.Done():
+		slog.Info("shutting down")
+	case err = <-errs:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return errors.Join(err, s.shutdown(shutdownCtx))
+}
+
+// shutdown stops taking new connections and waits for requests and job
+// runs in progress, gateway first since its requests need the gRPC
+// server. Whatever is left once ctx is done is cut off.
+func (s *servers) shutdown(ctx context.Context) error {
+	var errs []error
+
+	if err := s.gateway.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("HTTP server: %w", err))
+		s.gateway.Close()
+	}
+
+	if s.singlePort != nil {
+		if err := s.singlePort.shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("gRPC on the HTTP listener: %w", err))
+		}
+	}
+
+	s.gatewayConn.Close()
+
+	stopped := make(chan struct{})
+
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("gRPC server: %w", ctx.Err()))
+		s.grpc.Stop()
+		<-stopped
+	}
+
+	if s.workers != nil {
+		if err := s.workers.Wait(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("workers: %w", err))
+		}
+	}
+
+	if err := s.metrics.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("metrics server: %w", err))
+		s.metrics.Close()
+	}
+
+	s.closeReloaders()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,63 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+type csvWriter struct {
+	out io.Writer
+	w   *csv.Writer
+}
+
+func newCSVWriter(out io.Writer) *csvWriter {
+	return &csvWriter{out: out, w: csv.NewWriter(out)}
+}
+
+func (c *csvWriter) Begin(h Header) error {
+	err := c.w.Write([]string{"date", "entry_id", "transfer_id", "kind", "description", "amount", "balance"})
+
+	if err != nil {
+		return err
+	}
+
+	return c.w.Write([]string{h.From.Format(time.DateOnly), "", "", "opening", "Opening balance", "", money(h.OpeningBalance)})
+}
+
+func (c *csvWriter) Line(l Line) error {
+	var transferID string
+	if l.TransferID != nil {
+		transferID = strconv.FormatInt(*l.TransferID, 10)
+	}
+
+	return c.w.Write([]string{
+		l.Time.Format(time.RFC3339),
+		strconv.FormatInt(l.EntryID, 10),
+		transferID,
+		l.Kind,
+		l.Description,
+		money(l.Amount),
+		money(l.Balance),
+	})
+}
+
+func (c *csvWriter) End(s Summary) error {
+	return c.w.Write([]string{"", "", "", "closing", "Closing balance", "", money(s.ClosingBalance)})
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+
+	if err := c.w.Error(); err != nil {
+		return err
+	}
+
+	flush(c.out)
+	return nil
+}
+
+func money(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
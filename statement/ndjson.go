@@ -0,0 +1,43 @@
+package statement
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// ndjsonWriter emits one JSON object per line. The type field tells the
+// header, entry and summary records apart.
+type ndjsonWriter struct {
+	out io.Writer
+	enc *json.Encoder
+}
+
+func newNDJSONWriter(out io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{out: out, enc: json.NewEncoder(out)}
+}
+
+func (n *ndjsonWriter) Begin(h Header) error {
+	return n.enc.Encode(struct {
+		Type string `json:"type"`
+		Header
+	}{"header", h})
+}
+
+func (n *ndjsonWriter) Line(l Line) error {
+	return n.enc.Encode(struct {
+		Type string `json:"type"`
+		Line
+	}{"entry", l})
+}
+
+func (n *ndjsonWriter) End(s Summary) error {
+	return n.enc.Encode(struct {
+		Type string `json:"type"`
+		Summary
+	}{"summary", s})
+}
+
+func (n *ndjsonWriter) Flush() error {
+	flush(n.out)
+	return nil
+}
@@ -0,0 +1,151 @@
+package statement
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// The PDF is plain text set in Courier on US Letter pages. Objects 1-3 are
+// the catalog, the page tree and the font; pages follow from object 4 as
+// content stream and page pairs. Each page is written as soon as it fills
+// up, and the page tree and cross-reference table go out at the end.
+const (
+	pdfCatalogObj = 1
+	pdfPagesObj   = 2
+	pdfFontObj    = 3
+
+	pdfLinesPerPage     = 56
+	pdfDescriptionWidth = 38
+)
+
+type pdfWriter struct {
+	out     io.Writer
+	written int64
+	err     error
+	offsets map[int]int64
+	nextObj int
+	pages   []int
+	lines   []string
+}
+
+func newPDFWriter(out io.Writer) *pdfWriter {
+	return &pdfWriter{out: out, offsets: map[int]int64{}, nextObj: pdfFontObj + 1}
+}
+
+func (p *pdfWriter) Begin(h Header) error {
+	p.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	p.object(pdfFontObj, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>")
+
+	p.lines = append(p.lines,
+		"ACCOUNT STATEMENT",
+		"",
+		fmt.Sprintf("Account:  #%d (%s)", h.AccountID, h.Currency),
+		fmt.Sprintf("Period:   %s to %s", h.From.Format(time.DateOnly), h.To.Format(time.DateOnly)),
+		fmt.Sprintf("Opening balance: %s", money(h.OpeningBalance)),
+		"",
+		pdfRow("Date", "Description", "Amount", "Balance"),
+		strings.Repeat("-", 10+1+pdfDescriptionWidth+1+14+1+14),
+	)
+
+	return p.err
+}
+
+func (p *pdfWriter) Line(l Line) error {
+	p.addLine(pdfRow(l.Time.Format(time.DateOnly), l.Description, money(l.Amount), money(l.Balance)))
+	return p.err
+}
+
+func (p *pdfWriter) End(s Summary) error {
+	p.addLine("")
+	p.addLine(fmt.Sprintf("Entries: %d   Credits: %s   Debits: %s", s.EntryCount, money(s.TotalCredits), money(s.TotalDebits)))
+	p.addLine(fmt.Sprintf("Closing balance: %s", money(s.ClosingBalance)))
+	p.writePage()
+
+	kids := make([]string, len(p.pages))
+	for i, obj := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", obj)
+	}
+
+	p.object(pdfPagesObj, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	p.object(pdfCatalogObj, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObj))
+
+	xref := p.written
+	p.printf("xref\n0 %d\n0000000000 65535 f \n", p.nextObj)
+	for obj := 1; obj < p.nextObj; obj++ {
+		p.printf("%010d 00000 n \n", p.offsets[obj])
+	}
+	p.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", p.nextObj, pdfCatalogObj, xref)
+
+	return p.err
+}
+
+func (p *pdfWriter) Flush() error {
+	flush(p.out)
+	return p.err
+}
+
+func (p *pdfWriter) addLine(line string) {
+	p.lines = append(p.lines, line)
+
+	if len(p.lines) == pdfLinesPerPage {
+		p.writePage()
+	}
+}
+
+func (p *pdfWriter) writePage() {
+	var content bytes.Buffer
+	content.WriteString("BT\n/F1 9 Tf\n12 TL\n50 750 Td\n")
+	for _, line := range p.lines {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", pdfEscape(line))
+	}
+	content.WriteString("ET")
+	p.lines = p.lines[:0]
+
+	contentObj := p.reserve()
+	p.object(contentObj, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.Bytes()))
+
+	pageObj := p.reserve()
+	p.object(pageObj, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObj, pdfFontObj, contentObj,
+	))
+	p.pages = append(p.pages, pageObj)
+}
+
+func (p *pdfWriter) reserve() int {
+	obj := p.nextObj
+	p.nextObj++
+	return obj
+}
+
+func (p *pdfWriter) object(obj int, body string) {
+	p.offsets[obj] = p.written
+	p.printf("%d 0 obj\n%s\nendobj\n", obj, body)
+}
+
+// printf writes to the output, keeping track of the byte offset needed by
+// the cross-reference table. After the first error it does nothing.
+func (p *pdfWriter) printf(format string, args ...any) {
+	if p.err != nil {
+		return
+	}
+
+	n, err := fmt.Fprintf(p.out, format, args...)
+	p.written += int64(n)
+	p.err = err
+}
+
+func pdfRow(date, description, amount, balance string) string {
+	if len(description) > pdfDescriptionWidth {
+		description = description[:pdfDescriptionWidth-3] + "..."
+	}
+
+	return fmt.Sprintf("%-10s %-*s %14s %14s", date, pdfDescriptionWidth, description, amount, balance)
+}
+
+func pdfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(s)
+}
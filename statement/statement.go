@@ -0,0 +1,228 @@
+package statement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/fee"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type Format string
+
+const (
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+	PDF    Format = "pdf"
+)
+
+var ErrUnknownFormat = errors.New("unknown statement format")
+
+// batchSize is how many entries are read and written per round trip.
+// Output is flushed after every batch, so memory stays bounded however
+// long the period is.
+const batchSize = 500
+
+// ContentType returns the MIME type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv; charset=utf-8"
+	case NDJSON:
+		return "application/x-ndjson"
+	case PDF:
+		return "application/pdf"
+	}
+
+	return "application/octet-stream"
+}
+
+// Header opens a statement. From and To are inclusive calendar dates.
+type Header struct {
+	AccountID      int64     `json:"account_id"`
+	Currency       string    `json:"currency"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	OpeningBalance float64   `json:"opening_balance"`
+}
+
+// Line is one entry with the account balance right after it.
+type Line struct {
+	EntryID     int64     `json:"entry_id"`
+	Time        time.Time `json:"time"`
+	Kind        string    `json:"kind"`
+	Description string    `json:"description"`
+	TransferID  *int64    `json:"transfer_id,omitempty"`
+	Amount      float64   `json:"amount"`
+	Balance     float64   `json:"balance"`
+}
+
+type Summary struct {
+	ClosingBalance float64 `json:"closing_balance"`
+	TotalCredits   float64 `json:"total_credits"`
+	TotalDebits    float64 `json:"total_debits"`
+	EntryCount     int     `json:"entry_count"`
+}
+
+// Writer renders a statement. Begin is called once, then Line for every
+// entry in order, then End. Flush pushes buffered output to the client.
+type Writer interface {
+	Begin(h Header) error
+	Line(l Line) error
+	End(s Summary) error
+	Flush() error
+}
+
+// NewWriter returns a Writer rendering format to w.
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w), nil
+	case NDJSON:
+		return newNDJSONWriter(w), nil
+	case PDF:
+		return newPDFWriter(w), nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// Source is the part of the store statements are read from.
+type Source interface {
+	GetAccountBalanceAt(ctx context.Context, arg db.GetAccountBalanceAtParams) (float64, error)
+	GetStatementEntries(ctx context.Context, arg db.GetStatementEntriesParams) ([]db.GetStatementEntriesRow, error)
+}
+
+// Generate writes the statement of account for the inclusive dates from
+// and to (UTC). The opening balance is the balance at the start of from;
+// each line carries the running balance after its entry.
+func Generate(ctx context.Context, src Source, w Writer, account db.Account, from, to time.Time) error {
+	start := from.UTC().Truncate(24 * time.Hour)
+	end := to.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+
+	opening, err := src.GetAccountBalanceAt(ctx, db.GetAccountBalanceAtParams{
+		ID: account.ID,
+		At: pgtype.Timestamptz{Time: start, Valid: true},
+	})
+
+	if err != nil {
+		return err
+	}
+
+	err = w.Begin(Header{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		From:           start,
+		To:             end.AddDate(0, 0, -1),
+		OpeningBalance: fee.Round(opening),
+	})
+
+	if err != nil {
+		return err
+	}
+
+	summary := Summary{ClosingBalance: opening}
+	var afterID int64
+
+	for {
+		rows, err := src.GetStatementEntries(ctx, db.GetStatementEntriesParams{
+			AccountID: pgtype.Int8{Int64: account.ID, Valid: true},
+			From:      pgtype.Timestamptz{Time: start, Valid: true},
+			To:        pgtype.Timestamptz{Time: end, Valid: true},
+			AfterID:   afterID,
+			Limit:     batchSize,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			summary.ClosingBalance = fee.Round(summary.ClosingBalance + row.Amount)
+			summary.EntryCount++
+
+			if row.Amount >= 0 {
+				summary.TotalCredits += row.Amount
+			} else {
+				summary.TotalDebits -= row.Amount
+			}
+
+			line := Line{
+				EntryID:     row.ID,
+				Time:        row.CreatedAt.Time.UTC(),
+				Kind:        row.Kind,
+				Description: describe(account.ID, row),
+				Amount:      row.Amount,
+				Balance:     summary.ClosingBalance,
+			}
+
+			if row.TransferID.Valid {
+				line.TransferID = &row.TransferID.Int64
+			}
+
+			if err = w.Line(line); err != nil {
+				return err
+			}
+
+			afterID = row.ID
+		}
+
+		if err = w.Flush(); err != nil {
+			return err
+		}
+
+		if len(rows) < batchSize {
+			break
+		}
+	}
+
+	summary.TotalCredits = fee.Round(summary.TotalCredits)
+	summary.TotalDebits = fee.Round(summary.TotalDebits)
+
+	if err = w.End(summary); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+func describe(accountID int64, row db.GetStatementEntriesRow) string {
+	switch row.Kind {
+	case db.EntryKindTransfer:
+		if row.OriginalTransferID.Valid {
+			return fmt.Sprintf("Reversal of transfer #%d", row.OriginalTransferID.Int64)
+		}
+
+		if row.ToAccountID.Valid && row.ToAccountID.Int64 == accountID {
+			return fmt.Sprintf("Transfer from account #%d", row.FromAccountID.Int64)
+		}
+
+		if row.ToAccountID.Valid {
+			return fmt.Sprintf("Transfer to account #%d", row.ToAccountID.Int64)
+		}
+	case db.SystemPurposeFeeRevenue:
+		if row.TransferID.Valid {
+			return fmt.Sprintf("Fee for transfer #%d", row.TransferID.Int64)
+		}
+
+		return "Transfer fee"
+	case db.SystemPurposeOverdraftRevenue:
+		return "Overdraft charge"
+	case db.SystemPurposeInterestExpense:
+		return "Interest"
+	}
+
+	return row.Kind
+}
+
+// flush pushes output through to the client when w supports it, as
+// http.ResponseWriter implementations do.
+func flush(w io.Writer) {
+	if f, ok := w.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,168 @@
+package statement
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource serves entries of an account by id, the way the statement
+// query pages through them.
+type fakeSource struct {
+	opening float64
+	entries []db.GetStatementEntriesRow
+	calls   int
+}
+
+func (f *fakeSource) GetAccountBalanceAt(ctx context.Context, arg db.GetAccountBalanceAtParams) (float64, error) {
+	return f.opening, nil
+}
+
+func (f *fakeSource) GetStatementEntries(ctx context.Context, arg db.GetStatementEntriesParams) ([]db.GetStatementEntriesRow, error) {
+	f.calls++
+
+	var rows []db.GetStatementEntriesRow
+	for _, e := range f.entries {
+		if e.ID > arg.AfterID && len(rows) < int(arg.Limit) {
+			rows = append(rows, e)
+		}
+	}
+
+	return rows, nil
+}
+
+var testAccount = db.Account{ID: 7, Currency: "USD"}
+
+func testSource(n int) *fakeSource {
+	src := &fakeSource{opening: 100}
+	at := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	for i := 1; i <= n; i++ {
+		row := db.GetStatementEntriesRow{
+			ID:         int64(i),
+			Amount:     10,
+			Kind:       db.EntryKindTransfer,
+			CreatedAt:  pgtype.Timestamptz{Time: at, Valid: true},
+			TransferID: pgtype.Int8{Int64: int64(i), Valid: true},
+			// Even entries are incoming, odd ones outgoing.
+			FromAccountID: pgtype.Int8{Int64: 9, Valid: true},
+			ToAccountID:   pgtype.Int8{Int64: testAccount.ID, Valid: true},
+		}
+
+		if i%2 == 1 {
+			row.Amount = -4
+			row.FromAccountID, row.ToAccountID = row.ToAccountID, row.FromAccountID
+		}
+
+		src.entries = append(src.entries, row)
+	}
+
+	return src
+}
+
+func generate(t *testing.T, format Format, src Source) *bytes.Buffer {
+	var buf bytes.Buffer
+
+	w, err := NewWriter(format, &buf)
+	require.NoError(t, err)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, Generate(context.Background(), src, w, testAccount, from, to))
+	return &buf
+}
+
+func TestCSVStatement(t *testing.T) {
+	buf := generate(t, CSV, testSource(3))
+
+	records, err := csv.NewReader(buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 6)
+
+	require.Equal(t, "date", records[0][0])
+	require.Equal(t, []string{"2024-03-01", "", "", "opening", "Opening balance", "", "100.00"}, records[1])
+	require.Equal(t, []string{"2024-03-01T09:00:00Z", "1", "1", "transfer", "Transfer to account #9", "-4.00", "96.00"}, records[2])
+	require.Equal(t, []string{"2024-03-01T09:00:00Z", "2", "2", "transfer", "Transfer from account #9", "10.00", "106.00"}, records[3])
+	require.Equal(t, "102.00", records[4][6])
+	require.Equal(t, []string{"", "", "", "closing", "Closing balance", "", "102.00"}, records[5])
+}
+
+func TestNDJSONStatement(t *testing.T) {
+	src := testSource(2*batchSize + 1)
+	buf := generate(t, NDJSON, src)
+
+	// Three pages of entries, the last one short.
+	require.Equal(t, 3, src.calls)
+
+	scanner := bufio.NewScanner(buf)
+	var records []map[string]any
+	for scanner.Scan() {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 2*batchSize+3)
+
+	require.Equal(t, "header", records[0]["type"])
+	require.Equal(t, float64(100), records[0]["opening_balance"])
+	require.Equal(t, "2024-03-31T00:00:00Z", records[0]["to"])
+
+	require.Equal(t, "entry", records[1]["type"])
+	require.Equal(t, float64(96), records[1]["balance"])
+
+	summary := records[len(records)-1]
+	require.Equal(t, "summary", summary["type"])
+	require.Equal(t, float64(2*batchSize+1), summary["entry_count"])
+	require.Equal(t, float64(batchSize*10), summary["total_credits"])
+	require.Equal(t, float64((batchSize+1)*4), summary["total_debits"])
+	require.Equal(t, float64(100+batchSize*10-(batchSize+1)*4), summary["closing_balance"])
+}
+
+func TestPDFStatement(t *testing.T) {
+	buf := generate(t, PDF, testSource(pdfLinesPerPage*2))
+	out := buf.String()
+
+	require.True(t, strings.HasPrefix(out, "%PDF-1.4\n"))
+	require.True(t, strings.HasSuffix(out, "%%EOF\n"))
+	require.Contains(t, out, "(Closing balance: 436.00) Tj")
+	require.Contains(t, out, "/Count 3")
+
+	// Every cross-reference entry must point at the start of its object.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	require.NotNil(t, startxref)
+
+	offset, err := strconv.Atoi(startxref[1])
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(out[offset:], "xref\n"))
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(out[offset:], -1)
+	require.NotEmpty(t, entries)
+
+	for i, entry := range entries {
+		at, err := strconv.Atoi(entry[1])
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(out[at:], fmt.Sprintf("%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewWriter("xlsx", &bytes.Buffer{})
+	require.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestPDFEscape(t *testing.T) {
+	require.Equal(t, `a\(b\)c\\`, pdfEscape(`a(b)c\`))
+}
@@ -1,18 +1,18 @@
 package token
 
 import (
-	"errors"
 	"time"
 
+	"github.com/devphasex/cedar-bank-api/util"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
 var (
-	ErrExpiredToken          = errors.New("token has expired")
-	ErrInvalidToken          = errors.New("token not valid")
-	ErrInvalidOrExpiredToken = errors.New("token expired or invalid")
-	ErrUnverifiableToken     = errors.New("token is unverifiable")
+	ErrExpiredToken          = util.NewCustomError(util.KindUnauthenticated, "ErrExpiredToken", "token has expired")
+	ErrInvalidToken          = util.NewCustomError(util.KindUnauthenticated, "ErrInvalidToken", "token not valid")
+	ErrInvalidOrExpiredToken = util.NewCustomError(util.KindUnauthenticated, "ErrInvalidOrExpiredToken", "token expired or invalid")
+	ErrUnverifiableToken     = util.NewCustomError(util.KindUnauthenticated, "ErrUnverifiableToken", "token is unverifiable")
 )
 
 type Payload struct {
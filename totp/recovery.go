@@ -0,0 +1,69 @@
+package totp
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// RecoveryCodeCount is how many recovery codes a user gets when turning
+// two factor authentication on.
+const RecoveryCodeCount = 10
+
+// recoveryAlphabet leaves out characters that are easily confused.
+const recoveryAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+const (
+	recoveryGroups    = 4
+	recoveryGroupSize = 4
+)
+
+// NewRecoveryCodes returns n single-use codes formatted as xxxx-xxxx-xxxx-xxxx.
+// Each carries about 79 bits of entropy, enough to store them with a plain
+// hash.
+func NewRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+
+	for i := range codes {
+		b := make([]byte, recoveryGroups*recoveryGroupSize)
+
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+
+		var sb strings.Builder
+		for j, v := range b {
+			if j > 0 && j%recoveryGroupSize == 0 {
+				sb.WriteByte('-')
+			}
+
+			// 256 is not a multiple of the alphabet size, the resulting bias
+			// costs well under a bit of entropy per code.
+			sb.WriteByte(recoveryAlphabet[int(v)%len(recoveryAlphabet)])
+		}
+
+		codes[i] = sb.String()
+	}
+
+	return codes, nil
+}
+
+// NormalizeRecoveryCode strips what users tend to add or change when
+// typing a code back, so it can be hashed and compared.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.NewReplacer(" ", "", "-", "").Replace(code)
+
+	if len(code) != recoveryGroups*recoveryGroupSize {
+		return code
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(code); i += recoveryGroupSize {
+		if i > 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteString(code[i : i+recoveryGroupSize])
+	}
+
+	return sb.String()
+}
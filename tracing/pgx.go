@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryTracer is a pgx.QueryTracer tracing each query as a span. sqlc
+// queries are named after the query, such as GetAccountByID, others after
+// their first keyword, such as BEGIN or COMMIT. Query arguments are left
+// out, since they include password hashes and tokens.
+type QueryTracer struct {
+	tracer trace.Tracer
+}
+
+// NewQueryTracer returns a tracer to set as pgx.ConnConfig.Tracer.
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{tracer: otel.Tracer("github.com/devphasex/cedar-bank-api/tracing")}
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	ctx, _ = t.tracer.Start(ctx, queryName(data.SQL),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBStatement(data.SQL)),
+	)
+
+	return ctx
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+
+	// No rows is an answer, not a failure.
+	if data.Err != nil && !errors.Is(data.Err, pgx.ErrNoRows) {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	} else {
+		span.SetAttributes(attribute.Int64("db.rows_affected", data.CommandTag.RowsAffected()))
+	}
+
+	span.End()
+}
+
+// queryName reads the name from the "-- name: GetAccountByID :one"
+// comment sqlc starts its queries with.
+func queryName(sql string) string {
+	sql = strings.TrimSpace(sql)
+
+	if rest, ok := strings.CutPrefix(sql, "-- name:"); ok {
+		if fields := strings.Fields(rest); len(fields) != 0 {
+			return fields[0]
+		}
+	}
+
+	if fields := strings.Fields(sql); len(fields) != 0 {
+		return strings.ToUpper(fields[0])
+	}
+
+	return "query"
+}
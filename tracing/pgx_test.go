@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestQueryName(t *testing.T) {
+	require.Equal(t, "GetAccountByID", queryName("-- name: GetAccountByID :one\nSELECT * FROM accounts WHERE id = $1"))
+	require.Equal(t, "BEGIN", queryName("  begin"))
+	require.Equal(t, "query", queryName(""))
+}
+
+func TestQueryTracer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracer := &QueryTracer{tracer: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test")}
+
+	const sql = "-- name: GetUser :one\nSELECT * FROM users WHERE username = $1"
+
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql, Args: []any{"secret-arg"}})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "UPDATE accounts SET balance = 0"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 1")})
+
+	ctx = tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "COMMIT"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("connection reset")})
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+
+	require.Equal(t, "GetUser", spans[0].Name())
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	for _, attr := range spans[0].Attributes() {
+		require.NotContains(t, attr.Value.Emit(), "secret-arg")
+	}
+
+	require.Equal(t, "UPDATE", spans[1].Name())
+	require.Contains(t, spans[1].Attributes(), attribute.Int64("db.rows_affected", 1))
+
+	require.Equal(t, "COMMIT", spans[2].Name())
+	require.Equal(t, codes.Error, spans[2].Status().Code)
+}
@@ -0,0 +1,132 @@
+// Package tracing sets up OpenTelemetry tracing. Spans start at the HTTP
+// gateway, follow calls into the gRPC server through the propagated trace
+// context and end in the database, where QueryTracer traces every query.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+const serviceName = "cedar-bank-api"
+
+// Setup installs the W3C trace context propagator and, unless
+// config.TracingExporter is none, a global tracer provider exporting
+// spans to it:
+//
+//   - otlp sends them over gRPC to config.TracingEndpoint.
+//   - stdout writes them as JSON to config.TracingFile, or to stdout if
+//     no file is set, for local use.
+//
+// config.TracingSampleRatio of traces are sampled, see newSampler. The
+// returned func flushes pending spans and must be called before exiting.
+func Setup(ctx context.Context, config *util.Config) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if config.TracingSampleRatio < 0 || config.TracingSampleRatio > 1 {
+		return nil, fmt.Errorf("tracing sample ratio %v is not between 0 and 1", config.TracingSampleRatio)
+	}
+
+	exporter, err := newExporter(ctx, config)
+
+	if err != nil || exporter == nil {
+		return func(context.Context) error { return nil }, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(newSampler(config.TracingSampleRatio)),
+	)
+
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// newSampler samples ratio of traces by their trace id. Spans follow a
+// parent from this process, but the sampled flag of a remote one is
+// ignored: it comes from whoever called, and a client could otherwise
+// have every request it sends traced. Deciding on the trace id alone
+// still keeps the gateway and the gRPC server agreeing on each trace.
+func newSampler(ratio float64) sdktrace.Sampler {
+	byTraceID := sdktrace.TraceIDRatioBased(ratio)
+
+	return sdktrace.ParentBased(byTraceID,
+		sdktrace.WithRemoteParentSampled(byTraceID),
+		sdktrace.WithRemoteParentNotSampled(byTraceID),
+	)
+}
+
+func newExporter(ctx context.Context, config *util.Config) (sdktrace.SpanExporter, error) {
+	switch config.TracingExporter {
+	case "", "none":
+		return nil, nil
+	case "otlp":
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.TracingEndpoint)}
+
+		if config.TracingInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+
+		return otlptracegrpc.New(ctx, opts...)
+	case "stdout":
+		if config.TracingFile == "" {
+			return stdouttrace.New()
+		}
+
+		file, err := os.OpenFile(config.TracingFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+
+		if err != nil {
+			return nil, err
+		}
+
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+
+		return &fileExporter{SpanExporter: exporter, file: file}, nil
+	default:
+		return nil, fmt.Errorf("unsupported tracing exporter %q", config.TracingExporter)
+	}
+}
+
+// fileExporter closes the file spans are written to on shutdown.
+type fileExporter struct {
+	sdktrace.SpanExporter
+	file *os.File
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+
+	if closeErr := e.file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSetupStdoutFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "spans.json")
+
+	shutdown, err := Setup(context.Background(), &util.Config{
+		TracingExporter:    "stdout",
+		TracingFile:        file,
+		TracingSampleRatio: 1,
+	})
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "TransferTx")
+	span.End()
+
+	require.NoError(t, shutdown(context.Background()))
+
+	spans, err := os.ReadFile(file)
+	require.NoError(t, err)
+	require.Contains(t, string(spans), `"Name":"TransferTx"`)
+	require.Contains(t, string(spans), serviceName)
+}
+
+func TestSetupInvalid(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config util.Config
+	}{
+		{"UnsupportedExporter", util.Config{TracingExporter: "zipkin", TracingSampleRatio: 1}},
+		{"NegativeSampleRatio", util.Config{TracingExporter: "none", TracingSampleRatio: -0.1}},
+		{"SampleRatioAboveOne", util.Config{TracingExporter: "none", TracingSampleRatio: 1.5}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Setup(context.Background(), &tc.config)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestSetupNone(t *testing.T) {
+	shutdown, err := Setup(context.Background(), &util.Config{TracingExporter: "none", TracingSampleRatio: 1})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
+
+func TestSamplerIgnoresRemoteDecision(t *testing.T) {
+	traceID := trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+
+	parent := func(remote bool, flags trace.TraceFlags) context.Context {
+		return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     trace.SpanID{0, 0, 0, 0, 0, 0, 0, 1},
+			TraceFlags: flags,
+			Remote:     remote,
+		}))
+	}
+
+	decide := func(ratio float64, ctx context.Context) sdktrace.SamplingDecision {
+		return newSampler(ratio).ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: ctx,
+			TraceID:       traceID,
+			Name:          "span",
+		}).Decision
+	}
+
+	// A client asking for sampling does not get it past a zero ratio, nor
+	// can it opt out of a full one.
+	require.Equal(t, sdktrace.Drop, decide(0, parent(true, trace.FlagsSampled)))
+	require.Equal(t, sdktrace.RecordAndSample, decide(1, parent(true, 0)))
+
+	// Spans within the process follow their parent.
+	require.Equal(t, sdktrace.RecordAndSample, decide(0, parent(false, trace.FlagsSampled)))
+	require.Equal(t, sdktrace.Drop, decide(1, parent(false, 0)))
+}
@@ -0,0 +1,6 @@
+package util
+
+const (
+	CheckingAccount = "checking"
+	SavingsAccount  = "savings"
+)
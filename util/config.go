@@ -1,31 +1,394 @@
 package util
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	DbSource          string        `mapstructure:"DBSOURCE"`
-	HttpServerAddress string        `mapstructure:"HTTP_SERVER_ADDRESS"`
-	GrpcServerAddress string        `mapstructure:"GRPC_SERVER_ADDRESS"`
-	SymmetricKey      string        `mapstructure:"SYMMETRIC_KEY"`
-	AccessTokenTime   time.Duration `mapstructure:"ACCESS_TOKEN_TIME"`
-	RefreshTokenTime  time.Duration `mapstructure:"REFRESH_TOKEN_TIME"`
+	// Profile is the APP_PROFILE LoadConfig was run with: dev, test or prod.
+	Profile           string `mapstructure:"APP_PROFILE"`
+	DbSource          string `mapstructure:"DBSOURCE"`
+	HttpServerAddress string `mapstructure:"HTTP_SERVER_ADDRESS"`
+	GrpcServerAddress string `mapstructure:"GRPC_SERVER_ADDRESS"`
+	// MetricsServerAddress is where Prometheus metrics are served, apart
+	// from the public listeners so only the scraper can reach them.
+	// Empty turns the endpoint off.
+	MetricsServerAddress string        `mapstructure:"METRICS_SERVER_ADDRESS"`
+	SymmetricKey         string        `mapstructure:"SYMMETRIC_KEY"`
+	AccessTokenTime      time.Duration `mapstructure:"ACCESS_TOKEN_TIME"`
+	RefreshTokenTime     time.Duration `mapstructure:"REFRESH_TOKEN_TIME"`
+	TransferHoldTTL      time.Duration `mapstructure:"TRANSFER_HOLD_TTL"`
+	HoldExpiryPeriod     time.Duration `mapstructure:"HOLD_EXPIRY_PERIOD"`
+	// OverdraftAnnualRate is the yearly interest in percent charged on
+	// negative balances, accrued daily with OverdraftDailyFee on top.
+	OverdraftAnnualRate    float64       `mapstructure:"OVERDRAFT_ANNUAL_RATE"`
+	OverdraftDailyFee      float64       `mapstructure:"OVERDRAFT_DAILY_FEE"`
+	OverdraftAccrualPeriod time.Duration `mapstructure:"OVERDRAFT_ACCRUAL_PERIOD"`
+	InterestAccrualPeriod  time.Duration `mapstructure:"INTEREST_ACCRUAL_PERIOD"`
+	// Mailer selects how mail is delivered: smtp, file (one .eml per
+	// message under MailDir) or memory.
+	Mailer       string `mapstructure:"MAILER"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailDir      string `mapstructure:"MAIL_DIR"`
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	// EmailVerifyURL is the page verification links point to; the token
+	// is appended as the token query parameter.
+	EmailVerifyURL          string        `mapstructure:"EMAIL_VERIFY_URL"`
+	EmailVerifyTTL          time.Duration `mapstructure:"EMAIL_VERIFY_TTL"`
+	EmailVerifyResendLimit  int64         `mapstructure:"EMAIL_VERIFY_RESEND_LIMIT"`
+	EmailVerifyResendWindow time.Duration `mapstructure:"EMAIL_VERIFY_RESEND_WINDOW"`
+	// PasswordResetURL is the page reset links point to, with the token
+	// appended like EmailVerifyURL.
+	PasswordResetURL    string        `mapstructure:"PASSWORD_RESET_URL"`
+	PasswordResetTTL    time.Duration `mapstructure:"PASSWORD_RESET_TTL"`
+	PasswordResetLimit  int64         `mapstructure:"PASSWORD_RESET_LIMIT"`
+	PasswordResetWindow time.Duration `mapstructure:"PASSWORD_RESET_WINDOW"`
+	// TOTPIssuer names the service in authenticator apps.
+	TOTPIssuer         string        `mapstructure:"TOTP_ISSUER"`
+	SigninChallengeTTL time.Duration `mapstructure:"SIGNIN_CHALLENGE_TTL"`
+	// StepUpThreshold is the transfer amount above which the user has to
+	// re-authenticate; zero turns step-up off. StepUpTTL bounds both the
+	// challenge and the token it yields.
+	StepUpThreshold float64       `mapstructure:"STEP_UP_THRESHOLD"`
+	StepUpTTL       time.Duration `mapstructure:"STEP_UP_TTL"`
+	// Failed sign-ins delay the next attempt by SigninDelayBase, doubling
+	// up to SigninDelayMax. SigninMaxFailures for a user, or
+	// SigninIPMaxFailures for a client ip, within SigninFailureWindow lock
+	// it out for SigninLockoutDuration; zero never locks.
+	SigninMaxFailures     int32         `mapstructure:"SIGNIN_MAX_FAILURES"`
+	SigninIPMaxFailures   int32         `mapstructure:"SIGNIN_IP_MAX_FAILURES"`
+	SigninLockoutDuration time.Duration `mapstructure:"SIGNIN_LOCKOUT_DURATION"`
+	SigninDelayBase       time.Duration `mapstructure:"SIGNIN_DELAY_BASE"`
+	SigninDelayMax        time.Duration `mapstructure:"SIGNIN_DELAY_MAX"`
+	SigninFailureWindow   time.Duration `mapstructure:"SIGNIN_FAILURE_WINDOW"`
+	// RateLimiter keeps the token buckets in memory or, shared between
+	// instances, in postgres. RateLimits lists the per route limits, see
+	// ratelimit.ParseRules; empty turns rate limiting off.
+	RateLimiter string `mapstructure:"RATE_LIMITER"`
+	RateLimits  string `mapstructure:"RATE_LIMITS"`
+	// PasswordHasher is the algorithm new passwords are hashed with:
+	// argon2id, bcrypt or scrypt. Passwords hashed any other way are
+	// rehashed with it on sign-in.
+	PasswordHasher string `mapstructure:"PASSWORD_HASHER"`
+	// New passwords must be PasswordMinLength to PasswordMaxLength
+	// characters long and mix PasswordMinClasses of lower case, upper case,
+	// digits and symbols. PasswordBreachList names a file of breached
+	// password SHA-1 hashes to reject; empty skips the check.
+	PasswordMinLength  int    `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordMaxLength  int    `mapstructure:"PASSWORD_MAX_LENGTH"`
+	PasswordMinClasses int    `mapstructure:"PASSWORD_MIN_CLASSES"`
+	PasswordBreachList string `mapstructure:"PASSWORD_BREACH_LIST"`
+	// LogFormat is json or text; LogLevel one of debug, info, warn or
+	// error. Request payloads are only logged at debug.
+	LogFormat string `mapstructure:"LOG_FORMAT"`
+	LogLevel  string `mapstructure:"LOG_LEVEL"`
+	// TracingExporter is where spans go: none, otlp to the collector at
+	// TracingEndpoint, or stdout, which writes them to TracingFile or to
+	// stdout. TracingSampleRatio of traces are sampled,
+	// whatever a caller's traceparent asks for.
+	TracingExporter    string  `mapstructure:"TRACING_EXPORTER"`
+	TracingEndpoint    string  `mapstructure:"TRACING_ENDPOINT"`
+	TracingInsecure    bool    `mapstructure:"TRACING_INSECURE"`
+	TracingFile        string  `mapstructure:"TRACING_FILE"`
+	TracingSampleRatio float64 `mapstructure:"TRACING_SAMPLE_RATIO"`
+	// HealthCheckInterval is how often the gRPC server checks its
+	// dependencies to report its grpc.health.v1 status.
+	HealthCheckInterval time.Duration `mapstructure:"HEALTH_CHECK_INTERVAL"`
+	// ShutdownTimeout is how long requests and job runs in progress get
+	// to finish on SIGTERM before they are cut off.
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+	// The pool keeps DbMinConns to DbMaxConns connections, each replaced
+	// after DbMaxConnLifetime or once idle for DbMaxConnIdleTime. Postgres
+	// cancels any statement running longer than DbStatementTimeout.
+	DbMaxConns         int32         `mapstructure:"DB_MAX_CONNS"`
+	DbMinConns         int32         `mapstructure:"DB_MIN_CONNS"`
+	DbMaxConnLifetime  time.Duration `mapstructure:"DB_MAX_CONN_LIFETIME"`
+	DbMaxConnIdleTime  time.Duration `mapstructure:"DB_MAX_CONN_IDLE_TIME"`
+	DbConnectTimeout   time.Duration `mapstructure:"DB_CONNECT_TIMEOUT"`
+	DbStatementTimeout time.Duration `mapstructure:"DB_STATEMENT_TIMEOUT"`
+	// HTTP servers give clients HTTPReadHeaderTimeout to send the headers
+	// and HTTPReadTimeout the whole request, take at most HTTPWriteTimeout
+	// to answer and close keep-alive connections idle for HTTPIdleTimeout.
+	HTTPReadHeaderTimeout time.Duration `mapstructure:"HTTP_READ_HEADER_TIMEOUT"`
+	HTTPReadTimeout       time.Duration `mapstructure:"HTTP_READ_TIMEOUT"`
+	HTTPWriteTimeout      time.Duration `mapstructure:"HTTP_WRITE_TIMEOUT"`
+	HTTPIdleTimeout       time.Duration `mapstructure:"HTTP_IDLE_TIMEOUT"`
+	// RequestTimeouts lists the deadline of each route, see
+	// ParseRouteTimeouts. Handlers pass it on to their queries.
+	RequestTimeouts string `mapstructure:"REQUEST_TIMEOUTS"`
+	// The gRPC server pings connections idle for GrpcKeepaliveTime and
+	// drops them if the ping is not answered within GrpcKeepaliveTimeout.
+	// Clients pinging more often than GrpcKeepaliveMinTime are cut off.
+	GrpcKeepaliveTime    time.Duration `mapstructure:"GRPC_KEEPALIVE_TIME"`
+	GrpcKeepaliveTimeout time.Duration `mapstructure:"GRPC_KEEPALIVE_TIMEOUT"`
+	GrpcKeepaliveMinTime time.Duration `mapstructure:"GRPC_KEEPALIVE_MIN_TIME"`
+	// GrpcMaxRecvMsgSize and GrpcMaxSendMsgSize bound messages in bytes.
+	GrpcMaxRecvMsgSize int `mapstructure:"GRPC_MAX_RECV_MSG_SIZE"`
+	GrpcMaxSendMsgSize int `mapstructure:"GRPC_MAX_SEND_MSG_SIZE"`
+	// TLSCertFile and TLSKeyFile turn on TLS for the HTTP listener.
+	TLSCertFile string `mapstructure:"TLS_CERT_FILE"`
+	TLSKeyFile  string `mapstructure:"TLS_KEY_FILE"`
+	// GrpcTLSCertFile and GrpcTLSKeyFile turn on TLS for the gRPC
+	// listener; with GrpcTLSClientCAFile, clients must also present a
+	// certificate that CA issued.
+	GrpcTLSCertFile     string `mapstructure:"GRPC_TLS_CERT_FILE"`
+	GrpcTLSKeyFile      string `mapstructure:"GRPC_TLS_KEY_FILE"`
+	GrpcTLSClientCAFile string `mapstructure:"GRPC_TLS_CLIENT_CA_FILE"`
+	// The gateway connects to a gRPC server with TLS presenting
+	// GatewayTLSCertFile, if set, and trusting GatewayTLSCAFile, or the
+	// system's CAs if unset. GatewayTLSServerName is the name the server
+	// certificate must hold, the host of GrpcServerAddress by default,
+	// which must then name one.
+	GatewayTLSCertFile   string `mapstructure:"GATEWAY_TLS_CERT_FILE"`
+	GatewayTLSKeyFile    string `mapstructure:"GATEWAY_TLS_KEY_FILE"`
+	GatewayTLSCAFile     string `mapstructure:"GATEWAY_TLS_CA_FILE"`
+	GatewayTLSServerName string `mapstructure:"GATEWAY_TLS_SERVER_NAME"`
+	// SinglePort serves gRPC on the HTTP listener as well, told apart by
+	// content type over HTTP/2, negotiated with ALPN under TLS and h2c
+	// without. The gateway still reaches the gRPC server on
+	// GrpcServerAddress, which can then be kept to loopback. The HTTP
+	// listener asks for no client certificate, so this excludes
+	// GrpcTLSClientCAFile.
+	SinglePort bool `mapstructure:"SINGLE_PORT"`
 }
 
-func LoadConfig(path string) (config *Config, err error) {
+// Profiles tune defaults and validation to where the server runs.
+const (
+	ProfileDev  = "dev"
+	ProfileTest = "test"
+	ProfileProd = "prod"
+)
+
+// profileDefaults override the defaults of LoadConfig for a profile.
+var profileDefaults = map[string]map[string]any{
+	ProfileDev: {
+		"LOG_FORMAT": "text",
+		"LOG_LEVEL":  "debug",
+	},
+	ProfileTest: {
+		"MAILER":    "memory",
+		"LOG_LEVEL": "warn",
+	},
+	ProfileProd: {
+		"MAILER": "smtp",
+	},
+}
+
+// configExts are the config file formats LoadConfig looks for, in order.
+var configExts = []string{"env", "yaml", "yml", "toml"}
+
+// LoadConfig reads the configuration in layers, each overriding the ones
+// before it:
+//
+//  1. the defaults, then those of the profile named by APP_PROFILE, dev
+//     unless set;
+//  2. the file named by CONFIG_FILE or, if unset, the first of app.env,
+//     app.yaml, app.yml and app.toml in path, if any;
+//  3. app.<profile> in path with any of the same extensions, if any;
+//  4. environment variables.
+//
+// Without any file, everything comes from the environment. Any setting
+// can be read from the file named by <NAME>_FILE instead, such as
+// SYMMETRIC_KEY_FILE=/run/secrets/symmetric_key, so secrets stay out of
+// the environment and config files. Every invalid setting is reported,
+// not just the first.
+func LoadConfig(path string) (*Config, error) {
 	vp := viper.New()
-	vp.AddConfigPath(path)
-	vp.SetConfigName("app")
-	vp.SetConfigType("env")
 
-	vp.AutomaticEnv()
-	if err = vp.ReadInConfig(); err != nil {
+	vp.SetDefault("TRANSFER_HOLD_TTL", 7*24*time.Hour)
+	vp.SetDefault("HOLD_EXPIRY_PERIOD", time.Minute)
+	vp.SetDefault("OVERDRAFT_ANNUAL_RATE", 0)
+	vp.SetDefault("OVERDRAFT_DAILY_FEE", 0)
+	vp.SetDefault("OVERDRAFT_ACCRUAL_PERIOD", time.Hour)
+	vp.SetDefault("INTEREST_ACCRUAL_PERIOD", time.Hour)
+	vp.SetDefault("MAILER", "file")
+	vp.SetDefault("MAIL_DIR", "tmp/mail")
+	vp.SetDefault("SMTP_PORT", 587)
+	vp.SetDefault("EMAIL_VERIFY_TTL", 24*time.Hour)
+	vp.SetDefault("EMAIL_VERIFY_RESEND_LIMIT", 3)
+	vp.SetDefault("EMAIL_VERIFY_RESEND_WINDOW", time.Hour)
+	vp.SetDefault("PASSWORD_RESET_TTL", time.Hour)
+	vp.SetDefault("PASSWORD_RESET_LIMIT", 3)
+	vp.SetDefault("PASSWORD_RESET_WINDOW", time.Hour)
+	vp.SetDefault("TOTP_ISSUER", "Cedar Bank")
+	vp.SetDefault("SIGNIN_CHALLENGE_TTL", 5*time.Minute)
+	vp.SetDefault("STEP_UP_THRESHOLD", 10_000)
+	vp.SetDefault("STEP_UP_TTL", 5*time.Minute)
+	vp.SetDefault("SIGNIN_MAX_FAILURES", 5)
+	vp.SetDefault("SIGNIN_IP_MAX_FAILURES", 50)
+	vp.SetDefault("SIGNIN_LOCKOUT_DURATION", 15*time.Minute)
+	vp.SetDefault("SIGNIN_DELAY_BASE", time.Second)
+	vp.SetDefault("SIGNIN_DELAY_MAX", 30*time.Second)
+	vp.SetDefault("SIGNIN_FAILURE_WINDOW", time.Hour)
+	vp.SetDefault("RATE_LIMITER", "memory")
+	vp.SetDefault("PASSWORD_HASHER", "argon2id")
+	vp.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	vp.SetDefault("PASSWORD_MAX_LENGTH", 128)
+	vp.SetDefault("PASSWORD_MIN_CLASSES", 3)
+	vp.SetDefault("LOG_FORMAT", "json")
+	vp.SetDefault("LOG_LEVEL", "info")
+	vp.SetDefault("TRACING_EXPORTER", "none")
+	vp.SetDefault("TRACING_ENDPOINT", "localhost:4317")
+	vp.SetDefault("TRACING_SAMPLE_RATIO", 1)
+	vp.SetDefault("HEALTH_CHECK_INTERVAL", 10*time.Second)
+	vp.SetDefault("SHUTDOWN_TIMEOUT", 30*time.Second)
+	vp.SetDefault("DB_MAX_CONNS", 20)
+	vp.SetDefault("DB_MIN_CONNS", 2)
+	vp.SetDefault("DB_MAX_CONN_LIFETIME", time.Hour)
+	vp.SetDefault("DB_MAX_CONN_IDLE_TIME", 30*time.Minute)
+	vp.SetDefault("DB_CONNECT_TIMEOUT", 5*time.Second)
+	vp.SetDefault("DB_STATEMENT_TIMEOUT", 30*time.Second)
+	vp.SetDefault("HTTP_READ_HEADER_TIMEOUT", 5*time.Second)
+	vp.SetDefault("HTTP_READ_TIMEOUT", 15*time.Second)
+	vp.SetDefault("HTTP_WRITE_TIMEOUT", 30*time.Second)
+	vp.SetDefault("HTTP_IDLE_TIMEOUT", 2*time.Minute)
+	vp.SetDefault("REQUEST_TIMEOUTS", "*=15s")
+	vp.SetDefault("GRPC_KEEPALIVE_TIME", 2*time.Minute)
+	vp.SetDefault("GRPC_KEEPALIVE_TIMEOUT", 20*time.Second)
+	vp.SetDefault("GRPC_KEEPALIVE_MIN_TIME", 30*time.Second)
+	vp.SetDefault("GRPC_MAX_RECV_MSG_SIZE", 4<<20)
+	vp.SetDefault("GRPC_MAX_SEND_MSG_SIZE", 4<<20)
+	vp.SetDefault("SINGLE_PORT", false)
+
+	profile := os.Getenv("APP_PROFILE")
+
+	if profile == "" {
+		profile = ProfileDev
+	}
+
+	vp.SetDefault("APP_PROFILE", profile)
+
+	for key, value := range profileDefaults[profile] {
+		vp.SetDefault(key, value)
+	}
+
+	if err := readConfigFiles(vp, path, profile); err != nil {
 		return nil, err
 	}
 
-	err = vp.Unmarshal(&config)
-	return
+	keys := configKeys()
+
+	// Binding every key, rather than relying on AutomaticEnv, lets
+	// Unmarshal see settings that are only in the environment.
+	for _, key := range keys {
+		if err := vp.BindEnv(key); err != nil {
+			return nil, err
+		}
+
+		if err := vp.BindEnv(key + "_FILE"); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+
+	for _, key := range keys {
+		if err := readSecretFile(vp, key); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	var config Config
+
+	if err := vp.Unmarshal(&config); err != nil {
+		return nil, err
+	}
+
+	// The profile picks the files read, so it can only come from the
+	// environment.
+	config.Profile = profile
+
+	if err := errors.Join(append(errs, config.Validate())...); err != nil {
+		return nil, fmt.Errorf("invalid config:\n%w", err)
+	}
+
+	return &config, nil
+}
+
+func readConfigFiles(vp *viper.Viper, path, profile string) error {
+	base := os.Getenv("CONFIG_FILE")
+
+	if base == "" {
+		base = findConfigFile(path, "app")
+	}
+
+	if base != "" {
+		vp.SetConfigFile(base)
+
+		if err := vp.ReadInConfig(); err != nil {
+			return fmt.Errorf("cannot read config file %s: %w", base, err)
+		}
+	}
+
+	if overlay := findConfigFile(path, "app."+profile); overlay != "" {
+		vp.SetConfigFile(overlay)
+
+		if err := vp.MergeInConfig(); err != nil {
+			return fmt.Errorf("cannot read config file %s: %w", overlay, err)
+		}
+	}
+
+	return nil
+}
+
+// findConfigFile returns the first file in dir called name with one of
+// configExts, or "" if there is none.
+func findConfigFile(dir, name string) string {
+	for _, ext := range configExts {
+		file := filepath.Join(dir, name+"."+ext)
+
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file
+		}
+	}
+
+	return ""
+}
+
+// readSecretFile sets key to the contents of the file <key>_FILE names,
+// if any, less the trailing newline editors and echo add.
+func readSecretFile(vp *viper.Viper, key string) error {
+	file := vp.GetString(key + "_FILE")
+
+	if file == "" {
+		return nil
+	}
+
+	if _, ok := os.LookupEnv(key); ok {
+		return fmt.Errorf("%s and %s_FILE are both set", key, key)
+	}
+
+	content, err := os.ReadFile(file)
+
+	if err != nil {
+		return fmt.Errorf("%s_FILE: %w", key, err)
+	}
+
+	vp.Set(key, strings.TrimRight(string(content), "\r\n"))
+	return nil
+}
+
+// configKeys lists the setting names of Config fields.
+func configKeys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		if key := t.Field(i).Tag.Get("mapstructure"); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
 }
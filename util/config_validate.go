@@ -0,0 +1,186 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/mail"
+	"net/url"
+	"slices"
+	"time"
+)
+
+// symmetricKeySize is the key size of the PASETO v2 local tokens.
+const symmetricKeySize = 32
+
+// configValidator collects what is wrong with a config.
+type configValidator struct {
+	errs []error
+}
+
+func (v *configValidator) check(ok bool, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf(format, args...))
+	}
+}
+
+func (v *configValidator) required(key, value string) {
+	v.check(value != "", "%s is required", key)
+}
+
+func (v *configValidator) positive(key string, d time.Duration) {
+	v.check(d > 0, "%s must be positive, got %s", key, d)
+}
+
+func (v *configValidator) oneOf(key, value string, allowed ...string) {
+	v.check(slices.Contains(allowed, value), "%s must be one of %v, got %q", key, allowed, value)
+}
+
+func (v *configValidator) address(key, value string) {
+	if _, _, err := net.SplitHostPort(value); err != nil {
+		v.errs = append(v.errs, fmt.Errorf("%s must be host:port, got %q", key, value))
+	}
+}
+
+// pair checks two settings that only work together are both set or both
+// unset.
+func (v *configValidator) pair(key1, value1, key2, value2 string) {
+	v.check((value1 == "") == (value2 == ""), "%s and %s must be set together", key1, key2)
+}
+
+func (v *configValidator) url(key, value string) {
+	u, err := url.Parse(value)
+	v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "", "%s must be an absolute http(s) URL, got %q", key, value)
+}
+
+// Validate reports every setting that is missing or out of range, not
+// just the first.
+func (c *Config) Validate() error {
+	var v configValidator
+
+	v.oneOf("APP_PROFILE", c.Profile, ProfileDev, ProfileTest, ProfileProd)
+
+	v.required("DBSOURCE", c.DbSource)
+	v.address("HTTP_SERVER_ADDRESS", c.HttpServerAddress)
+	v.address("GRPC_SERVER_ADDRESS", c.GrpcServerAddress)
+
+	if c.MetricsServerAddress != "" {
+		v.address("METRICS_SERVER_ADDRESS", c.MetricsServerAddress)
+		v.check(c.MetricsServerAddress != c.HttpServerAddress && c.MetricsServerAddress != c.GrpcServerAddress,
+			"METRICS_SERVER_ADDRESS must differ from HTTP_SERVER_ADDRESS and GRPC_SERVER_ADDRESS")
+	}
+
+	v.check(len(c.SymmetricKey) == symmetricKeySize, "SYMMETRIC_KEY must be exactly %d bytes, got %d", symmetricKeySize, len(c.SymmetricKey))
+	v.positive("ACCESS_TOKEN_TIME", c.AccessTokenTime)
+	v.positive("REFRESH_TOKEN_TIME", c.RefreshTokenTime)
+	v.positive("TRANSFER_HOLD_TTL", c.TransferHoldTTL)
+	v.positive("HOLD_EXPIRY_PERIOD", c.HoldExpiryPeriod)
+	v.check(c.OverdraftAnnualRate >= 0, "OVERDRAFT_ANNUAL_RATE must not be negative")
+	v.check(c.OverdraftDailyFee >= 0, "OVERDRAFT_DAILY_FEE must not be negative")
+	v.positive("OVERDRAFT_ACCRUAL_PERIOD", c.OverdraftAccrualPeriod)
+	v.positive("INTEREST_ACCRUAL_PERIOD", c.InterestAccrualPeriod)
+
+	v.oneOf("MAILER", c.Mailer, "smtp", "file", "memory")
+
+	if c.Mailer != "memory" {
+		_, err := mail.ParseAddress(c.MailFrom)
+		v.check(err == nil, "MAIL_FROM must be an email address, got %q", c.MailFrom)
+	}
+
+	switch c.Mailer {
+	case "smtp":
+		v.required("SMTP_HOST", c.SMTPHost)
+		v.check(c.SMTPPort > 0 && c.SMTPPort < 1<<16, "SMTP_PORT must be a port number, got %d", c.SMTPPort)
+	case "file":
+		v.required("MAIL_DIR", c.MailDir)
+	}
+
+	v.url("EMAIL_VERIFY_URL", c.EmailVerifyURL)
+	v.positive("EMAIL_VERIFY_TTL", c.EmailVerifyTTL)
+	v.check(c.EmailVerifyResendLimit > 0, "EMAIL_VERIFY_RESEND_LIMIT must be positive")
+	v.positive("EMAIL_VERIFY_RESEND_WINDOW", c.EmailVerifyResendWindow)
+	v.url("PASSWORD_RESET_URL", c.PasswordResetURL)
+	v.positive("PASSWORD_RESET_TTL", c.PasswordResetTTL)
+	v.check(c.PasswordResetLimit > 0, "PASSWORD_RESET_LIMIT must be positive")
+	v.positive("PASSWORD_RESET_WINDOW", c.PasswordResetWindow)
+	v.required("TOTP_ISSUER", c.TOTPIssuer)
+	v.positive("SIGNIN_CHALLENGE_TTL", c.SigninChallengeTTL)
+	v.check(c.StepUpThreshold >= 0, "STEP_UP_THRESHOLD must not be negative")
+	v.positive("STEP_UP_TTL", c.StepUpTTL)
+
+	v.check(c.SigninMaxFailures >= 0, "SIGNIN_MAX_FAILURES must not be negative")
+	v.check(c.SigninIPMaxFailures >= 0, "SIGNIN_IP_MAX_FAILURES must not be negative")
+	v.check(c.SigninLockoutDuration >= 0, "SIGNIN_LOCKOUT_DURATION must not be negative")
+	v.positive("SIGNIN_DELAY_BASE", c.SigninDelayBase)
+	v.check(c.SigninDelayMax >= c.SigninDelayBase, "SIGNIN_DELAY_MAX must be at least SIGNIN_DELAY_BASE")
+	v.positive("SIGNIN_FAILURE_WINDOW", c.SigninFailureWindow)
+
+	v.oneOf("RATE_LIMITER", c.RateLimiter, "memory", "postgres")
+	v.oneOf("PASSWORD_HASHER", c.PasswordHasher, "argon2id", "bcrypt", "scrypt")
+	v.check(c.PasswordMinLength > 0, "PASSWORD_MIN_LENGTH must be positive")
+	v.check(c.PasswordMaxLength >= c.PasswordMinLength, "PASSWORD_MAX_LENGTH must be at least PASSWORD_MIN_LENGTH")
+	v.check(c.PasswordMinClasses >= 1 && c.PasswordMinClasses <= 4, "PASSWORD_MIN_CLASSES must be between 1 and 4")
+
+	v.oneOf("LOG_FORMAT", c.LogFormat, "json", "text")
+
+	var level slog.Level
+	v.check(level.UnmarshalText([]byte(c.LogLevel)) == nil, "LOG_LEVEL must be debug, info, warn or error, got %q", c.LogLevel)
+
+	v.oneOf("TRACING_EXPORTER", c.TracingExporter, "none", "otlp", "stdout")
+
+	if c.TracingExporter == "otlp" {
+		v.address("TRACING_ENDPOINT", c.TracingEndpoint)
+	}
+
+	v.check(c.TracingSampleRatio >= 0 && c.TracingSampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+	v.positive("HEALTH_CHECK_INTERVAL", c.HealthCheckInterval)
+	v.positive("SHUTDOWN_TIMEOUT", c.ShutdownTimeout)
+
+	v.check(c.DbMaxConns > 0, "DB_MAX_CONNS must be positive")
+	v.check(c.DbMinConns >= 0 && c.DbMinConns <= c.DbMaxConns, "DB_MIN_CONNS must be between 0 and DB_MAX_CONNS")
+	v.positive("DB_MAX_CONN_LIFETIME", c.DbMaxConnLifetime)
+	v.positive("DB_MAX_CONN_IDLE_TIME", c.DbMaxConnIdleTime)
+	v.positive("DB_CONNECT_TIMEOUT", c.DbConnectTimeout)
+	v.check(c.DbStatementTimeout >= time.Millisecond, "DB_STATEMENT_TIMEOUT must be at least 1ms")
+	v.positive("HTTP_READ_HEADER_TIMEOUT", c.HTTPReadHeaderTimeout)
+	v.check(c.HTTPReadTimeout >= c.HTTPReadHeaderTimeout, "HTTP_READ_TIMEOUT must be at least HTTP_READ_HEADER_TIMEOUT")
+	v.positive("HTTP_WRITE_TIMEOUT", c.HTTPWriteTimeout)
+	v.positive("HTTP_IDLE_TIMEOUT", c.HTTPIdleTimeout)
+	v.positive("GRPC_KEEPALIVE_TIME", c.GrpcKeepaliveTime)
+	v.positive("GRPC_KEEPALIVE_TIMEOUT", c.GrpcKeepaliveTimeout)
+	v.check(c.GrpcKeepaliveMinTime > 0 && c.GrpcKeepaliveMinTime <= c.GrpcKeepaliveTime, "GRPC_KEEPALIVE_MIN_TIME must be between 0 and GRPC_KEEPALIVE_TIME")
+	v.check(c.GrpcMaxRecvMsgSize > 0, "GRPC_MAX_RECV_MSG_SIZE must be positive")
+	v.check(c.GrpcMaxSendMsgSize > 0, "GRPC_MAX_SEND_MSG_SIZE must be positive")
+
+	if _, err := ParseRouteTimeouts(c.RequestTimeouts); err != nil {
+		v.errs = append(v.errs, fmt.Errorf("REQUEST_TIMEOUTS: %w", err))
+	}
+
+	v.pair("TLS_CERT_FILE", c.TLSCertFile, "TLS_KEY_FILE", c.TLSKeyFile)
+	v.pair("GRPC_TLS_CERT_FILE", c.GrpcTLSCertFile, "GRPC_TLS_KEY_FILE", c.GrpcTLSKeyFile)
+	v.pair("GATEWAY_TLS_CERT_FILE", c.GatewayTLSCertFile, "GATEWAY_TLS_KEY_FILE", c.GatewayTLSKeyFile)
+	v.check(c.GrpcTLSClientCAFile == "" || c.GrpcTLSCertFile != "", "GRPC_TLS_CLIENT_CA_FILE needs GRPC_TLS_CERT_FILE")
+	v.check(c.GrpcTLSClientCAFile == "" || c.GatewayTLSCertFile != "", "GRPC_TLS_CLIENT_CA_FILE needs GATEWAY_TLS_CERT_FILE for the gateway to authenticate with")
+
+	gatewayTLS := c.GatewayTLSCertFile != "" || c.GatewayTLSCAFile != "" || c.GatewayTLSServerName != ""
+	v.check(!gatewayTLS || c.GrpcTLSCertFile != "", "GATEWAY_TLS_* settings need GRPC_TLS_CERT_FILE")
+	// The HTTP listener asks for no client certificate.
+	v.check(!c.SinglePort || c.GrpcTLSClientCAFile == "", "SINGLE_PORT would serve gRPC without the client certificates GRPC_TLS_CLIENT_CA_FILE asks for")
+
+	if c.GrpcTLSCertFile != "" && c.GatewayTLSServerName == "" {
+		// A listen address such as 0.0.0.0:9090 names no host a
+		// certificate could hold.
+		host, _, err := net.SplitHostPort(c.GrpcServerAddress)
+		ip := net.ParseIP(host)
+		v.check(err == nil && host != "" && (ip == nil || !ip.IsUnspecified()), "GATEWAY_TLS_SERVER_NAME must be set when GRPC_SERVER_ADDRESS has no host, got %q", c.GrpcServerAddress)
+	}
+
+	if c.Profile == ProfileProd {
+		// Files and memory lose mail; debug logs request payloads.
+		v.check(c.Mailer == "smtp", "MAILER must be smtp in the prod profile")
+		v.check(c.LogLevel != "debug", "LOG_LEVEL must not be debug in the prod profile")
+	}
+
+	return errors.Join(v.errs...)
+}
@@ -1,18 +1,150 @@
 package util
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/codes"
 )
 
+// ErrorKind says what went wrong in terms a client can act on, and with
+// it the HTTP status and gRPC code an error is reported with.
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindInvalidArgument
+	KindUnauthenticated
+	KindPermissionDenied
+	KindNotFound
+	KindAlreadyExists
+	// KindConflict is a request the current state of a resource does not
+	// allow, such as capturing a hold that was already voided.
+	KindConflict
+	// KindExpired is a link or challenge that was valid once.
+	KindExpired
+	KindRateLimited
+	KindUnavailable
+	// KindDeadlineExceeded is a request that ran out of time, see
+	// RouteTimeouts.
+	KindDeadlineExceeded
+)
+
+var errorKinds = map[ErrorKind]struct {
+	httpStatus int
+	grpcCode   codes.Code
+}{
+	KindInternal:         {http.StatusInternalServerError, codes.Internal},
+	KindInvalidArgument:  {http.StatusBadRequest, codes.InvalidArgument},
+	KindUnauthenticated:  {http.StatusUnauthorized, codes.Unauthenticated},
+	KindPermissionDenied: {http.StatusForbidden, codes.PermissionDenied},
+	KindNotFound:         {http.StatusNotFound, codes.NotFound},
+	KindAlreadyExists:    {http.StatusConflict, codes.AlreadyExists},
+	KindConflict:         {http.StatusConflict, codes.FailedPrecondition},
+	KindExpired:          {http.StatusGone, codes.FailedPrecondition},
+	KindRateLimited:      {http.StatusTooManyRequests, codes.ResourceExhausted},
+	KindUnavailable:      {http.StatusServiceUnavailable, codes.Unavailable},
+	KindDeadlineExceeded: {http.StatusGatewayTimeout, codes.DeadlineExceeded},
+}
+
+func (k ErrorKind) HTTPStatus() int {
+	return errorKinds[k].httpStatus
+}
+
+func (k ErrorKind) GRPCCode() codes.Code {
+	return errorKinds[k].grpcCode
+}
+
+// FieldViolation is one thing wrong with one field of a request.
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// CustomError is an entry of the error catalog: a stable code clients can
+// branch on, the kind that decides how it is reported, and a default
+// English message that RegisterMessages can translate. Errors compare
+// equal under errors.Is when their codes match, whatever violations they
+// carry.
 type CustomError struct {
 	code string
 	msg  string
+	kind ErrorKind
+	// violations is a pointer so CustomError stays comparable.
+	violations *[]FieldViolation
 }
 
-func NewCustomError(code, msg string) CustomError {
-	return CustomError{
+var (
+	catalogMu sync.RWMutex
+	catalog   = map[string]CustomError{}
+	messages  = map[string]map[string]string{}
+)
+
+// NewCustomError adds an error to the catalog. Codes are part of the API,
+// so each may only be defined once; NewCustomError is meant for package
+// level vars and panics on a duplicate.
+func NewCustomError(kind ErrorKind, code, msg string) CustomError {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	if _, ok := catalog[code]; ok {
+		panic(fmt.Sprintf("error code %s defined twice", code))
+	}
+
+	e := CustomError{
 		code: code,
 		msg:  msg,
+		kind: kind,
+	}
+
+	catalog[code] = e
+	return e
+}
+
+// ErrorCatalog lists every defined error by code.
+func ErrorCatalog() []CustomError {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	list := make([]CustomError, 0, len(catalog))
+
+	for _, e := range catalog {
+		list = append(list, e)
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].code < list[j].code })
+	return list
+}
+
+// LookupError returns the catalog entry for code.
+func LookupError(code string) (CustomError, bool) {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	e, ok := catalog[code]
+	return e, ok
+}
+
+// RegisterMessages adds translations, by error code, for the language tag
+// lang such as "fr" or "pt-BR".
+func RegisterMessages(lang string, translations map[string]string) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	lang = strings.ToLower(lang)
+
+	if messages[lang] == nil {
+		messages[lang] = map[string]string{}
+	}
+
+	for code, msg := range translations {
+		messages[lang][code] = msg
 	}
 }
 
@@ -24,6 +156,51 @@ func (e CustomError) Code() string {
 	return e.code
 }
 
+func (e CustomError) Kind() ErrorKind {
+	return e.kind
+}
+
+func (e CustomError) Violations() []FieldViolation {
+	if e.violations == nil {
+		return nil
+	}
+
+	return *e.violations
+}
+
+// WithViolations returns a copy of e reporting violations as well.
+func (e CustomError) WithViolations(violations ...FieldViolation) CustomError {
+	all := append(append([]FieldViolation(nil), e.Violations()...), violations...)
+	e.violations = &all
+	return e
+}
+
+// Localize returns the message in the language an Accept-Language header
+// value prefers most among those with a translation, English otherwise.
+// A region falls back to its base language, so "fr-CA" uses "fr".
+func (e CustomError) Localize(acceptLanguage string) string {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	for _, lang := range parseAcceptLanguage(acceptLanguage) {
+		if lang == "en" || strings.HasPrefix(lang, "en-") {
+			return e.msg
+		}
+
+		if msg, ok := messages[lang][e.code]; ok {
+			return msg
+		}
+
+		if base, _, ok := strings.Cut(lang, "-"); ok {
+			if msg, ok := messages[base][e.code]; ok {
+				return msg
+			}
+		}
+	}
+
+	return e.msg
+}
+
 func (e CustomError) ErrorWithCode() string {
 	return fmt.Sprintf("%s:%s", e.code, e.msg)
 }
@@ -32,3 +209,85 @@ func (e CustomError) Is(target error) bool {
 	t, ok := target.(CustomError)
 	return ok && t.code == e.code
 }
+
+// AsCustomError finds the catalog error in err's chain. A request that
+// ran out of time is ErrDeadlineExceeded. Other errors outside the
+// catalog, such as a failed query, become ErrInternal so their details
+// never reach a client.
+func AsCustomError(err error) (CustomError, bool) {
+	var e CustomError
+
+	if errors.As(err, &e) {
+		return e, true
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrDeadlineExceeded, true
+	}
+
+	return ErrInternal, false
+}
+
+// parseAcceptLanguage returns the lower cased tags of an Accept-Language
+// value, most preferred first.
+func parseAcceptLanguage(value string) []string {
+	type tag struct {
+		lang string
+		q    float64
+	}
+
+	var tags []tag
+
+	for _, part := range strings.Split(value, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		if lang != "" && lang != "*" && q > 0 {
+			tags = append(tags, tag{strings.ToLower(lang), q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	langs := make([]string, len(tags))
+
+	for i, t := range tags {
+		langs[i] = t.lang
+	}
+
+	return langs
+}
+
+// ErrorBody is how both servers render an error over HTTP.
+type ErrorBody struct {
+	Code       string           `json:"code"`
+	Message    string           `json:"message"`
+	RequestID  string           `json:"request_id,omitempty"`
+	Violations []FieldViolation `json:"violations,omitempty"`
+	// Details carries whatever else a client needs to recover, such as the
+	// challenge to answer for ErrStepUpRequired.
+	Details any `json:"details,omitempty"`
+}
+
+// ErrorResponse is the whole body of a failed HTTP request.
+type ErrorResponse struct {
+	Status bool      `json:"status"`
+	Error  ErrorBody `json:"error"`
+}
+
+// Body renders e for the request with id requestID, in the language
+// acceptLanguage prefers.
+func (e CustomError) Body(requestID, acceptLanguage string) ErrorBody {
+	return ErrorBody{
+		Code:       e.code,
+		Message:    e.Localize(acceptLanguage),
+		RequestID:  requestID,
+		Violations: e.Violations(),
+	}
+}
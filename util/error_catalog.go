@@ -0,0 +1,24 @@
+package util
+
+// Errors shared by the REST and gRPC servers. Errors of a single domain
+// live next to it, such as db.ErrAccountNotFound; ErrorCatalog lists them
+// all.
+var (
+	ErrInternal        = NewCustomError(KindInternal, "ErrInternal", "something went wrong, try again later")
+	ErrInvalidArgument = NewCustomError(KindInvalidArgument, "ErrInvalidArgument", "request has invalid fields")
+	ErrRateLimited     = NewCustomError(KindRateLimited, "ErrRateLimited", "too many requests, try again later")
+	ErrRouteNotFound   = NewCustomError(KindNotFound, "ErrRouteNotFound", "no such endpoint")
+	// ErrDeadlineExceeded is a request that took longer than its route's
+	// timeout.
+	ErrDeadlineExceeded = NewCustomError(KindDeadlineExceeded, "ErrDeadlineExceeded", "request took too long, try again later")
+
+	ErrInvalidCredentials = NewCustomError(KindUnauthenticated, "ErrInvalidCredentials", "invalid credential email or password mismatch")
+	ErrSigninThrottled    = NewCustomError(KindRateLimited, "ErrSigninThrottled", "too many failed sign-in attempts, try again later")
+	ErrEmailNotVerified   = NewCustomError(KindPermissionDenied, "ErrEmailNotVerified", "email address not verified")
+	ErrNotAuthorized      = NewCustomError(KindPermissionDenied, "ErrNotAuthorized", "user not authorized")
+	ErrUserNotFound       = NewCustomError(KindNotFound, "ErrUserNotFound", "user not found")
+
+	ErrUsernameTaken = NewCustomError(KindAlreadyExists, "ErrUsernameTaken", "username already taken")
+	ErrEmailTaken    = NewCustomError(KindAlreadyExists, "ErrEmailTaken", "email already taken")
+	ErrWeakPassword  = NewCustomError(KindInvalidArgument, "ErrWeakPassword", "password does not meet the password policy")
+)
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCustomErrorDuplicate(t *testing.T) {
+	require.Panics(t, func() {
+		NewCustomError(KindInternal, ErrInternal.Code(), "again")
+	})
+}
+
+func TestCustomErrorIs(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", ErrInvalidArgument.WithViolations(FieldViolation{Field: "email", Description: "is required"}))
+
+	require.ErrorIs(t, err, ErrInvalidArgument)
+	require.False(t, errors.Is(err, ErrRateLimited))
+
+	customErr, ok := AsCustomError(err)
+	require.True(t, ok)
+	require.Len(t, customErr.Violations(), 1)
+	require.Empty(t, ErrInvalidArgument.Violations())
+
+	customErr, ok = AsCustomError(errors.New("no rows"))
+	require.False(t, ok)
+	require.Equal(t, ErrInternal, customErr)
+
+	customErr, ok = AsCustomError(fmt.Errorf("query: %w", context.DeadlineExceeded))
+	require.True(t, ok)
+	require.Equal(t, ErrDeadlineExceeded, customErr)
+}
+
+func TestLocalize(t *testing.T) {
+	RegisterMessages("tlh", map[string]string{ErrRateLimited.Code(): "tlh message"})
+	RegisterMessages("tlh-XX", map[string]string{ErrRateLimited.Code(): "tlh-XX message"})
+
+	testCases := []struct {
+		acceptLanguage string
+		message        string
+	}{
+		{"", ErrRateLimited.Error()},
+		{"fr", ErrRateLimited.Error()},
+		{"tlh", "tlh message"},
+		{"TLH-xx", "tlh-XX message"},
+		{"tlh-YY", "tlh message"},
+		{"en;q=0.9, tlh;q=0.5", ErrRateLimited.Error()},
+		{"de, tlh;q=0.5", "tlh message"},
+		{"tlh;q=0", ErrRateLimited.Error()},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.message, ErrRateLimited.Localize(tc.acceptLanguage), tc.acceptLanguage)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	require.Equal(t, "abc-123", RequestID("abc-123"))
+	require.NotEqual(t, "", RequestID(""))
+	require.NotEqual(t, "bad\nid", RequestID("bad\nid"))
+}
@@ -1,13 +1,28 @@
 package hash
 
 import (
-	"bytes"
+	"crypto/subtle"
 	"encoding/base64"
 	"errors"
+	"fmt"
+	"strings"
 
 	"golang.org/x/crypto/argon2"
 )
 
+var ErrIncompatibleVersion = errors.New("hash was made with an unsupported argon2 version")
+
+// argon2idPrefix starts every hash made by Argon2idHash.
+const argon2idPrefix = "$argon2id$"
+
+// Bounds on the parameters a stored hash may ask Verify to run with, so a
+// corrupt or planted hash cannot make a sign-in take gigabytes or minutes.
+const (
+	argon2idMaxMemory = 1 << 20 // KiB, 1 GiB
+	argon2idMaxTime   = 64
+)
+
+// Argon2idHash is the PasswordHasher for argon2id.
 type Argon2idHash struct {
 	// time represents the number of
 	// passed over the specified memory.
@@ -35,65 +50,108 @@ func NewArgon2idHash(time, saltLen uint32, memory uint32, threads uint8, keyLen
 	}
 }
 
-type HashSalt struct {
-	Hash, Salt []byte
-}
-
-func (hs *HashSalt) EncodeBase64() (string, string) {
-	return ArgonStringEncode(hs)
+// DefaultArgonHash holds the parameters new passwords are hashed with.
+// Hashes made with anything else are upgraded on the next sign-in, see
+// NeedsRehash, so these can be raised at any time.
+func DefaultArgonHash() *Argon2idHash {
+	return NewArgon2idHash(3, 16, 64*1024, 4, 32)
 }
 
-func ArgonStringEncode(hs *HashSalt) (string, string) {
-	hashedPasswordStr := base64.StdEncoding.EncodeToString(hs.Hash)
-	saltStr := base64.StdEncoding.EncodeToString(hs.Salt)
-	return hashedPasswordStr, saltStr
-}
+// Hash hashes password under a fresh random salt and encodes the result,
+// parameters included, in the PHC string format:
+//
+//	$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>
+func (a *Argon2idHash) Hash(password []byte) (string, error) {
+	salt, err := randomSecret(a.saltLen)
 
-func ArgonStringDecode(passwordHashStr, passwordSaltStr string) ([]byte, []byte) {
-	hashedPassword, _ := base64.StdEncoding.DecodeString(passwordHashStr)
-	salt, _ := base64.StdEncoding.DecodeString(passwordSaltStr)
-	return hashedPassword, salt
-}
-
-// GenerateHash using the password and provided salt.
-// If not salt value provided fallback to random value
-// generated of a given length.
-func (a *Argon2idHash) GenerateHash(password, salt []byte) (*HashSalt, error) {
-	var err error
-	// If salt is not provided generate a salt of
-	// the configured salt length.
-	if len(salt) == 0 {
-		salt, err = randomSecret(a.saltLen)
-	}
 	if err != nil {
-		return nil, err
+		return "", err
 	}
-	// Generate hash
-	hash := argon2.IDKey(password, salt, a.time, a.memory, a.threads, a.keyLen)
-	// Return the generated hash and salt used for storage.
-	return &HashSalt{Hash: hash, Salt: salt}, nil
+
+	key := argon2.IDKey(password, salt, a.time, a.memory, a.threads, a.keyLen)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, a.memory, a.time, a.threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
-// Compare generated hash with store hash.
-func (a *Argon2idHash) Compare(hash, salt, password []byte) error {
-	// Generate hash for comparison.
-	hashSalt, err := a.GenerateHash(password, salt)
+// Verify checks password against a PHC encoded argon2id hash using the
+// parameters recorded in it rather than a's. The comparison takes constant
+// time.
+func (a *Argon2idHash) Verify(encoded string, password []byte) error {
+	params, salt, key, err := decodeArgon2id(encoded)
+
 	if err != nil {
 		return err
 	}
-	// Compare the generated hash with the stored hash.
-	// If they don't match return error.
-	if !bytes.Equal(hash, hashSalt.Hash) {
-		return errors.New("hash doesn't match")
+
+	other := argon2.IDKey(password, salt, params.time, params.memory, params.threads, params.keyLen)
+
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatchedPassword
 	}
+
 	return nil
 }
 
-type ArgonHashPayload struct {
-	PasswordHash string
-	PasswordSalt string
+// NeedsRehash reports whether encoded was made with parameters other than
+// a's, or cannot be read at all.
+func (a *Argon2idHash) NeedsRehash(encoded string) bool {
+	params, _, _, err := decodeArgon2id(encoded)
+
+	if err != nil {
+		return true
+	}
+
+	return *params != *a
 }
 
-func DefaultArgonHash() *Argon2idHash {
-	return NewArgon2idHash(1, 32, 64*1024, 32, 256)
+func decodeArgon2id(encoded string) (*Argon2idHash, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	var version int
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	if version != argon2.Version {
+		return nil, nil, nil, ErrIncompatibleVersion
+	}
+
+	params := &Argon2idHash{}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	// argon2.IDKey panics on zero rounds or threads.
+	if params.time == 0 || params.time > argon2idMaxTime ||
+		params.threads == 0 ||
+		params.memory < 8*uint32(params.threads) || params.memory > argon2idMaxMemory {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.Strict().DecodeString(parts[4])
+
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.Strict().DecodeString(parts[5])
+
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	params.saltLen = uint32(len(salt))
+	params.keyLen = uint32(len(key))
+
+	return params, salt, key, nil
 }
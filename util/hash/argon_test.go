@@ -1,6 +1,7 @@
 package hash
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -10,30 +11,73 @@ func TestPassword(t *testing.T) {
 	argonHash := DefaultArgonHash()
 	password := "verysecurepassword"
 
-	passwordHash, err := argonHash.GenerateHash([]byte(password), nil)
+	encoded, err := argonHash.Hash([]byte(password))
 
 	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=65536,t=3,p=4$"))
 
-	passwordHashStr, passwordSaltStr := ArgonStringEncode(passwordHash)
-
-	passwordHashByte, passwordSaltByte := ArgonStringDecode(passwordHashStr, passwordSaltStr)
-	err = argonHash.Compare(passwordHashByte, passwordSaltByte, []byte(password))
+	err = Verify(encoded, []byte(password))
 	require.NoError(t, err)
+	require.False(t, argonHash.NeedsRehash(encoded))
 }
 
 func TestIncorrectPassword(t *testing.T) {
 	argonHash := DefaultArgonHash()
 	password := "verysecurepassword"
 
-	passwordHash, err := argonHash.GenerateHash([]byte(password), nil)
+	encoded, err := argonHash.Hash([]byte(password))
 
 	require.NoError(t, err)
 
-	passwordHashStr, passwordSaltStr := ArgonStringEncode(passwordHash)
+	enteredPassword := "wrongpassword"
+	err = Verify(encoded, []byte(enteredPassword))
+	require.ErrorIs(t, err, ErrMismatchedPassword)
+}
 
-	passwordHashByte, passwordSaltByte := ArgonStringDecode(passwordHashStr, passwordSaltStr)
+func TestVerifyUsesEncodedParameters(t *testing.T) {
+	old := NewArgon2idHash(1, 8, 8*1024, 1, 16)
+	password := []byte("verysecurepassword")
 
-	enteredPassword := "wrongpassword"
-	err = argonHash.Compare(passwordHashByte, passwordSaltByte, []byte(enteredPassword))
-	require.Error(t, err)
+	encoded, err := old.Hash(password)
+	require.NoError(t, err)
+
+	require.NoError(t, Verify(encoded, password))
+	require.False(t, old.NeedsRehash(encoded))
+	require.True(t, DefaultArgonHash().NeedsRehash(encoded))
+}
+
+// Hashes stored before the PHC format were migrated by wrapping the bare
+// base64 salt and key with the parameters used back then.
+func TestVerifyMigratedHash(t *testing.T) {
+	legacySalt := "c29tZXNhbHQ="
+	legacyHash := "yOmu3JVvan3/Ck1ClA32KGI/Mo6hI1AFq6yTPFcJPiM="
+
+	encoded := "$argon2id$v=19$m=1024,t=1,p=1$" +
+		strings.TrimRight(legacySalt, "=") + "$" + strings.TrimRight(legacyHash, "=")
+
+	require.NoError(t, Verify(encoded, []byte("password")))
+	require.ErrorIs(t, Verify(encoded, []byte("passwore")), ErrMismatchedPassword)
+}
+
+func TestVerifyInvalidHash(t *testing.T) {
+	for _, encoded := range []string{
+		"",
+		"c29tZWhhc2g=",
+		"$argon2i$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$c29tZWhhc2g",
+		"$argon2id$v=19$m=65536,t=3$c29tZXNhbHQ$c29tZWhhc2g",
+		"$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ=$c29tZWhhc2g",
+		"$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$",
+		"$argon2id$v=19$m=65536,t=0,p=4$c29tZXNhbHQ$c29tZWhhc2g",
+		"$argon2id$v=19$m=65536,t=65,p=4$c29tZXNhbHQ$c29tZWhhc2g",
+		"$argon2id$v=19$m=65536,t=3,p=0$c29tZXNhbHQ$c29tZWhhc2g",
+		"$argon2id$v=19$m=65536,t=3,p=300$c29tZXNhbHQ$c29tZWhhc2g",
+		"$argon2id$v=19$m=16,t=3,p=4$c29tZXNhbHQ$c29tZWhhc2g",
+		"$argon2id$v=19$m=4194304,t=3,p=4$c29tZXNhbHQ$c29tZWhhc2g",
+	} {
+		require.ErrorIs(t, Verify(encoded, []byte("password")), ErrInvalidHash, encoded)
+		require.True(t, DefaultArgonHash().NeedsRehash(encoded))
+	}
+
+	err := Verify("$argon2id$v=16$m=65536,t=3,p=4$c29tZXNhbHQ$c29tZWhhc2g", []byte("password"))
+	require.ErrorIs(t, err, ErrIncompatibleVersion)
 }
@@ -0,0 +1,68 @@
+package hash
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptHash is the PasswordHasher for bcrypt, kept for the hashes of
+// users migrated from systems that used it. bcrypt only looks at the first
+// 72 bytes of a password and refuses to hash longer ones.
+type BcryptHash struct {
+	cost int
+}
+
+func NewBcryptHash(cost int) *BcryptHash {
+	return &BcryptHash{cost: cost}
+}
+
+func DefaultBcryptHash() *BcryptHash {
+	return NewBcryptHash(12)
+}
+
+// isBcrypt reports whether encoded is in the modular crypt format of
+// bcrypt, $2a$, $2b$ or $2y$ followed by the cost.
+func isBcrypt(encoded string) bool {
+	for _, prefix := range []string{"$2a$", "$2b$", "$2y$"} {
+		if strings.HasPrefix(encoded, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *BcryptHash) Hash(password []byte) (string, error) {
+	encoded, err := bcrypt.GenerateFromPassword(password, b.cost)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+func (b *BcryptHash) Verify(encoded string, password []byte) error {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), password)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return ErrMismatchedPassword
+	}
+
+	return ErrInvalidHash
+}
+
+func (b *BcryptHash) NeedsRehash(encoded string) bool {
+	if !isBcrypt(encoded) {
+		return true
+	}
+
+	cost, err := bcrypt.Cost([]byte(encoded))
+
+	return err != nil || cost != b.cost
+}
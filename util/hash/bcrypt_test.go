@@ -0,0 +1,43 @@
+package hash
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBcryptHash(t *testing.T) {
+	hasher := NewBcryptHash(bcrypt.MinCost)
+	password := []byte("verysecurepassword")
+
+	encoded, err := hasher.Hash(password)
+	require.NoError(t, err)
+
+	require.NoError(t, hasher.Verify(encoded, password))
+	require.ErrorIs(t, hasher.Verify(encoded, []byte("wrongpassword")), ErrMismatchedPassword)
+
+	require.False(t, hasher.NeedsRehash(encoded))
+	require.True(t, DefaultBcryptHash().NeedsRehash(encoded))
+	require.True(t, hasher.NeedsRehash("$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHQ$c29tZWhhc2g"))
+}
+
+// Hashes exported by other bcrypt implementations carry the $2y$ or $2b$
+// prefix; the algorithm is the same.
+func TestBcryptLegacyPrefixes(t *testing.T) {
+	encoded, err := NewBcryptHash(bcrypt.MinCost).Hash([]byte("verysecurepassword"))
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "$2a$"))
+
+	for _, prefix := range []string{"$2y$", "$2b$"} {
+		legacy := prefix + strings.TrimPrefix(encoded, "$2a$")
+
+		require.NoError(t, Verify(legacy, []byte("verysecurepassword")), legacy)
+		require.ErrorIs(t, Verify(legacy, []byte("wrongpassword")), ErrMismatchedPassword, legacy)
+	}
+}
+
+func TestBcryptInvalidHash(t *testing.T) {
+	require.ErrorIs(t, Verify("$2b$10$tooshort", []byte("password")), ErrInvalidHash)
+}
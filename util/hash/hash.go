@@ -1,6 +1,70 @@
 package hash
 
-import "crypto/rand"
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrMismatchedPassword = errors.New("hash doesn't match")
+	ErrInvalidHash        = errors.New("hash is not in a supported format")
+)
+
+// PasswordHasher hashes passwords into strings that name their algorithm
+// and parameters, so hashes made under different settings can live side
+// by side.
+type PasswordHasher interface {
+	// Hash hashes password under a fresh random salt.
+	Hash(password []byte) (string, error)
+	// Verify checks password against a hash made by this algorithm, with
+	// whatever parameters are recorded in it.
+	Verify(encoded string, password []byte) error
+	// NeedsRehash reports whether encoded was made by another algorithm
+	// or with other parameters than the hasher's own.
+	NeedsRehash(encoded string) bool
+}
+
+// NewPasswordHasher returns the hasher for algorithm, argon2id, bcrypt or
+// scrypt, with its default parameters.
+func NewPasswordHasher(algorithm string) (PasswordHasher, error) {
+	switch algorithm {
+	case "argon2id":
+		return DefaultArgonHash(), nil
+	case "bcrypt":
+		return DefaultBcryptHash(), nil
+	case "scrypt":
+		return DefaultScryptHash(), nil
+	}
+
+	return nil, fmt.Errorf("unknown password hasher %q", algorithm)
+}
+
+// Verify checks password against a hash made by any supported algorithm,
+// picked by the hash's prefix.
+func Verify(encoded string, password []byte) error {
+	hasher, ok := hasherFor(encoded)
+
+	if !ok {
+		return ErrInvalidHash
+	}
+
+	return hasher.Verify(encoded, password)
+}
+
+func hasherFor(encoded string) (PasswordHasher, bool) {
+	switch {
+	case strings.HasPrefix(encoded, argon2idPrefix):
+		return &Argon2idHash{}, true
+	case isBcrypt(encoded):
+		return &BcryptHash{}, true
+	case strings.HasPrefix(encoded, scryptPrefix):
+		return &ScryptHash{}, true
+	}
+
+	return nil, false
+}
 
 func randomSecret(length uint32) ([]byte, error) {
 	secret := make([]byte, length)
@@ -0,0 +1,40 @@
+package hash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPasswordHasher(t *testing.T) {
+	for algorithm, want := range map[string]PasswordHasher{
+		"argon2id": DefaultArgonHash(),
+		"bcrypt":   DefaultBcryptHash(),
+		"scrypt":   DefaultScryptHash(),
+	} {
+		hasher, err := NewPasswordHasher(algorithm)
+		require.NoError(t, err)
+		require.Equal(t, want, hasher)
+	}
+
+	_, err := NewPasswordHasher("md5")
+	require.Error(t, err)
+}
+
+func TestVerifyDispatchesOnPrefix(t *testing.T) {
+	password := []byte("verysecurepassword")
+
+	for _, hasher := range []PasswordHasher{
+		NewArgon2idHash(1, 16, 8*1024, 1, 32),
+		NewBcryptHash(4),
+		NewScryptHash(10, 8, 1, 32, 16),
+	} {
+		encoded, err := hasher.Hash(password)
+		require.NoError(t, err)
+
+		require.NoError(t, Verify(encoded, password), encoded)
+		require.ErrorIs(t, Verify(encoded, []byte("wrongpassword")), ErrMismatchedPassword, encoded)
+
+		require.True(t, DefaultArgonHash().NeedsRehash(encoded), encoded)
+	}
+}
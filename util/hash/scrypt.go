@@ -0,0 +1,126 @@
+package hash
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// scryptPrefix starts every hash made by ScryptHash.
+const scryptPrefix = "$scrypt$"
+
+// ScryptHash is the PasswordHasher for scrypt. Hashes are encoded like
+// argon2id ones, with the cost as its base two logarithm:
+//
+//	$scrypt$ln=15,r=8,p=1$<salt>$<hash>
+type ScryptHash struct {
+	// logN is the log2 of the CPU/memory cost N.
+	logN uint8
+	// blockSize r and parallelism p.
+	r, p    int
+	keyLen  int
+	saltLen uint32
+}
+
+func NewScryptHash(logN uint8, r, p, keyLen int, saltLen uint32) *ScryptHash {
+	return &ScryptHash{
+		logN:    logN,
+		r:       r,
+		p:       p,
+		keyLen:  keyLen,
+		saltLen: saltLen,
+	}
+}
+
+func DefaultScryptHash() *ScryptHash {
+	return NewScryptHash(15, 8, 1, 32, 16)
+}
+
+func (s *ScryptHash) Hash(password []byte) (string, error) {
+	salt, err := randomSecret(s.saltLen)
+
+	if err != nil {
+		return "", err
+	}
+
+	key, err := scrypt.Key(password, salt, 1<<s.logN, s.r, s.p, s.keyLen)
+
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%sln=%d,r=%d,p=%d$%s$%s",
+		scryptPrefix, s.logN, s.r, s.p,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (s *ScryptHash) Verify(encoded string, password []byte) error {
+	params, salt, key, err := decodeScrypt(encoded)
+
+	if err != nil {
+		return err
+	}
+
+	other, err := scrypt.Key(password, salt, 1<<params.logN, params.r, params.p, params.keyLen)
+
+	if err != nil {
+		return ErrInvalidHash
+	}
+
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return ErrMismatchedPassword
+	}
+
+	return nil
+}
+
+func (s *ScryptHash) NeedsRehash(encoded string) bool {
+	params, _, _, err := decodeScrypt(encoded)
+
+	if err != nil {
+		return true
+	}
+
+	return *params != *s
+}
+
+func decodeScrypt(encoded string) (*ScryptHash, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+
+	if len(parts) != 5 || parts[0] != "" || parts[1] != "scrypt" {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	params := &ScryptHash{}
+
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &params.logN, &params.r, &params.p); err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	// scrypt.Key rejects the rest, but N has to fit an int first.
+	if params.logN == 0 || params.logN > 30 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	salt, err := base64.RawStdEncoding.Strict().DecodeString(parts[3])
+
+	if err != nil {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	key, err := base64.RawStdEncoding.Strict().DecodeString(parts[4])
+
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, ErrInvalidHash
+	}
+
+	params.saltLen = uint32(len(salt))
+	params.keyLen = len(key)
+
+	return params, salt, key, nil
+}
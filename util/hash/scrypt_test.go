@@ -0,0 +1,35 @@
+package hash
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScryptHash(t *testing.T) {
+	hasher := NewScryptHash(10, 8, 1, 32, 16)
+	password := []byte("verysecurepassword")
+
+	encoded, err := hasher.Hash(password)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "$scrypt$ln=10,r=8,p=1$"))
+
+	require.NoError(t, hasher.Verify(encoded, password))
+	require.ErrorIs(t, hasher.Verify(encoded, []byte("wrongpassword")), ErrMismatchedPassword)
+
+	require.False(t, hasher.NeedsRehash(encoded))
+	require.True(t, DefaultScryptHash().NeedsRehash(encoded))
+}
+
+func TestScryptInvalidHash(t *testing.T) {
+	for _, encoded := range []string{
+		"$scrypt$ln=10,r=8$c29tZXNhbHQ$c29tZWhhc2g",
+		"$scrypt$ln=0,r=8,p=1$c29tZXNhbHQ$c29tZWhhc2g",
+		"$scrypt$ln=40,r=8,p=1$c29tZXNhbHQ$c29tZWhhc2g",
+		"$scrypt$ln=10,r=8,p=1$c29tZXNhbHQ=$c29tZWhhc2g",
+		"$scrypt$ln=10,r=8,p=1$c29tZXNhbHQ$",
+	} {
+		require.ErrorIs(t, Verify(encoded, []byte("password")), ErrInvalidHash, encoded)
+	}
+}
@@ -0,0 +1,110 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// Redacted replaces the value of sensitive log attributes.
+const Redacted = "[REDACTED]"
+
+// sensitiveKeyParts mark an attribute key, such as new_password or
+// refresh_token, whose value must never be logged.
+var sensitiveKeyParts = []string{"password", "token", "secret", "authorization", "cookie"}
+
+// sensitiveKeys are keys too short to match by part without catching
+// harmless ones, such as the one-time code of a 2FA sign-in.
+var sensitiveKeys = map[string]bool{"code": true, "otp": true}
+
+type loggerKey struct{}
+
+// NewLogger returns a logger writing to w in format, json or text, at
+// level, one of debug, info, warn or error. Sensitive attributes are
+// redacted, see IsSensitiveKey.
+func NewLogger(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{
+		Level:       lvl,
+		ReplaceAttr: redactAttr,
+	}
+
+	switch format {
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unsupported log format %q", format)
+	}
+}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// Logger returns the logger of the request ctx belongs to, which tags its
+// records with the request id, or the default logger outside of one.
+func Logger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+// IsSensitiveKey reports whether values under key, a log attribute or a
+// request field, must be redacted.
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+
+	if sensitiveKeys[key] {
+		return true
+	}
+
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RedactQuery returns rawQuery with the values of sensitive parameters,
+// such as the token of an email verification link, redacted.
+func RedactQuery(rawQuery string) string {
+	params := strings.Split(rawQuery, "&")
+
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+
+		name, err := url.QueryUnescape(key)
+
+		if err != nil {
+			name = key
+		}
+
+		if IsSensitiveKey(name) {
+			params[i] = key + "=" + Redacted
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+func redactAttr(_ []string, a slog.Attr) slog.Attr {
+	if IsSensitiveKey(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+
+	return a
+}
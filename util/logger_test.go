@@ -0,0 +1,46 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(&buf, "json", "info")
+	require.NoError(t, err)
+
+	logger.Debug("hidden")
+	logger.Info("sign-in", "user_id", 7, "new_password", "hunter2",
+		slog.Group("request", "refresh_token", "abc", "code", "123456", "username", "jdoe"))
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+
+	require.Equal(t, "sign-in", record["msg"])
+	require.EqualValues(t, 7, record["user_id"])
+	require.Equal(t, Redacted, record["new_password"])
+	require.Equal(t, map[string]any{
+		"refresh_token": Redacted,
+		"code":          Redacted,
+		"username":      "jdoe",
+	}, record["request"])
+
+	_, err = NewLogger(&buf, "xml", "info")
+	require.Error(t, err)
+
+	_, err = NewLogger(&buf, "json", "loud")
+	require.Error(t, err)
+}
+
+func TestRedactQuery(t *testing.T) {
+	require.Equal(t, "", RedactQuery(""))
+	require.Equal(t, "token=[REDACTED]&page=2", RedactQuery("token=abc&page=2"))
+	require.Equal(t, "page=2&Access%5FToken=[REDACTED]", RedactQuery("page=2&Access%5FToken=abc"))
+	require.Equal(t, "code=[REDACTED]&currency=USD", RedactQuery("code&currency=USD"))
+}
@@ -0,0 +1,41 @@
+package util
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request id in both directions. Clients may
+// set it to correlate their own logs; otherwise one is generated.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLen bounds what a client can make us log and echo.
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// RequestID returns id if it is fit to echo back, and a new one otherwise.
+func RequestID(id string) string {
+	if id == "" || len(id) > maxRequestIDLen {
+		return uuid.NewString()
+	}
+
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return uuid.NewString()
+		}
+	}
+
+	return id
+}
+
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the id WithRequestID stored, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,28 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// NewSecretToken returns a random URL-safe token to hand to a user, along
+// with the hash to store in its place. Tokens are looked up by HashSecretToken.
+func NewSecretToken() (token, hash string, err error) {
+	b := make([]byte, 32)
+
+	if _, err = rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	token = base64.RawURLEncoding.EncodeToString(b)
+	return token, HashSecretToken(token), nil
+}
+
+// HashSecretToken hashes a token from NewSecretToken for storage and lookup.
+// Tokens carry 256 bits of entropy, so a plain SHA-256 is sufficient.
+func HashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
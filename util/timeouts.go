@@ -0,0 +1,66 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultRoute is the route of the timeout applied to routes without one
+// of their own.
+const DefaultRoute = "*"
+
+// RouteTimeouts maps routes to how long requests to them may take, from
+// the handler down to the queries it runs. Routes are "METHOD /path" as
+// registered in Gin, or the full gRPC method name.
+type RouteTimeouts map[string]time.Duration
+
+// ParseRouteTimeouts reads a semicolon separated list of
+// <route>=<duration>, such as "POST /transfer=10s;*=15s". The route "*"
+// applies to every route not listed.
+func ParseRouteTimeouts(spec string) (RouteTimeouts, error) {
+	timeouts := RouteTimeouts{}
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+
+		if entry == "" {
+			continue
+		}
+
+		route, value, ok := strings.Cut(entry, "=")
+		route = strings.TrimSpace(route)
+
+		if !ok || route == "" {
+			return nil, fmt.Errorf("request timeout %q: expected <route>=<duration>", entry)
+		}
+
+		timeout, err := time.ParseDuration(strings.TrimSpace(value))
+
+		if err != nil {
+			return nil, fmt.Errorf("request timeout %q: %w", entry, err)
+		}
+
+		if timeout <= 0 {
+			return nil, fmt.Errorf("request timeout %q: duration must be positive", entry)
+		}
+
+		if _, dup := timeouts[route]; dup {
+			return nil, fmt.Errorf("request timeout %q: route listed twice", entry)
+		}
+
+		timeouts[route] = timeout
+	}
+
+	return timeouts, nil
+}
+
+// For returns the timeout of route, falling back to the "*" one. Zero
+// means requests to route run without a deadline of their own.
+func (t RouteTimeouts) For(route string) time.Duration {
+	if timeout, ok := t[route]; ok {
+		return timeout
+	}
+
+	return t[DefaultRoute]
+}
@@ -0,0 +1,26 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRouteTimeouts(t *testing.T) {
+	timeouts, err := ParseRouteTimeouts(" POST /transfer=10s; /pb.SimpleBank/SigninUser = 500ms ;*=15s;")
+	require.NoError(t, err)
+
+	require.Equal(t, 10*time.Second, timeouts.For("POST /transfer"))
+	require.Equal(t, 500*time.Millisecond, timeouts.For("/pb.SimpleBank/SigninUser"))
+	require.Equal(t, 15*time.Second, timeouts.For("GET /accounts/:id"))
+
+	timeouts, err = ParseRouteTimeouts("")
+	require.NoError(t, err)
+	require.Zero(t, timeouts.For("POST /transfer"))
+
+	for _, spec := range []string{"POST /transfer", "=10s", "*=soon", "*=0s", "*=-1s", "*=1s;*=2s"} {
+		_, err := ParseRouteTimeouts(spec)
+		require.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// dailyFunc processes every account for one UTC date, reporting how many
+// it skipped over an error. An error of its own stops the run.
+type dailyFunc func(ctx context.Context, date time.Time) (skipped int, err error)
+
+// runDaily calls fn for each date from the first one job has not finished
+// through the day before today, so the days a job missed while the
+// service was down are caught up. Finished dates are kept as the job's
+// watermark, which only moves past dates without skipped accounts; the
+// next run starts over from the first date an account was skipped on.
+func runDaily(ctx context.Context, store db.Store, job string, today time.Time, fn dailyFunc) error {
+	from, err := firstPendingDate(ctx, store, job, today)
+
+	if err != nil {
+		return err
+	}
+
+	advance := true
+
+	for date := from; date.Before(today); date = date.AddDate(0, 0, 1) {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		skipped, err := fn(ctx, date)
+
+		if err != nil {
+			return err
+		}
+
+		if skipped > 0 {
+			advance = false
+		}
+
+		if !advance {
+			continue
+		}
+
+		err = store.SetJobWatermark(ctx, db.SetJobWatermarkParams{
+			Job:         job,
+			DoneThrough: pgtype.Date{Time: date, Valid: true},
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// firstPendingDate is the day after job's watermark. A job that never
+// finished a date starts from the day the oldest account was opened.
+func firstPendingDate(ctx context.Context, store db.Store, job string, today time.Time) (time.Time, error) {
+	watermark, err := store.GetJobWatermark(ctx, job)
+
+	if err == nil {
+		return watermark.DoneThrough.Time.AddDate(0, 0, 1), nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, err
+	}
+
+	opened, err := store.GetOldestAccountCreatedAt(ctx)
+
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if !opened.Valid {
+		return today, nil
+	}
+
+	return opened.Time.UTC().Truncate(24 * time.Hour), nil
+}
@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func expectWatermark(store *mockdb.MockStore, job string, doneThrough time.Time) {
+	store.EXPECT().
+		GetJobWatermark(gomock.Any(), gomock.Eq(job)).
+		Times(1).
+		Return(db.JobWatermark{Job: job, DoneThrough: pgtype.Date{Time: doneThrough, Valid: true}}, nil)
+}
+
+func expectSetWatermark(store *mockdb.MockStore, job string, doneThrough time.Time) {
+	store.EXPECT().
+		SetJobWatermark(gomock.Any(), gomock.Eq(db.SetJobWatermarkParams{
+			Job:         job,
+			DoneThrough: pgtype.Date{Time: doneThrough, Valid: true},
+		})).
+		Times(1)
+}
+
+func TestRunDailyFromOldestAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	today := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	store.EXPECT().
+		GetJobWatermark(gomock.Any(), gomock.Eq("test")).
+		Times(1).
+		Return(db.JobWatermark{}, pgx.ErrNoRows)
+
+	store.EXPECT().
+		GetOldestAccountCreatedAt(gomock.Any()).
+		Times(1).
+		Return(pgtype.Timestamptz{Time: time.Date(2024, 3, 8, 15, 4, 0, 0, time.UTC), Valid: true}, nil)
+
+	expectSetWatermark(store, "test", time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC))
+	expectSetWatermark(store, "test", time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC))
+
+	var dates []time.Time
+
+	err := runDaily(context.Background(), store, "test", today, func(ctx context.Context, date time.Time) (int, error) {
+		dates = append(dates, date)
+		return 0, nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, []time.Time{
+		time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+	}, dates)
+}
+
+func TestRunDailyWithoutAccounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		GetJobWatermark(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.JobWatermark{}, pgx.ErrNoRows)
+
+	store.EXPECT().
+		GetOldestAccountCreatedAt(gomock.Any()).
+		Times(1).
+		Return(pgtype.Timestamptz{}, nil)
+
+	store.EXPECT().SetJobWatermark(gomock.Any(), gomock.Any()).Times(0)
+
+	err := runDaily(context.Background(), store, "test", time.Now().UTC().Truncate(24*time.Hour), func(ctx context.Context, date time.Time) (int, error) {
+		t.Fatal("no date is due without accounts")
+		return 0, nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,41 @@
+package worker
+
+import (
+	"context"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+)
+
+const expireHoldsBatchSize = 100
+
+// ExpireHoldsJob releases pending transfer holds past their TTL.
+type ExpireHoldsJob struct {
+	store db.Store
+}
+
+func NewExpireHoldsJob(store db.Store) *ExpireHoldsJob {
+	return &ExpireHoldsJob{store: store}
+}
+
+func (j *ExpireHoldsJob) Name() string {
+	return "expire_transfer_holds"
+}
+
+func (j *ExpireHoldsJob) Run(ctx context.Context) error {
+	for {
+		expired, err := j.store.ExpireTransferHoldsTx(ctx, expireHoldsBatchSize)
+
+		if err != nil {
+			return err
+		}
+
+		if len(expired) > 0 {
+			util.Logger(ctx).Info("expired transfer holds", "count", len(expired))
+		}
+
+		if len(expired) < expireHoldsBatchSize {
+			return nil
+		}
+	}
+}
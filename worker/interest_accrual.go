@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const interestBatchSize = 100
+
+// InterestAccrualJob accrues daily interest on savings accounts for every
+// UTC day through the previous one, catching up on days it missed, and
+// posts every accrual from earlier months. Both steps are idempotent, so
+// the job can run as often as wanted. An account that fails is logged and
+// left for the next run.
+type InterestAccrualJob struct {
+	store db.Store
+	now   func() time.Time
+}
+
+func NewInterestAccrualJob(store db.Store) *InterestAccrualJob {
+	return &InterestAccrualJob{
+		store: store,
+		now:   time.Now,
+	}
+}
+
+func (j *InterestAccrualJob) Name() string {
+	return "interest_accrual"
+}
+
+func (j *InterestAccrualJob) Run(ctx context.Context) error {
+	today := j.now().UTC().Truncate(24 * time.Hour)
+
+	if err := runDaily(ctx, j.store, j.Name(), today, j.accrue); err != nil {
+		return err
+	}
+
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return j.post(ctx, monthStart)
+}
+
+func (j *InterestAccrualJob) accrue(ctx context.Context, date time.Time) (int, error) {
+	var afterID int64
+	var accrued, skipped int
+
+	for {
+		accounts, err := j.store.GetAccountsByType(ctx, db.GetAccountsByTypeParams{
+			Type:    util.SavingsAccount,
+			AfterID: afterID,
+			Limit:   interestBatchSize,
+		})
+
+		if err != nil {
+			return skipped, err
+		}
+
+		for _, account := range accounts {
+			afterID = account.ID
+
+			accrual, err := j.store.AccrueInterestTx(ctx, db.AccrueInterestTxParams{
+				AccountID: account.ID,
+				Date:      date,
+			})
+
+			if err != nil {
+				util.Logger(ctx).Error("cannot accrue interest", "account_id", account.ID, "date", date.Format(time.DateOnly), "error", err)
+				skipped++
+				continue
+			}
+
+			if accrual != nil {
+				accrued++
+			}
+		}
+
+		if len(accounts) < interestBatchSize {
+			break
+		}
+	}
+
+	if accrued > 0 {
+		util.Logger(ctx).Info("accrued interest", "accounts", accrued, "date", date.Format(time.DateOnly))
+	}
+
+	return skipped, nil
+}
+
+func (j *InterestAccrualJob) post(ctx context.Context, before time.Time) error {
+	var afterID int64
+	var posted int
+
+	for {
+		accountIDs, err := j.store.GetUnpostedInterestAccountIDs(ctx, db.GetUnpostedInterestAccountIDsParams{
+			Before:  pgtype.Date{Time: before, Valid: true},
+			AfterID: afterID,
+			Limit:   interestBatchSize,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		for _, id := range accountIDs {
+			afterID = id
+
+			result, err := j.store.PostInterestTx(ctx, db.PostInterestTxParams{
+				AccountID: id,
+				Before:    before,
+			})
+
+			if err != nil {
+				util.Logger(ctx).Error("cannot post interest", "account_id", id, "error", err)
+				continue
+			}
+
+			if result.Entry != nil {
+				posted++
+			}
+		}
+
+		if len(accountIDs) < interestBatchSize {
+			break
+		}
+	}
+
+	if posted > 0 {
+		util.Logger(ctx).Info("posted interest", "accounts", posted, "before", before.Format(time.DateOnly))
+	}
+
+	return nil
+}
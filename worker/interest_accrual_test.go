@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterestAccrualJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	job := NewInterestAccrualJob(store)
+	job.now = func() time.Time {
+		return time.Date(2024, 4, 1, 0, 30, 0, 0, time.UTC)
+	}
+
+	lastDayOfMarch := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	expectWatermark(store, job.Name(), lastDayOfMarch.AddDate(0, 0, -1))
+
+	accrue := store.EXPECT().
+		GetAccountsByType(gomock.Any(), gomock.Eq(db.GetAccountsByTypeParams{
+			Type:  util.SavingsAccount,
+			Limit: interestBatchSize,
+		})).
+		Times(1).
+		Return([]db.Account{{ID: 4, Balance: 1000}}, nil)
+
+	store.EXPECT().
+		AccrueInterestTx(gomock.Any(), gomock.Eq(db.AccrueInterestTxParams{AccountID: 4, Date: lastDayOfMarch})).
+		Times(1).
+		After(accrue).
+		Return(&db.InterestAccrual{AccountID: 4}, nil)
+
+	expectSetWatermark(store, job.Name(), lastDayOfMarch)
+
+	post := store.EXPECT().
+		GetUnpostedInterestAccountIDs(gomock.Any(), gomock.Eq(db.GetUnpostedInterestAccountIDsParams{
+			Before: pgtype.Date{Time: monthStart, Valid: true},
+			Limit:  interestBatchSize,
+		})).
+		Times(1).
+		Return([]int64{4}, nil)
+
+	store.EXPECT().
+		PostInterestTx(gomock.Any(), gomock.Eq(db.PostInterestTxParams{AccountID: 4, Before: monthStart})).
+		Times(1).
+		After(post).
+		Return(&db.PostInterestTxResult{Amount: 3.1, Entry: &db.Entry{}}, nil)
+
+	require.NoError(t, job.Run(context.Background()))
+}
+
+func TestInterestAccrualJobCatchUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	job := NewInterestAccrualJob(store)
+	job.now = func() time.Time {
+		return time.Date(2024, 3, 20, 0, 30, 0, 0, time.UTC)
+	}
+
+	// The job last finished the 16th, so the 17th to the 19th are due.
+	expectWatermark(store, job.Name(), time.Date(2024, 3, 16, 0, 0, 0, 0, time.UTC))
+
+	store.EXPECT().
+		GetAccountsByType(gomock.Any(), gomock.Any()).
+		Times(3).
+		Return([]db.Account{{ID: 4}, {ID: 5}}, nil)
+
+	for day := 17; day <= 19; day++ {
+		date := time.Date(2024, 3, day, 0, 0, 0, 0, time.UTC)
+
+		// Account 4 failing on the 18th does not stop account 5 or the
+		// later dates.
+		var err error
+		if day == 18 {
+			err = errors.New("connection reset")
+		}
+
+		store.EXPECT().
+			AccrueInterestTx(gomock.Any(), gomock.Eq(db.AccrueInterestTxParams{AccountID: 4, Date: date})).
+			Times(1).
+			Return(nil, err)
+
+		store.EXPECT().
+			AccrueInterestTx(gomock.Any(), gomock.Eq(db.AccrueInterestTxParams{AccountID: 5, Date: date})).
+			Times(1).
+			Return(&db.InterestAccrual{AccountID: 5}, nil)
+	}
+
+	// Only the 17th is finished for every account; the next run retries
+	// from the 18th.
+	expectSetWatermark(store, job.Name(), time.Date(2024, 3, 17, 0, 0, 0, 0, time.UTC))
+
+	store.EXPECT().
+		GetUnpostedInterestAccountIDs(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, nil)
+
+	require.NoError(t, job.Run(context.Background()))
+}
+
+func TestDailyInterest(t *testing.T) {
+	require.Zero(t, db.DailyInterest(0, 3.65))
+	require.Zero(t, db.DailyInterest(-100, 3.65))
+	require.Zero(t, db.DailyInterest(100, 0))
+	// 1000 * 3.65% / 365 = 0.1 a day.
+	require.InDelta(t, 0.1, db.DailyInterest(1000, 3.65), 1e-9)
+}
@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const overdraftBatchSize = 100
+
+// OverdraftAccrualJob charges daily overdraft interest and fees on every
+// account that ended a UTC day with a negative balance, for each day
+// through the previous one, catching up on days it missed. It can run as
+// often as wanted: the store skips accounts already charged for a date.
+// An account that fails is logged and left for the next run.
+type OverdraftAccrualJob struct {
+	store      db.Store
+	annualRate float64
+	dailyFee   float64
+	now        func() time.Time
+}
+
+func NewOverdraftAccrualJob(store db.Store, annualRate, dailyFee float64) *OverdraftAccrualJob {
+	return &OverdraftAccrualJob{
+		store:      store,
+		annualRate: annualRate,
+		dailyFee:   dailyFee,
+		now:        time.Now,
+	}
+}
+
+func (j *OverdraftAccrualJob) Name() string {
+	return "overdraft_accrual"
+}
+
+func (j *OverdraftAccrualJob) Run(ctx context.Context) error {
+	today := j.now().UTC().Truncate(24 * time.Hour)
+	return runDaily(ctx, j.store, j.Name(), today, j.accrue)
+}
+
+func (j *OverdraftAccrualJob) accrue(ctx context.Context, date time.Time) (int, error) {
+	var afterID int64
+	var charged, skipped int
+
+	for {
+		accounts, err := j.store.GetOverdrawnAccounts(ctx, db.GetOverdrawnAccountsParams{
+			At:      pgtype.Timestamptz{Time: date.AddDate(0, 0, 1), Valid: true},
+			AfterID: afterID,
+			Limit:   overdraftBatchSize,
+		})
+
+		if err != nil {
+			return skipped, err
+		}
+
+		for _, account := range accounts {
+			afterID = account.ID
+
+			accrual, err := j.store.AccrueOverdraftTx(ctx, db.AccrueOverdraftTxParams{
+				AccountID:  account.ID,
+				Date:       date,
+				AnnualRate: j.annualRate,
+				DailyFee:   j.dailyFee,
+			})
+
+			if err != nil {
+				util.Logger(ctx).Error("cannot accrue overdraft charges", "account_id", account.ID, "date", date.Format(time.DateOnly), "error", err)
+				skipped++
+				continue
+			}
+
+			if accrual != nil {
+				charged++
+			}
+		}
+
+		if len(accounts) < overdraftBatchSize {
+			break
+		}
+	}
+
+	if charged > 0 {
+		util.Logger(ctx).Info("accrued overdraft charges", "accounts", charged, "date", date.Format(time.DateOnly))
+	}
+
+	return skipped, nil
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverdraftAccrualJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	job := NewOverdraftAccrualJob(store, 18.25, 0.5)
+	job.now = func() time.Time {
+		return time.Date(2024, 3, 10, 0, 30, 0, 0, time.UTC)
+	}
+
+	yesterday := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	expectWatermark(store, job.Name(), yesterday.AddDate(0, 0, -1))
+
+	store.EXPECT().
+		GetOverdrawnAccounts(gomock.Any(), gomock.Eq(db.GetOverdrawnAccountsParams{
+			At:    pgtype.Timestamptz{Time: yesterday.AddDate(0, 0, 1), Valid: true},
+			Limit: overdraftBatchSize,
+		})).
+		Times(1).
+		Return([]db.Account{{ID: 3, Balance: -100}, {ID: 7, Balance: -50}}, nil)
+
+	for _, id := range []int64{3, 7} {
+		store.EXPECT().
+			AccrueOverdraftTx(gomock.Any(), gomock.Eq(db.AccrueOverdraftTxParams{
+				AccountID:  id,
+				Date:       yesterday,
+				AnnualRate: 18.25,
+				DailyFee:   0.5,
+			})).
+			Times(1).
+			Return(&db.OverdraftAccrual{AccountID: id}, nil)
+	}
+
+	expectSetWatermark(store, job.Name(), yesterday)
+
+	require.NoError(t, job.Run(context.Background()))
+}
+
+func TestOverdraftAccrualJobCatchUp(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	job := NewOverdraftAccrualJob(store, 18.25, 0.5)
+	job.now = func() time.Time {
+		return time.Date(2024, 3, 10, 0, 30, 0, 0, time.UTC)
+	}
+
+	// The job last finished the 6th, so the 7th to the 9th are due.
+	expectWatermark(store, job.Name(), time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC))
+
+	for day := 7; day <= 9; day++ {
+		date := time.Date(2024, 3, day, 0, 0, 0, 0, time.UTC)
+
+		// Each date charges the accounts overdrawn at its own end.
+		store.EXPECT().
+			GetOverdrawnAccounts(gomock.Any(), gomock.Eq(db.GetOverdrawnAccountsParams{
+				At:    pgtype.Timestamptz{Time: date.AddDate(0, 0, 1), Valid: true},
+				Limit: overdraftBatchSize,
+			})).
+			Times(1).
+			Return([]db.Account{{ID: 3}, {ID: 7}}, nil)
+
+		// Account 3 failing on the 8th does not stop account 7 or the
+		// later dates.
+		var err error
+		if day == 8 {
+			err = errors.New("connection reset")
+		}
+
+		store.EXPECT().
+			AccrueOverdraftTx(gomock.Any(), gomock.Eq(db.AccrueOverdraftTxParams{
+				AccountID:  3,
+				Date:       date,
+				AnnualRate: 18.25,
+				DailyFee:   0.5,
+			})).
+			Times(1).
+			Return(nil, err)
+
+		store.EXPECT().
+			AccrueOverdraftTx(gomock.Any(), gomock.Eq(db.AccrueOverdraftTxParams{
+				AccountID:  7,
+				Date:       date,
+				AnnualRate: 18.25,
+				DailyFee:   0.5,
+			})).
+			Times(1).
+			Return(&db.OverdraftAccrual{AccountID: 7}, nil)
+	}
+
+	// Only the 7th is finished for every account; the next run retries
+	// from the 8th.
+	expectSetWatermark(store, job.Name(), time.Date(2024, 3, 7, 0, 0, 0, 0, time.UTC))
+
+	require.NoError(t, job.Run(context.Background()))
+}
+
+func TestOverdraftCharge(t *testing.T) {
+	require.Zero(t, db.OverdraftCharge(10, 18.25, 0.5))
+	require.Zero(t, db.OverdraftCharge(0, 18.25, 0.5))
+	// 1000 * 18.25% / 365 = 0.5 a day, plus the flat daily fee.
+	require.Equal(t, 1.0, db.OverdraftCharge(-1000, 18.25, 0.5))
+}
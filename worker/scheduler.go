@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/devphasex/cedar-bank-api/util"
+)
+
+// Job is a unit of background work run periodically by the Scheduler.
+type Job interface {
+	Name() string
+	Run(ctx context.Context) error
+}
+
+type scheduledJob struct {
+	job      Job
+	interval time.Duration
+}
+
+// Scheduler runs each registered job on its own ticker until the
+// context passed to Start is cancelled.
+type Scheduler struct {
+	jobs       []scheduledJob
+	wg         sync.WaitGroup
+	cancelRuns context.CancelFunc
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Every registers job to run once per interval. Jobs must be registered
+// before Start is called.
+func (s *Scheduler) Every(interval time.Duration, job Job) {
+	s.jobs = append(s.jobs, scheduledJob{job: job, interval: interval})
+}
+
+// Start runs the job loops until ctx is cancelled. Runs get a context of
+// their own that outlives ctx, so a shutdown does not abort a job halfway
+// through its queries unless Wait gives up on it.
+func (s *Scheduler) Start(ctx context.Context) {
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	s.cancelRuns = cancel
+
+	for _, sj := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, runCtx, sj)
+	}
+}
+
+// Wait blocks until every job loop has returned, letting a run in
+// progress finish. If ctx is done first, the runs still going are
+// cancelled and Wait returns ctx.Err() once they have returned.
+func (s *Scheduler) Wait(ctx context.Context) error {
+	if s.cancelRuns == nil {
+		return nil
+	}
+	defer s.cancelRuns()
+
+	stopped := make(chan struct{})
+
+	go func() {
+		s.wg.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.cancelRuns()
+		<-stopped
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) loop(ctx, runCtx context.Context, sj scheduledJob) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(sj.interval)
+	defer ticker.Stop()
+
+	logger := slog.Default().With("job", sj.job.Name())
+	runCtx = util.WithLogger(runCtx, logger)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// A tick that came in while the last run was going on must not
+			// start another once the context is cancelled.
+			if ctx.Err() != nil {
+				return
+			}
+
+			if err := sj.job.Run(runCtx); err != nil {
+				logger.Error("job failed", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type countingJob struct {
+	runs atomic.Int32
+	err  error
+}
+
+func (j *countingJob) Name() string {
+	return "counting"
+}
+
+func (j *countingJob) Run(ctx context.Context) error {
+	j.runs.Add(1)
+	return j.err
+}
+
+func TestSchedulerRunsJobsUntilCancelled(t *testing.T) {
+	ok := &countingJob{}
+	failing := &countingJob{err: errors.New("boom")}
+
+	scheduler := NewScheduler()
+	scheduler.Every(5*time.Millisecond, ok)
+	scheduler.Every(5*time.Millisecond, failing)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Start(ctx)
+
+	require.Eventually(t, func() bool {
+		return ok.runs.Load() >= 2 && failing.runs.Load() >= 2
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.NoError(t, scheduler.Wait(context.Background()))
+
+	runs := ok.runs.Load()
+	time.Sleep(20 * time.Millisecond)
+	require.Equal(t, runs, ok.runs.Load())
+}
+
+type blockingJob struct {
+	started  chan struct{}
+	release  chan struct{}
+	finished atomic.Bool
+}
+
+func (j *blockingJob) Name() string {
+	return "blocking"
+}
+
+func (j *blockingJob) Run(ctx context.Context) error {
+	close(j.started)
+	<-j.release
+
+	if ctx.Err() == nil {
+		j.finished.Store(true)
+	}
+
+	return nil
+}
+
+func TestSchedulerWaitsForRunInProgress(t *testing.T) {
+	job := &blockingJob{started: make(chan struct{}), release: make(chan struct{})}
+
+	scheduler := NewScheduler()
+	scheduler.Every(5*time.Millisecond, job)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Start(ctx)
+
+	<-job.started
+	cancel()
+
+	waited := make(chan struct{})
+
+	go func() {
+		require.NoError(t, scheduler.Wait(context.Background()))
+		close(waited)
+	}()
+
+	select {
+	case <-waited:
+		t.Fatal("Wait returned before the run finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(job.release)
+	<-waited
+
+	require.True(t, job.finished.Load())
+}
+
+type stuckJob struct {
+	started chan struct{}
+	err     error
+}
+
+func (j *stuckJob) Name() string {
+	return "stuck"
+}
+
+func (j *stuckJob) Run(ctx context.Context) error {
+	close(j.started)
+	<-ctx.Done()
+	j.err = ctx.Err()
+
+	return j.err
+}
+
+func TestSchedulerWaitCancelsRunPastDeadline(t *testing.T) {
+	job := &stuckJob{started: make(chan struct{})}
+
+	scheduler := NewScheduler()
+	scheduler.Every(5*time.Millisecond, job)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scheduler.Start(ctx)
+
+	<-job.started
+	cancel()
+
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer waitCancel()
+
+	require.ErrorIs(t, scheduler.Wait(waitCtx), context.DeadlineExceeded)
+	require.ErrorIs(t, job.err, context.Canceled)
+}
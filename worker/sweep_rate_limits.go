@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	db "github.com/devphasex/cedar-bank-api/db/sqlc"
+	"github.com/devphasex/cedar-bank-api/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// rateLimitIdle is how long a bucket goes untouched before it is
+// dropped. It must outlast the slowest refill of any configured limit.
+const rateLimitIdle = 24 * time.Hour
+
+// SweepRateLimitsJob deletes the postgres rate limit buckets of clients
+// that have gone quiet. A deleted bucket starts over full, as it would
+// have refilled by then anyway.
+type SweepRateLimitsJob struct {
+	store db.Store
+	now   func() time.Time
+}
+
+func NewSweepRateLimitsJob(store db.Store) *SweepRateLimitsJob {
+	return &SweepRateLimitsJob{store: store, now: time.Now}
+}
+
+func (j *SweepRateLimitsJob) Name() string {
+	return "sweep_rate_limits"
+}
+
+func (j *SweepRateLimitsJob) Run(ctx context.Context) error {
+	deleted, err := j.store.DeleteStaleRateLimitBuckets(ctx, pgtype.Timestamptz{
+		Time:  j.now().Add(-rateLimitIdle),
+		Valid: true,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if deleted > 0 {
+		util.Logger(ctx).Info("deleted idle rate limit buckets", "count", deleted)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	mockdb "github.com/devphasex/cedar-bank-api/db/mock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSweepRateLimitsJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	job := NewSweepRateLimitsJob(store)
+	job.now = func() time.Time { return now }
+
+	store.EXPECT().
+		DeleteStaleRateLimitBuckets(gomock.Any(), gomock.Eq(pgtype.Timestamptz{Time: now.Add(-rateLimitIdle), Valid: true})).
+		Times(1).
+		Return(int64(3), nil)
+
+	require.NoError(t, job.Run(context.Background()))
+}